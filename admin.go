@@ -0,0 +1,129 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LoggerLevels describes the level of a live Logger and its descendants, as
+// served by LevelsHandler()
+type LoggerLevels struct {
+	// Label is the label of the Logger
+	Label string `json:"label"`
+	// Level is the effective level of the Logger
+	Level string `json:"level"`
+	// Configured is the level set for the Logger, if any. Without one, it
+	// inherits the level of its parent.
+	Configured string `json:"configured,omitempty"`
+	// Expires is when a temporary level set with SetLevelFor() reverts
+	Expires *time.Time `json:"expires,omitempty"`
+	// Loggers are the child loggers that have been created, by name
+	Loggers map[string]*LoggerLevels `json:"loggers,omitempty"`
+}
+
+// LevelsHandler returns an http.Handler for inspecting and changing the levels of
+// a live logger tree, similar to the loggers endpoint of Spring Boot Actuator.
+// Paths below the handler name a logger, so mount it with http.StripPrefix:
+//
+//	http.Handle("/loglevels/", http.StripPrefix("/loglevels", logs.LevelsHandler(logger)))
+//
+// GET /loglevels returns the tree of loggers and their levels as JSON, and GET
+// /loglevels/db.cache the subtree of a logger. PUT /loglevels/db.cache sets the
+// level of a logger:
+//
+//	{"level": "DEBUG", "expires": "15m"}
+//
+// expires, a Go duration, is optional; when set, the level reverts after it. A
+// null level makes the logger inherit its parent's level again. The handler can
+// change what is logged, so serve it only to operators.
+func LevelsHandler(logger *Logger) http.Handler {
+	return &levelsHandler{logger: logger}
+}
+
+type levelsHandler struct {
+	logger *Logger
+}
+
+func (h *levelsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := h.logger
+	if name := strings.Trim(r.URL.Path, "/"); len(name) > 0 {
+		target = h.logger.ChildLogger(name)
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut:
+		var body struct {
+			Level   *LogLevel `json:"level"`
+			Expires string    `json:"expires"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %s", err), http.StatusBadRequest)
+			return
+		}
+		level := NotSet
+		if nil != body.Level {
+			level = *body.Level
+		}
+		var expires time.Duration
+		if len(body.Expires) > 0 {
+			d, err := time.ParseDuration(body.Expires)
+			if err != nil || d <= 0 {
+				http.Error(w, fmt.Sprintf("Invalid expires %q. Use a duration, such as \"15m\"", body.Expires), http.StatusBadRequest)
+				return
+			}
+			expires = d
+		}
+		if level == NotSet && nil == target.parent {
+			http.Error(w, "The root logger requires a level", http.StatusBadRequest)
+			return
+		}
+		target.SetLevelFor(level, expires)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(target.Levels())
+}
+
+// Levels describes the levels of the Logger and of the descendants that have been
+// created from it
+func (logger *Logger) Levels() *LoggerLevels {
+	if nil != logger.base {
+		return logger.base.Levels()
+	}
+	childlock.Lock()
+	defer childlock.Unlock()
+	return logger.describeLevels()
+}
+
+// describeLevels describes a logger and its descendants. childlock must be held.
+func (logger *Logger) describeLevels() *LoggerLevels {
+	levels := &LoggerLevels{
+		Label: logger.label,
+		Level: LogLevels.Label(logger.Level()),
+	}
+	if logger.logConfig.Level != NotSet {
+		levels.Configured = LogLevels.Label(logger.logConfig.Level)
+	}
+	if nil != logger.temporary {
+		expires := logger.temporary.expires
+		levels.Expires = &expires
+	}
+
+	for name, child := range logger.children {
+		if nil == levels.Loggers {
+			levels.Loggers = make(map[string]*LoggerLevels, len(logger.children))
+		}
+		levels.Loggers[name] = child.describeLevels()
+	}
+	return levels
+}
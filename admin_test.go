@@ -0,0 +1,91 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// adminRequest sends a request to a LevelsHandler mounted at /loglevels
+func adminRequest(test *testing.T, handler http.Handler, method string, path string, body string) (*httptest.ResponseRecorder, *logs.LoggerLevels) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	http.StripPrefix("/loglevels", handler).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return rec, nil
+	}
+	var levels logs.LoggerLevels
+	if err := json.Unmarshal(rec.Body.Bytes(), &levels); err != nil {
+		test.Fatalf("Invalid response: %s\n%s", err, rec.Body.String())
+	}
+	return rec, &levels
+}
+
+func TestLevelsHandler(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "app",
+		LogHandler: func(logs.LogMessage) {},
+		Loggers:    map[string]*logs.LogConfig{"db": {Level: logs.Warn}},
+	})
+	cache := logger.ChildLogger("db").ChildLogger("cache")
+	logger.ChildLogger("http")
+	handler := logs.LevelsHandler(logger)
+
+	_, levels := adminRequest(test, handler, http.MethodGet, "/loglevels", "")
+	if nil == levels || levels.Level != "INFO" || levels.Loggers["db"].Configured != "WARN" || levels.Loggers["db"].Loggers["cache"].Level != "WARN" || levels.Loggers["http"].Configured != "" {
+		test.Fatalf("Unexpected tree: %+v", levels)
+	}
+
+	_, levels = adminRequest(test, handler, http.MethodPut, "/loglevels/db.cache", `{"level": "TRACE"}`)
+	if nil == levels || levels.Label != "app.db.cache" || levels.Level != "TRACE" || cache.Level() != logs.Trace {
+		test.Errorf("Expected db.cache to be at TRACE. Found: %+v", levels)
+	}
+	_, levels = adminRequest(test, handler, http.MethodPut, "/loglevels/db", `{"level": "ERROR"}`)
+	if cache.Level() != logs.Trace || nil == levels || levels.Loggers["cache"].Level != "TRACE" {
+		test.Errorf("Expected db.cache to keep its own level. Found: %+v", levels)
+	}
+	adminRequest(test, handler, http.MethodPut, "/loglevels/db.cache", `{"level": null}`)
+	if cache.Level() != logs.Error {
+		test.Errorf("Expected db.cache to inherit the ERROR level. Found: %s", logs.LogLevels.Label(cache.Level()))
+	}
+
+	for _, body := range []string{`{"level": "LOUD"}`, `{"level": "DEBUG", "expires": "soon"}`, `{`} {
+		if rec, _ := adminRequest(test, handler, http.MethodPut, "/loglevels/db", body); rec.Code != http.StatusBadRequest {
+			test.Errorf("Expected a 400 for %s. Found: %d", body, rec.Code)
+		}
+	}
+	if rec, _ := adminRequest(test, handler, http.MethodPut, "/loglevels", `{"level": null}`); rec.Code != http.StatusBadRequest {
+		test.Errorf("Expected a 400 for resetting the root level. Found: %d", rec.Code)
+	}
+	if rec, _ := adminRequest(test, handler, http.MethodDelete, "/loglevels/db", ""); rec.Code != http.StatusMethodNotAllowed {
+		test.Errorf("Expected a 405 for DELETE. Found: %d", rec.Code)
+	}
+}
+
+func TestLevelsHandlerExpiry(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	payments := logger.ChildLogger("main.payments")
+	handler := logs.LevelsHandler(logger)
+
+	_, levels := adminRequest(test, handler, http.MethodPut, "/loglevels/main.payments", `{"level": "DEBUG", "expires": "1h"}`)
+	if nil == levels || nil == levels.Expires {
+		test.Fatalf("Expected an expiry. Found: %+v", levels)
+	}
+	_, levels = adminRequest(test, handler, http.MethodPut, "/loglevels/main.payments", `{"level": "TRACE", "expires": "50ms"}`)
+	if nil == levels || payments.Level() != logs.Trace {
+		test.Fatalf("Expected the TRACE level. Found: %s", logs.LogLevels.Label(payments.Level()))
+	}
+
+	waitForLevel(payments, logs.Info)
+	if payments.Level() != logs.Info {
+		test.Errorf("Expected the level to revert to INFO. Found: %s", logs.LogLevels.Label(payments.Level()))
+	}
+	_, levels = adminRequest(test, handler, http.MethodGet, "/loglevels/main.payments", "")
+	if nil == levels || nil != levels.Expires || levels.Configured != "" {
+		test.Errorf("Expected the temporary level to be gone. Found: %+v", levels)
+	}
+}
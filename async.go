@@ -0,0 +1,150 @@
+package gologsgo
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what an AsyncHandler does with an entry when its queue
+// is full
+type OverflowPolicy int32
+
+const (
+	// DropNewest discards the entry being logged
+	DropNewest OverflowPolicy = iota
+	// DropOldest discards the oldest queued entry to make room
+	DropOldest
+	// Block waits for room in the queue, applying backpressure to the caller
+	Block
+)
+
+// AsyncHandler decouples logging from a slow LogHandler by queueing entries on a
+// channel that is drained by a pool of workers
+type AsyncHandler struct {
+	// dropped is first to guarantee 64-bit alignment for atomic operations
+	dropped uint64
+	policy  int32
+	handler LogHandler
+	queue   chan LogMessage
+	mu      sync.RWMutex
+	closed  bool
+	workers sync.WaitGroup
+	// pending counts entries that have been queued but not yet handled
+	pending     int
+	pendinglock sync.Mutex
+	idle        *sync.Cond
+}
+
+// Async wraps handler in an AsyncHandler with a queue of queueSize entries and
+// the given number of workers. Entries are dropped with the DropNewest policy
+// when the queue is full; use SetOverflowPolicy() to change this. Note that with
+// more than one worker, entries may be handled out of order.
+func Async(handler LogHandler, queueSize int, workers int) *AsyncHandler {
+	if queueSize < 1 {
+		queueSize = 1000
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	a := &AsyncHandler{
+		handler: handler,
+		queue:   make(chan LogMessage, queueSize),
+	}
+	a.idle = sync.NewCond(&a.pendinglock)
+	a.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go a.run()
+	}
+	return a
+}
+
+// SetOverflowPolicy sets what happens to entries logged while the queue is full
+func (a *AsyncHandler) SetOverflowPolicy(policy OverflowPolicy) {
+	atomic.StoreInt32(&a.policy, int32(policy))
+}
+
+// LogHandler queues a LogMessage for the wrapped handler. It is a LogHandler.
+func (a *AsyncHandler) LogHandler(msg LogMessage) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		drop(&a.dropped, 1)
+		return
+	}
+
+	a.addPending(1)
+	switch OverflowPolicy(atomic.LoadInt32(&a.policy)) {
+	case Block:
+		a.queue <- msg
+	case DropOldest:
+		for {
+			select {
+			case a.queue <- msg:
+				return
+			default:
+				select {
+				case <-a.queue:
+					drop(&a.dropped, 1)
+					a.addPending(-1)
+				default:
+				}
+			}
+		}
+	default:
+		select {
+		case a.queue <- msg:
+		default:
+			drop(&a.dropped, 1)
+			a.addPending(-1)
+		}
+	}
+}
+
+// Flush waits until every entry queued so far has been handed to the wrapped
+// handler. It is part of FlushCloser.
+func (a *AsyncHandler) Flush() error {
+	a.pendinglock.Lock()
+	defer a.pendinglock.Unlock()
+	for a.pending > 0 {
+		a.idle.Wait()
+	}
+	return nil
+}
+
+func (a *AsyncHandler) addPending(delta int) {
+	a.pendinglock.Lock()
+	defer a.pendinglock.Unlock()
+	a.pending += delta
+	if a.pending < 1 {
+		a.idle.Broadcast()
+	}
+}
+
+// Dropped returns the number of entries discarded because the queue was full or
+// they were logged after Close()
+func (a *AsyncHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Close stops accepting entries and waits for the workers to hand every queued
+// entry to the wrapped handler. It is safe to call more than once.
+func (a *AsyncHandler) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	a.workers.Wait()
+	return nil
+}
+
+func (a *AsyncHandler) run() {
+	defer a.workers.Done()
+	for msg := range a.queue {
+		a.handler(msg)
+		a.addPending(-1)
+	}
+}
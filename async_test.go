@@ -0,0 +1,111 @@
+package gologsgo_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestAsyncDrainsOnClose(test *testing.T) {
+	var mu sync.Mutex
+	received := 0
+	async := logs.Async(func(logs.LogMessage) {
+		mu.Lock()
+		received++
+		mu.Unlock()
+	}, 100, 4)
+
+	for i := 0; i < 50; i++ {
+		async.LogHandler(logs.LogMessage{Message: "hello"})
+	}
+	if err := async.Close(); err != nil {
+		test.Fatalf("Unexpected error from Close(): %v", err)
+	}
+
+	if received != 50 {
+		test.Errorf("Expected all 50 entries to be handled before Close() returned. Found: %d", received)
+	}
+
+	async.LogHandler(logs.LogMessage{Message: "late"})
+	if async.Dropped() != 1 {
+		test.Errorf("Expected entries logged after Close() to be dropped. Found: %d", async.Dropped())
+	}
+}
+
+func TestAsyncOverflowPolicies(test *testing.T) {
+	for _, tc := range []struct {
+		policy  logs.OverflowPolicy
+		first   string
+		dropped uint64
+	}{
+		{logs.DropNewest, "1", 1},
+		{logs.DropOldest, "2", 1},
+		{logs.Block, "", 0},
+	} {
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+		var mu sync.Mutex
+		var messages []string
+		async := logs.Async(func(msg logs.LogMessage) {
+			select {
+			case started <- struct{}{}:
+				<-release
+			default:
+			}
+			mu.Lock()
+			messages = append(messages, msg.Message)
+			mu.Unlock()
+		}, 2, 1)
+		async.SetOverflowPolicy(tc.policy)
+
+		// The worker holds "0" until released, filling the queue with "1" and "2"
+		async.LogHandler(logs.LogMessage{Message: "0"})
+		<-started
+		async.LogHandler(logs.LogMessage{Message: "1"})
+		async.LogHandler(logs.LogMessage{Message: "2"})
+		if tc.policy == logs.Block {
+			go func() { close(release) }()
+		}
+		async.LogHandler(logs.LogMessage{Message: "3"})
+		if tc.policy != logs.Block {
+			close(release)
+		}
+		async.Close()
+
+		if async.Dropped() != tc.dropped {
+			test.Errorf("Policy %d: expected %d dropped. Found: %d", tc.policy, tc.dropped, async.Dropped())
+		}
+		if tc.policy == logs.Block {
+			if len(messages) != 4 {
+				test.Errorf("Expected Block to deliver every entry. Found: %v", messages)
+			}
+		} else if len(messages) != 3 || messages[1] != tc.first {
+			test.Errorf("Policy %d: expected %q after \"0\". Found: %v", tc.policy, tc.first, messages)
+		}
+	}
+}
+
+func TestAsyncFlush(test *testing.T) {
+	var mu sync.Mutex
+	received := 0
+	async := logs.Async(func(logs.LogMessage) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		received++
+		mu.Unlock()
+	}, 100, 2)
+	defer async.Close()
+
+	for i := 0; i < 20; i++ {
+		async.LogHandler(logs.LogMessage{Message: "hello"})
+	}
+	async.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received != 20 {
+		test.Errorf("Expected Flush() to wait for all 20 entries. Found: %d", received)
+	}
+}
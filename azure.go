@@ -0,0 +1,78 @@
+package gologsgo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// azureLogType is the pattern Log Analytics requires of custom log type names
+var azureLogType = regexp.MustCompile(`^[A-Za-z0-9_]{1,100}$`)
+
+// AzureLogAnalyticsConfig configures an Azure Monitor Log Analytics handler
+type AzureLogAnalyticsConfig struct {
+	// HTTPHandlerConfig configures batching and retries. URL defaults to the
+	// Data Collector API endpoint for the workspace. Gzip is not supported by
+	// the API and is ignored. Encoder must produce JSON and defaults to
+	// JSONEncoder.
+	HTTPHandlerConfig
+	// WorkspaceID is the Log Analytics workspace ID
+	WorkspaceID string
+	// SharedKey is the workspace's primary or secondary key, base64 encoded
+	SharedKey string
+	// LogType names the custom log table entries are written to. Log Analytics
+	// adds a _CL suffix.
+	LogType string
+}
+
+// NewAzureLogAnalyticsHandler returns an HTTPHandler that posts entries to an
+// Azure Log Analytics workspace with the HTTP Data Collector API, signing each
+// request with the workspace's shared key.
+func NewAzureLogAnalyticsHandler(config AzureLogAnalyticsConfig) (*HTTPHandler, error) {
+	if len(config.WorkspaceID) == 0 {
+		return nil, fmt.Errorf("Azure Log Analytics handler requires a WorkspaceID")
+	}
+	key, err := base64.StdEncoding.DecodeString(config.SharedKey)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("Azure Log Analytics SharedKey must be base64 encoded")
+	}
+	if !azureLogType.MatchString(config.LogType) {
+		return nil, fmt.Errorf("Azure Log Analytics LogType must be 1 to 100 letters, numbers or underscores. Found: %q", config.LogType)
+	}
+
+	if len(config.URL) == 0 {
+		config.URL = fmt.Sprintf("https://%s.ods.opinsights.azure.com/api/logs?api-version=2016-04-01", config.WorkspaceID)
+	}
+	config.Gzip = false
+	config.Headers = cloneHeader(config.Headers)
+	config.Headers.Set("Log-Type", config.LogType)
+	// JSONEncoder writes each entry's time in the "time" field
+	config.Headers.Set("time-generated-field", "time")
+
+	h, err := NewHTTPHandler(config.HTTPHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+	workspaceID := config.WorkspaceID
+	h.prepare = func(req *http.Request, body []byte) error {
+		date := time.Now().UTC().Format(http.TimeFormat)
+		req.Header.Set("x-ms-date", date)
+		req.Header.Set("Authorization", azureSharedKey(workspaceID, key, len(body), date))
+		return nil
+	}
+	return h, nil
+}
+
+// azureSharedKey builds the SharedKey Authorization header for a Data Collector
+// API request
+func azureSharedKey(workspaceID string, key []byte, contentLength int, date string) string {
+	stringToSign := fmt.Sprintf("POST\n%d\napplication/json\nx-ms-date:%s\n/api/logs", contentLength, date)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf("SharedKey %s:%s", workspaceID, signature)
+}
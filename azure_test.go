@@ -0,0 +1,60 @@
+package gologsgo_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestAzureLogAnalyticsHandler(test *testing.T) {
+	key := []byte("workspace-key")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		stringToSign := fmt.Sprintf("POST\n%d\napplication/json\nx-ms-date:%s\n/api/logs", len(body), r.Header.Get("x-ms-date"))
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(stringToSign))
+		expected := "SharedKey workspace:" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		if r.Header.Get("Authorization") != expected {
+			test.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Log-Type") != "AppLogs" || r.Header.Get("time-generated-field") != "time" {
+			test.Errorf("Unexpected headers: %v", r.Header)
+		}
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewAzureLogAnalyticsHandler(logs.AzureLogAnalyticsConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{URL: server.URL + "/api/logs?api-version=2016-04-01"},
+		WorkspaceID:       "workspace",
+		SharedKey:         base64.StdEncoding.EncodeToString(key),
+		LogType:           "AppLogs",
+	})
+	if nil != err {
+		test.Fatalf("Error creating Azure Log Analytics handler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{LogHandler: handler.LogHandler})
+	logger.Info("An info log message")
+	handler.Close()
+
+	if requests != 1 {
+		test.Errorf("Expected 1 request. Found: %d", requests)
+	}
+
+	if _, err := logs.NewAzureLogAnalyticsHandler(logs.AzureLogAnalyticsConfig{
+		WorkspaceID: "workspace",
+		SharedKey:   base64.StdEncoding.EncodeToString(key),
+		LogType:     "not valid",
+	}); nil == err {
+		test.Error("Expected an error for an invalid LogType")
+	}
+}
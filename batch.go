@@ -0,0 +1,156 @@
+package gologsgo
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchFunc handles a batch of entries
+type BatchFunc func(batch []LogMessage)
+
+// Batcher groups entries into batches for a BatchFunc, so that handlers for
+// network and cloud sinks can share one batching implementation. Entries are
+// queued and batched by a background goroutine. A batch is passed on when it
+// reaches maxCount entries, when maxAge elapses, and by Flush() and Close().
+type Batcher struct {
+	// dropped is first to guarantee 64-bit alignment for atomic operations
+	dropped  uint64
+	handler  BatchFunc
+	maxCount int
+	maxAge   time.Duration
+	queue    chan LogMessage
+	flush    chan chan struct{}
+	done     chan struct{}
+	mu       sync.RWMutex
+	closed   bool
+}
+
+// Batch wraps handler in a Batcher. A maxCount below 1 defaults to 100 and a
+// maxAge of 0 defaults to 1 second. Up to 100 batches worth of entries are
+// queued; when the queue is full, new entries are dropped.
+func Batch(handler BatchFunc, maxCount int, maxAge time.Duration) *Batcher {
+	if maxCount < 1 {
+		maxCount = 100
+	}
+	return newBatcher(handler, maxCount, maxAge, maxCount*100)
+}
+
+func newBatcher(handler BatchFunc, maxCount int, maxAge time.Duration, queueSize int) *Batcher {
+	if maxAge <= 0 {
+		maxAge = time.Second
+	}
+	b := &Batcher{
+		handler:  handler,
+		maxCount: maxCount,
+		maxAge:   maxAge,
+		queue:    make(chan LogMessage, queueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// LogHandler queues a LogMessage for the next batch. It is a LogHandler.
+func (b *Batcher) LogHandler(msg LogMessage) {
+	if nil != b.enqueue(msg) {
+		drop(&b.dropped, 1)
+	}
+}
+
+// enqueue queues a LogMessage for the next batch, returning an error if the
+// Batcher is closed or the queue is full
+func (b *Batcher) enqueue(msg LogMessage) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return fmt.Errorf("Batcher is closed")
+	}
+
+	select {
+	case b.queue <- msg:
+		return nil
+	default:
+		return fmt.Errorf("Batch queue is full")
+	}
+}
+
+// Dropped returns the number of entries that were discarded because the queue was
+// full or they were logged after Close()
+func (b *Batcher) Dropped() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+// Flush passes on everything queued so far and waits for the BatchFunc to return.
+// It is part of FlushCloser.
+func (b *Batcher) Flush() error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		<-b.done
+		return nil
+	}
+	flushed := make(chan struct{})
+	b.flush <- flushed
+	b.mu.RUnlock()
+
+	<-flushed
+	return nil
+}
+
+// Close stops accepting entries, passes on everything still queued and waits for
+// the BatchFunc to return. It is part of FlushCloser.
+func (b *Batcher) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	<-b.done
+	return nil
+}
+
+// run is the background goroutine that batches queued entries
+func (b *Batcher) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.maxAge)
+	defer ticker.Stop()
+
+	batch := make([]LogMessage, 0, b.maxCount)
+	send := func() {
+		if len(batch) > 0 {
+			b.handler(batch)
+			batch = make([]LogMessage, 0, b.maxCount)
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-b.queue:
+			if !ok {
+				send()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= b.maxCount {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-b.flush:
+			for queued := len(b.queue); queued > 0; queued-- {
+				batch = append(batch, <-b.queue)
+				if len(batch) >= b.maxCount {
+					send()
+				}
+			}
+			send()
+			close(flushed)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package gologsgo_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestBatch(test *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	batcher := logs.Batch(func(batch []logs.LogMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(batch))
+	}, 3, time.Hour)
+
+	for i := 0; i < 7; i++ {
+		batcher.LogHandler(logs.LogMessage{Message: "entry"})
+	}
+	batcher.Flush()
+
+	mu.Lock()
+	if len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		test.Errorf("Expected batches of 3, 3 and a flushed 1. Found: %v", sizes)
+	}
+	mu.Unlock()
+
+	batcher.LogHandler(logs.LogMessage{Message: "last"})
+	batcher.Close()
+	batcher.LogHandler(logs.LogMessage{Message: "too late"})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sizes) != 4 || batcher.Dropped() != 1 {
+		test.Errorf("Expected Close() to send the last batch and drop later entries. Found: %v, %d dropped", sizes, batcher.Dropped())
+	}
+}
+
+func TestBatchMaxAge(test *testing.T) {
+	sent := make(chan int, 1)
+	batcher := logs.Batch(func(batch []logs.LogMessage) {
+		sent <- len(batch)
+	}, 100, 10*time.Millisecond)
+	defer batcher.Close()
+
+	batcher.LogHandler(logs.LogMessage{Message: "entry"})
+	select {
+	case n := <-sent:
+		if n != 1 {
+			test.Errorf("Expected a batch of 1. Found: %d", n)
+		}
+	case <-time.After(time.Second):
+		test.Errorf("Expected the batch to be sent after maxAge")
+	}
+}
@@ -0,0 +1,103 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// bootstrapBufferSize is the most records a bootstrap Logger holds for replay.
+// Once full, the oldest records are dropped.
+const bootstrapBufferSize = 1000
+
+// bootstrapState is shared by a bootstrap Logger and all of its children
+type bootstrapState struct {
+	mu       sync.Mutex
+	output   io.Writer
+	level    LogLevel
+	buffer   []LogMessage
+	promoted *Logger
+}
+
+// Bootstrap returns a minimal Logger that can be used before configuration has
+// been loaded. It writes INFO and above to stderr as plain text and buffers every
+// record (at every level) so they can be replayed in to the configured pipeline
+// with Promote().
+func Bootstrap() *Logger {
+	state := &bootstrapState{
+		output: os.Stderr,
+		level:  Info,
+	}
+	logger := New(&RootLogConfig{
+		Level:      All,
+		LogHandler: state.logHandler,
+	})
+	logger.bootstrap = state
+	return logger
+}
+
+func (state *bootstrapState) logHandler(msg LogMessage) {
+	state.mu.Lock()
+	promoted := state.promoted
+	if nil == promoted {
+		if len(state.buffer) >= bootstrapBufferSize {
+			state.buffer = state.buffer[1:]
+		}
+		state.buffer = append(state.buffer, msg)
+		if msg.Level >= state.level {
+			if data, err := TextEncoder(msg); nil == err {
+				fmt.Fprintln(state.output, string(data))
+			}
+		}
+	}
+	state.mu.Unlock()
+
+	if nil != promoted {
+		forward(promoted, msg)
+	}
+}
+
+// Promote replays the records buffered by a bootstrap Logger in to the configured
+// Logger's pipeline, filtered by the configured levels, and forwards everything
+// logged to the bootstrap Logger (or its children) from then on. A bootstrap
+// record's logger label is resolved to the configured child logger with the same
+// name, so early records are formatted consistently with the rest.
+func Promote(boot *Logger, configured *Logger) error {
+	if nil == boot.bootstrap {
+		return fmt.Errorf("Promote requires a Logger created by Bootstrap()")
+	}
+
+	state := boot.bootstrap
+	state.mu.Lock()
+	if nil != state.promoted {
+		state.mu.Unlock()
+		return fmt.Errorf("Bootstrap logger has already been promoted")
+	}
+	buffer := state.buffer
+	state.buffer = nil
+	state.promoted = configured
+	state.mu.Unlock()
+
+	for _, msg := range buffer {
+		forward(configured, msg)
+	}
+
+	return nil
+}
+
+// forward delivers a record logged to a bootstrap Logger to the equivalent logger
+// in the configured tree, if its level allows
+func forward(configured *Logger, msg LogMessage) {
+	target := configured
+	if len(msg.Logger) > 0 {
+		target = configured.ChildLogger(strings.TrimPrefix(msg.Logger, "."))
+	}
+	if msg.Level < target.Level() {
+		return
+	}
+
+	msg.Logger = target.Label()
+	target.logHandler(msg)
+}
@@ -0,0 +1,41 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestBootstrapPromote(test *testing.T) {
+	boot := logs.Bootstrap()
+	boot.ChildLogger("config").Debug("Reading config")
+	boot.ChildLogger("config").Warn("Config file not found, using defaults")
+
+	var messages []logs.LogMessage
+	configured := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		Level: logs.Info,
+		LogHandler: func(msg logs.LogMessage) {
+			messages = append(messages, msg)
+		},
+	})
+
+	if err := logs.Promote(boot, configured); nil != err {
+		test.Fatalf("Error promoting bootstrap logger: %s", err)
+	}
+	boot.Error("Logged after promotion")
+
+	if len(messages) != 2 {
+		test.Fatalf("Expected 2 forwarded messages. Found: %v", messages)
+	}
+	if messages[0].Logger != "main.config" || messages[0].Level != logs.Warn {
+		test.Errorf("Expected the buffered WARN to be replayed from main.config. Found: %+v", messages[0])
+	}
+	if messages[1].Logger != "main" || messages[1].Message != "Logged after promotion" {
+		test.Errorf("Expected records logged after promotion to be forwarded. Found: %+v", messages[1])
+	}
+
+	if err := logs.Promote(boot, configured); nil == err {
+		test.Error("Expected an error promoting a bootstrap logger twice")
+	}
+}
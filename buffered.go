@@ -0,0 +1,149 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// BufferedHandlerConfig configures a BufferedHandler
+type BufferedHandlerConfig struct {
+	// Writer receives the buffered output
+	Writer io.Writer
+	// Encoder serializes each LogMessage. Defaults to TextEncoder.
+	Encoder Encoder
+	// Framing delimits entries. Defaults to NewlineFraming.
+	Framing Framing
+	// Size is the number of bytes buffered before they are written. Defaults to
+	// 64KiB.
+	Size int
+	// FlushInterval is the longest an entry is held in the buffer. Defaults to
+	// 1 second.
+	FlushInterval time.Duration
+	// FlushLevel is the level at which an entry causes the buffer to be flushed
+	// immediately, so that errors are never held back. Defaults to Error.
+	FlushLevel LogLevel
+}
+
+// BufferedHandler accumulates encoded entries in memory and writes them to a
+// Writer in large chunks, reducing write syscalls for chatty services. The buffer
+// is flushed when it is full, on an interval, when an entry at FlushLevel or
+// above is logged, and by Flush() and Close().
+type BufferedHandler struct {
+	config BufferedHandlerConfig
+	mu     sync.Mutex
+	buffer []byte
+	closed bool
+	err    error
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewBufferedHandler validates the config and starts a BufferedHandler
+func NewBufferedHandler(config BufferedHandlerConfig) (*BufferedHandler, error) {
+	if nil == config.Writer {
+		return nil, fmt.Errorf("BufferedHandler requires a Writer")
+	}
+	if nil == config.Encoder {
+		config.Encoder = TextEncoder
+	}
+	if nil == config.Framing {
+		config.Framing = NewlineFraming
+	}
+	if config.Size < 1 {
+		config.Size = 64 * 1024
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.FlushLevel == NotSet {
+		config.FlushLevel = Error
+	}
+
+	h := &BufferedHandler{
+		config: config,
+		buffer: make([]byte, 0, config.Size),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go h.run()
+
+	return h, nil
+}
+
+// LogHandler adds a LogMessage to the buffer. It is a LogHandler.
+func (h *BufferedHandler) LogHandler(msg LogMessage) {
+	data, err := h.config.Encoder(msg)
+	if err != nil {
+		return
+	}
+	data = h.config.Framing(data)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		h.config.Writer.Write(data)
+		return
+	}
+
+	if len(h.buffer)+len(data) > h.config.Size {
+		h.flush()
+	}
+	h.buffer = append(h.buffer, data...)
+	if len(h.buffer) >= h.config.Size || msg.Level >= h.config.FlushLevel {
+		h.flush()
+	}
+}
+
+// Flush writes the buffered entries to the Writer. It returns the first error
+// encountered writing to the Writer since the last call to Flush().
+func (h *BufferedHandler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flush()
+	err := h.err
+	h.err = nil
+	return err
+}
+
+// Close stops the flush interval and flushes the buffer. Entries logged after
+// Close() are written to the Writer directly. It is safe to call more than once.
+func (h *BufferedHandler) Close() error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.stop)
+	}
+	h.mu.Unlock()
+
+	<-h.done
+	return h.Flush()
+}
+
+// flush must be called with h.mu held
+func (h *BufferedHandler) flush() {
+	if len(h.buffer) < 1 {
+		return
+	}
+	if _, err := h.config.Writer.Write(h.buffer); err != nil && nil == h.err {
+		h.err = err
+	}
+	h.buffer = h.buffer[:0]
+}
+
+func (h *BufferedHandler) run() {
+	defer close(h.done)
+	ticker := time.NewTicker(h.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.mu.Lock()
+			h.flush()
+			h.mu.Unlock()
+		case <-h.stop:
+			return
+		}
+	}
+}
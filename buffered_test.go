@@ -0,0 +1,97 @@
+package gologsgo_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// countingWriter records each call to Write separately
+type countingWriter struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func (w *countingWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.writes)
+}
+
+func TestBufferedHandler(test *testing.T) {
+	writer := &countingWriter{}
+	handler, err := logs.NewBufferedHandler(logs.BufferedHandlerConfig{
+		Writer:        writer,
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		handler.LogHandler(logs.LogMessage{Level: logs.Info, LevelLabel: "INFO", Message: "chatty"})
+	}
+	if writer.count() != 0 {
+		test.Fatalf("Expected Info entries to be buffered. Found %d writes", writer.count())
+	}
+
+	handler.LogHandler(logs.LogMessage{Level: logs.Error, LevelLabel: "ERROR", Message: "boom"})
+	if writer.count() != 1 || strings.Count(writer.writes[0], "\n") != 11 {
+		test.Fatalf("Expected an Error entry to flush everything in one write. Found: %q", writer.writes)
+	}
+
+	handler.LogHandler(logs.LogMessage{Level: logs.Info, LevelLabel: "INFO", Message: "last"})
+	if err := handler.Close(); err != nil {
+		test.Fatalf("Unexpected error from Close(): %v", err)
+	}
+	if writer.count() != 2 || !strings.Contains(writer.writes[1], "last") {
+		test.Errorf("Expected Close() to flush the buffer. Found: %q", writer.writes)
+	}
+}
+
+func TestBufferedHandlerFlushesWhenFullAndOnInterval(test *testing.T) {
+	writer := &countingWriter{}
+	handler, err := logs.NewBufferedHandler(logs.BufferedHandlerConfig{
+		Writer:        writer,
+		Size:          32,
+		FlushInterval: 20 * time.Millisecond,
+		Encoder: func(msg logs.LogMessage) ([]byte, error) {
+			return []byte(msg.Message), nil
+		},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer handler.Close()
+
+	entry := strings.Repeat("x", 20)
+	handler.LogHandler(logs.LogMessage{Level: logs.Info, Message: entry})
+	handler.LogHandler(logs.LogMessage{Level: logs.Info, Message: entry})
+	if writer.count() != 1 {
+		test.Errorf("Expected a full buffer to be written. Found %d writes", writer.count())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for writer.count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if writer.count() != 2 {
+		test.Errorf("Expected the interval to flush the buffer. Found %d writes", writer.count())
+	}
+}
+
+func TestBufferedHandlerRequiresWriter(test *testing.T) {
+	if _, err := logs.NewBufferedHandler(logs.BufferedHandlerConfig{}); err == nil {
+		test.Errorf("Expected an error without a Writer")
+	}
+}
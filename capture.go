@@ -0,0 +1,189 @@
+package gologsgo
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// activeCaptures lets Logger.log skip looking for captures in the common case
+// where there are none
+var activeCaptures int32
+
+// capturelock guards the captures registered on every Logger
+var capturelock sync.RWMutex
+
+// CaptureOptions configures a burst capture started with Logger.Capture()
+type CaptureOptions struct {
+	// Duration stops the capture after the given time. Zero means the capture
+	// runs until MaxRecords is reached, the context is done or Stop() is called.
+	Duration time.Duration
+	// MaxRecords stops the capture after the given number of records. Zero
+	// means no limit.
+	MaxRecords int
+	// Writer, when set, receives each captured record (one per line) instead of
+	// it being held in memory. Use an *os.File to capture to a file.
+	Writer io.Writer
+	// Encoder is used to write records to Writer. Defaults to TextEncoder.
+	Encoder Encoder
+	// Handler, when set, receives each captured record instead of it being held
+	// in memory or written to Writer
+	Handler LogHandler
+}
+
+// Capture is a handle to a burst capture. It collects every record logged by a
+// Logger and its children - at every level, regardless of their configured
+// levels - until it is stopped.
+type Capture struct {
+	logger  *Logger
+	options CaptureOptions
+	mu      sync.Mutex
+	records []LogMessage
+	count   int
+	err     error
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Capture starts capturing everything logged by the Logger and its children,
+// at every level, for a limited time or number of records. This is a focused
+// tool for reproducing intermittent problems in production without turning on
+// verbose logging everywhere. Records are still sent to the LogHandler as usual
+// if they meet the logger's level.
+func (logger *Logger) Capture(ctx context.Context, options CaptureOptions) *Capture {
+	if nil == options.Encoder {
+		options.Encoder = TextEncoder
+	}
+
+	c := &Capture{
+		logger:  logger,
+		options: options,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	capturelock.Lock()
+	logger.captures = append(logger.captures, c)
+	capturelock.Unlock()
+	atomic.AddInt32(&activeCaptures, 1)
+
+	go c.wait(ctx)
+
+	return c
+}
+
+// wait ends the capture when its context is done, its duration elapses or it
+// is stopped
+func (c *Capture) wait(ctx context.Context) {
+	var timeout <-chan time.Time
+	if c.options.Duration > 0 {
+		timer := time.NewTimer(c.options.Duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-timeout:
+	case <-c.stop:
+	}
+
+	capturelock.Lock()
+	captures := c.logger.captures
+	for i, other := range captures {
+		if other == c {
+			c.logger.captures = append(captures[:i:i], captures[i+1:]...)
+			break
+		}
+	}
+	capturelock.Unlock()
+	atomic.AddInt32(&activeCaptures, -1)
+
+	close(c.done)
+}
+
+// record adds a message to the capture, stopping it once MaxRecords is reached
+func (c *Capture) record(msg LogMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.options.MaxRecords > 0 && c.count >= c.options.MaxRecords {
+		return
+	}
+	c.count++
+
+	if nil != c.options.Handler {
+		c.options.Handler(msg)
+	} else if nil != c.options.Writer {
+		data, err := c.options.Encoder(msg)
+		if nil == err {
+			_, err = c.options.Writer.Write(append(data, '\n'))
+		}
+		if nil != err && nil == c.err {
+			c.err = err
+		}
+	} else {
+		c.records = append(c.records, msg)
+	}
+
+	if c.options.MaxRecords > 0 && c.count >= c.options.MaxRecords {
+		c.Stop()
+	}
+}
+
+// Stop ends the capture early. It is safe to call more than once.
+func (c *Capture) Stop() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+}
+
+// Done returns a channel that is closed once the capture has ended
+func (c *Capture) Done() <-chan struct{} {
+	return c.done
+}
+
+// Records returns the records captured so far. Records written to a Writer or
+// passed to a Handler are not retained.
+func (c *Capture) Records() []LogMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	records := make([]LogMessage, len(c.records))
+	copy(records, c.records)
+	return records
+}
+
+// Count returns the number of records captured so far
+func (c *Capture) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+// Err returns the first error encountered writing to the capture's Writer
+func (c *Capture) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// capture offers a message to every capture registered on the logger or one
+// of its ancestors
+func (logger *Logger) capture(msg LogMessage) {
+	if atomic.LoadInt32(&activeCaptures) == 0 {
+		return
+	}
+
+	capturelock.RLock()
+	var captures []*Capture
+	for l := logger; nil != l; l = l.parent {
+		captures = append(captures, l.captures...)
+	}
+	capturelock.RUnlock()
+
+	for _, c := range captures {
+		c.record(msg)
+	}
+}
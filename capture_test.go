@@ -0,0 +1,69 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestCapture(test *testing.T) {
+	var delivered []logs.LogMessage
+	root := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		Level: logs.Warn,
+		LogHandler: func(msg logs.LogMessage) {
+			delivered = append(delivered, msg)
+		},
+	})
+	child := root.ChildLogger("child")
+
+	capture := root.Capture(context.Background(), logs.CaptureOptions{MaxRecords: 3})
+	child.Trace("A trace log message")
+	root.Debug("A debug log message")
+	child.Warn("A warn log message")
+	child.Error("An error log message after the capture is full")
+
+	select {
+	case <-capture.Done():
+	case <-time.After(time.Second):
+		test.Fatal("Expected capture to end after MaxRecords")
+	}
+
+	records := capture.Records()
+	if len(records) != 3 {
+		test.Fatalf("Expected 3 captured records. Found: %d", len(records))
+	}
+	if records[0].Level != logs.Trace || records[0].Logger != "main.child" {
+		test.Errorf("Expected first captured record to be TRACE from main.child. Found: %v", records[0])
+	}
+
+	// Capturing must not change what reaches the LogHandler
+	if len(delivered) != 2 {
+		test.Errorf("Expected 2 records delivered to the LogHandler. Found: %d", len(delivered))
+	}
+}
+
+func TestCaptureWriter(test *testing.T) {
+	root := logs.New(&logs.RootLogConfig{
+		Level:      logs.Off,
+		LogHandler: func(logs.LogMessage) {},
+	})
+
+	var buffer bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	capture := root.ChildLogger("worker").Capture(ctx, logs.CaptureOptions{Writer: &buffer})
+	root.Info("Not in the captured subtree")
+	root.ChildLogger("worker").Debug("A debug log message")
+	cancel()
+	<-capture.Done()
+	root.ChildLogger("worker").Debug("After the capture ended")
+
+	out := buffer.String()
+	if !strings.HasSuffix(out, "DEBUG [worker]: A debug log message\n") || strings.Count(out, "\n") != 1 {
+		test.Errorf("Unexpected capture output: %q", out)
+	}
+}
@@ -0,0 +1,139 @@
+package gologsgo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// This file contains the small subset of CBOR (RFC 8949) needed to encode log
+// entries. It is not a general purpose implementation.
+
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborText     = 3 << 5
+	cborMap      = 5 << 5
+	cborTag      = 6 << 5
+)
+
+// appendCBORHead appends the initial byte and argument of a data item
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n < 1<<8:
+		return append(b, major|24, byte(n))
+	case n < 1<<16:
+		return append(b, major|25, byte(n>>8), byte(n))
+	case n < 1<<32:
+		return append(b, major|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		b = append(b, major|27)
+		b = append(b, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(b[len(b)-8:], n)
+		return b
+	}
+}
+
+func appendCBORString(b []byte, s string) []byte {
+	b = appendCBORHead(b, cborText, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendCBORInt(b []byte, i int64) []byte {
+	if i < 0 {
+		return appendCBORHead(b, cborNegative, uint64(-1-i))
+	}
+	return appendCBORHead(b, cborUnsigned, uint64(i))
+}
+
+// appendCBORValue appends a field value. Strings, booleans, numbers and nil are
+// encoded natively. Anything else is encoded as its fmt.Sprint string.
+func appendCBORValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xf6)
+	case bool:
+		if v {
+			return append(b, 0xf5)
+		}
+		return append(b, 0xf4)
+	case string:
+		return appendCBORString(b, v)
+	case int:
+		return appendCBORInt(b, int64(v))
+	case int8:
+		return appendCBORInt(b, int64(v))
+	case int16:
+		return appendCBORInt(b, int64(v))
+	case int32:
+		return appendCBORInt(b, int64(v))
+	case int64:
+		return appendCBORInt(b, v)
+	case uint:
+		return appendCBORHead(b, cborUnsigned, uint64(v))
+	case uint8:
+		return appendCBORHead(b, cborUnsigned, uint64(v))
+	case uint16:
+		return appendCBORHead(b, cborUnsigned, uint64(v))
+	case uint32:
+		return appendCBORHead(b, cborUnsigned, uint64(v))
+	case uint64:
+		return appendCBORHead(b, cborUnsigned, v)
+	case float32:
+		return appendCBORFloat(b, float64(v))
+	case float64:
+		return appendCBORFloat(b, v)
+	default:
+		return appendCBORString(b, fmt.Sprint(v))
+	}
+}
+
+func appendCBORFloat(b []byte, f float64) []byte {
+	b = append(b, 0xfb)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[len(b)-8:], math.Float64bits(f))
+	return b
+}
+
+func init() {
+	RegisterFormat("cbor", FormatTraits{Binary: true})
+}
+
+// CBOREncoder encodes a LogMessage as a CBOR map with the same keys as
+// JSONEncoder. The time is an RFC3339 date/time string (tag 0). Like
+// MsgpackEncoder, use it with LengthPrefixFraming.
+func CBOREncoder(msg LogMessage) ([]byte, error) {
+	n := 3
+	if len(msg.Logger) > 0 {
+		n++
+	}
+	if len(msg.Fields) > 0 {
+		n++
+	}
+
+	b := make([]byte, 0, 64+len(msg.Message))
+	b = appendCBORHead(b, cborMap, uint64(n))
+	b = appendCBORString(b, "time")
+	b = appendCBORHead(b, cborTag, 0)
+	b = appendCBORString(b, msg.Time.Format(time.RFC3339Nano))
+	b = appendCBORString(b, "level")
+	b = appendCBORString(b, levelLabel(msg))
+	if len(msg.Logger) > 0 {
+		b = appendCBORString(b, "logger")
+		b = appendCBORString(b, msg.Logger)
+	}
+	b = appendCBORString(b, "message")
+	b = appendCBORString(b, msg.Message)
+	if len(msg.Fields) > 0 {
+		b = appendCBORString(b, "fields")
+		b = appendCBORHead(b, cborMap, uint64(len(msg.Fields)))
+		for _, k := range fieldKeys(msg.Fields) {
+			b = appendCBORString(b, k)
+			b = appendCBORValue(b, msg.Fields[k])
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,63 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestBinaryEncoders(test *testing.T) {
+	msg := logs.LogMessage{
+		Level:      logs.Info,
+		LevelLabel: "info",
+		Message:    "hi",
+		Time:       time.Unix(1, 2).UTC(),
+		Fields:     map[string]interface{}{"n": -2, "ok": true},
+	}
+
+	data, err := logs.MsgpackEncoder(msg)
+	if err != nil {
+		test.Fatal(err)
+	}
+	expected := []byte{
+		0x84,
+		0xa4, 't', 'i', 'm', 'e', 0xc7, 12, 0xff, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1,
+		0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'I', 'N', 'F', 'O',
+		0xa7, 'm', 'e', 's', 's', 'a', 'g', 'e', 0xa2, 'h', 'i',
+		0xa6, 'f', 'i', 'e', 'l', 'd', 's', 0x82, 0xa1, 'n', 0xfe, 0xa2, 'o', 'k', 0xc3,
+	}
+	if !bytes.Equal(data, expected) {
+		test.Errorf("Expected MessagePack:\n% x\nFound:\n% x", expected, data)
+	}
+
+	data, err = logs.CBOREncoder(msg)
+	if err != nil {
+		test.Fatal(err)
+	}
+	expected = []byte{0xa4, 0x64, 't', 'i', 'm', 'e', 0xc0, 0x78, 30}
+	expected = append(expected, "1970-01-01T00:00:01.000000002Z"...)
+	expected = append(expected, 0x65, 'l', 'e', 'v', 'e', 'l', 0x64, 'I', 'N', 'F', 'O')
+	expected = append(expected, 0x67, 'm', 'e', 's', 's', 'a', 'g', 'e', 0x62, 'h', 'i')
+	expected = append(expected, 0x66, 'f', 'i', 'e', 'l', 'd', 's', 0xa2, 0x61, 'n', 0x21, 0x62, 'o', 'k', 0xf5)
+	if !bytes.Equal(data, expected) {
+		test.Errorf("Expected CBOR:\n% x\nFound:\n% x", expected, data)
+	}
+}
+
+func TestMsgpackUnsignedFields(test *testing.T) {
+	data, err := logs.MsgpackEncoder(logs.LogMessage{
+		Level:      logs.Info,
+		LevelLabel: "INFO",
+		Fields:     map[string]interface{}{"id": uint64(1) << 63, "n": uint(5)},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	id := []byte{0xa2, 'i', 'd', 0xcf, 0x80, 0, 0, 0, 0, 0, 0, 0}
+	n := []byte{0xa1, 'n', 0x05}
+	if !bytes.Contains(data, id) || !bytes.Contains(data, n) {
+		test.Errorf("Expected unsigned integers to be encoded natively. Found:\n% x", data)
+	}
+}
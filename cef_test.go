@@ -0,0 +1,32 @@
+package gologsgo_test
+
+import (
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestCEFEncoder(test *testing.T) {
+	encode := logs.CEFEncoder(logs.CEFConfig{Vendor: "Acme", Product: "Bill|ing", Version: "2.0"})
+	data, err := encode(logs.LogMessage{
+		Level:      logs.Warn,
+		LevelLabel: "WARN",
+		Logger:     "auth",
+		Message:    "Login failed for a|b",
+		Time:       time.Unix(1500000000, 0),
+		Fields: map[string]interface{}{
+			"event_id": "login-failed",
+			"suser":    "jane",
+			"reason":   "bad=password\nagain",
+		},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := `CEF:0|Acme|Bill\|ing|2.0|login-failed|Login failed for a\|b|6|rt=1500000000000 cs1Label=logger cs1=auth reason=bad\=password\nagain suser=jane`
+	if string(data) != expected {
+		test.Errorf("Expected:\n%s\nFound:\n%s", expected, data)
+	}
+}
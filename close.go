@@ -0,0 +1,133 @@
+package gologsgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// LastResortWriter receives records that can't be delivered normally, such as
+// those logged after Close(). It is plain text, written with TextEncoder.
+var LastResortWriter io.Writer = os.Stderr
+
+var lastresortlock sync.Mutex
+
+// lastResortWarning reports a problem with the logging setup, such as an invalid
+// setting or a failure to reload levels, to LastResortWriter
+func lastResortWarning(format string, args ...interface{}) {
+	lastResortReport("WARN", format, args...)
+}
+
+// lastResortError reports a problem with the logging setup that loses entries to
+// LastResortWriter
+func lastResortError(format string, args ...interface{}) {
+	lastResortReport("ERROR", format, args...)
+}
+
+func lastResortReport(level string, format string, args ...interface{}) {
+	lastresortlock.Lock()
+	defer lastresortlock.Unlock()
+	fmt.Fprintf(LastResortWriter, level+" [gologsgo]: "+format+"\n", args...)
+}
+
+// lifecycle is shared by a root Logger and all of its children
+type lifecycle struct {
+	closed  int32
+	mu      sync.Mutex
+	closers []io.Closer
+	warned  sync.Once
+}
+
+// RegisterCloser adds an io.Closer - typically a handler with queued entries,
+// such as a NetworkHandler - to be closed when the Logger is closed. Closers are
+// closed in the reverse of the order they were registered.
+func (logger *Logger) RegisterCloser(closer io.Closer) {
+	logger.lifecycle.mu.Lock()
+	defer logger.lifecycle.mu.Unlock()
+	logger.lifecycle.closers = append(logger.lifecycle.closers, closer)
+}
+
+// Close closes the Logger tree it belongs to - the root Logger and all of its
+// children - and each registered io.Closer, draining their queued entries. Records
+// logged after Close() are written to LastResortWriter, preceded by a one-time
+// warning. The first error returned by a closer is returned.
+func (logger *Logger) Close() error {
+	if !atomic.CompareAndSwapInt32(&logger.lifecycle.closed, 0, 1) {
+		return nil
+	}
+
+	logger.lifecycle.mu.Lock()
+	closers := logger.lifecycle.closers
+	logger.lifecycle.closers = nil
+	logger.lifecycle.mu.Unlock()
+
+	var first error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil && nil == first {
+			first = err
+		}
+	}
+	return first
+}
+
+// FlushCloser may be implemented by handlers that hold entries in memory, such as
+// BufferedHandler and AsyncHandler. Register them with RegisterCloser() or
+// RootLogConfig.Closers so that Shutdown() can flush and close them.
+type FlushCloser interface {
+	Flush() error
+	Close() error
+}
+
+// Shutdown gracefully shuts down the Logger tree it belongs to. Every registered
+// closer that implements FlushCloser is flushed, then the tree is closed as with
+// Close(). If ctx is done first, Shutdown returns ctx.Err() and the remaining work
+// continues in the background. Otherwise, the first error encountered is returned.
+func (logger *Logger) Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		logger.lifecycle.mu.Lock()
+		closers := append([]io.Closer(nil), logger.lifecycle.closers...)
+		logger.lifecycle.mu.Unlock()
+
+		var first error
+		for i := len(closers) - 1; i >= 0; i-- {
+			if flusher, ok := closers[i].(FlushCloser); ok {
+				if err := flusher.Flush(); err != nil && nil == first {
+					first = err
+				}
+			}
+		}
+		if err := logger.Close(); err != nil && nil == first {
+			first = err
+		}
+		done <- first
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Closed reports whether the Logger tree has been closed
+func (logger *Logger) Closed() bool {
+	return atomic.LoadInt32(&logger.lifecycle.closed) == 1
+}
+
+// lastResort writes a record logged after Close() to LastResortWriter
+func (l *lifecycle) lastResort(msg LogMessage) {
+	lastresortlock.Lock()
+	defer lastresortlock.Unlock()
+
+	l.warned.Do(func() {
+		fmt.Fprintln(LastResortWriter, "WARN [gologsgo]: A Logger was used after Close(). Further records will be written here.")
+	})
+	if data, err := TextEncoder(msg); nil == err {
+		fmt.Fprintln(LastResortWriter, string(data))
+	}
+}
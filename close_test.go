@@ -0,0 +1,128 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+type closerFunc func() error
+
+func (fn closerFunc) Close() error {
+	return fn()
+}
+
+func TestClose(test *testing.T) {
+	var order []string
+	var delivered int
+	root := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		LogHandler: func(logs.LogMessage) {
+			delivered++
+		},
+		Closers: []io.Closer{closerFunc(func() error {
+			order = append(order, "first")
+			return nil
+		})},
+	})
+	root.RegisterCloser(closerFunc(func() error {
+		order = append(order, "second")
+		return nil
+	}))
+	child := root.ChildLogger("child")
+
+	var lastResort bytes.Buffer
+	output := logs.LastResortWriter
+	logs.LastResortWriter = &lastResort
+	defer func() {
+		logs.LastResortWriter = output
+	}()
+
+	child.Info("Before Close")
+	if err := root.Close(); nil != err {
+		test.Errorf("Error closing logger: %s", err)
+	}
+	if !child.Closed() {
+		test.Error("Expected children of a closed logger to be closed")
+	}
+	if strings.Join(order, ",") != "second,first" {
+		test.Errorf("Expected closers to be closed in reverse order. Found: %v", order)
+	}
+
+	child.Info("After Close")
+	child.Warn("Still after Close")
+
+	if delivered != 1 {
+		test.Errorf("Expected only the record logged before Close to be delivered. Found: %d", delivered)
+	}
+	out := lastResort.String()
+	if strings.Count(out, "used after Close()") != 1 ||
+		!strings.Contains(out, "INFO [main.child]: After Close") ||
+		!strings.Contains(out, "WARN [main.child]: Still after Close") {
+		test.Errorf("Unexpected last resort output:\n%s", out)
+	}
+}
+
+type flushCloser struct {
+	calls *[]string
+	name  string
+}
+
+func (f flushCloser) Flush() error {
+	*f.calls = append(*f.calls, "flush "+f.name)
+	return nil
+}
+
+func (f flushCloser) Close() error {
+	*f.calls = append(*f.calls, "close "+f.name)
+	return nil
+}
+
+func TestShutdown(test *testing.T) {
+	var calls []string
+	root := logs.New(&logs.RootLogConfig{
+		LogHandler: func(logs.LogMessage) {},
+		Closers: []io.Closer{
+			flushCloser{&calls, "a"},
+			closerFunc(func() error {
+				calls = append(calls, "close plain")
+				return nil
+			}),
+			flushCloser{&calls, "b"},
+		},
+	})
+
+	if err := root.Shutdown(context.Background()); err != nil {
+		test.Fatalf("Unexpected error from Shutdown(): %v", err)
+	}
+	expected := "flush b,flush a,close b,close plain,close a"
+	if strings.Join(calls, ",") != expected {
+		test.Errorf("Expected %q. Found: %q", expected, strings.Join(calls, ","))
+	}
+	if !root.Closed() {
+		test.Errorf("Expected Shutdown() to close the logger")
+	}
+}
+
+func TestShutdownDeadline(test *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	root := logs.New(&logs.RootLogConfig{
+		LogHandler: func(logs.LogMessage) {},
+		Closers: []io.Closer{closerFunc(func() error {
+			<-release
+			return nil
+		})},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := root.Shutdown(ctx); err != context.DeadlineExceeded {
+		test.Errorf("Expected Shutdown() to give up at the deadline. Found: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Command gologsgo provides tooling for logs written by go-logs-go.
+//
+// Usage:
+//
+//	gologsgo decrypt -keys keys.json [file ...]
+//
+// decrypt reads records written with logs.EncryptingEncoder from the named files
+// (or stdin) and writes them decrypted to stdout. Lines that aren't encrypted
+// are passed through unchanged. The keys file is a JSON object mapping key IDs
+// to base64 encoded 32 byte keys.
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "decrypt":
+		if err := decrypt(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: gologsgo decrypt -keys keys.json [file ...]")
+	os.Exit(2)
+}
+
+func decrypt(args []string) error {
+	flags := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	keysFile := flags.String("keys", "", "JSON file mapping key IDs to base64 encoded keys")
+	flags.Parse(args)
+
+	if len(*keysFile) == 0 {
+		return fmt.Errorf("decrypt requires -keys")
+	}
+	keys, err := readKeys(*keysFile)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	if flags.NArg() == 0 {
+		return decryptStream(os.Stdin, out, keys)
+	}
+	for _, name := range flags.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = decryptStream(f, out, keys)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+func readKeys(path string) (logs.StaticKeys, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return logs.StaticKeys{}, err
+	}
+	encoded := make(map[string]string)
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return logs.StaticKeys{}, fmt.Errorf("Unable to parse %s: %s", path, err)
+	}
+
+	keys := logs.StaticKeys{Keys: make(map[string][]byte)}
+	for id, k := range encoded {
+		key, err := base64.StdEncoding.DecodeString(k)
+		if err != nil {
+			return logs.StaticKeys{}, fmt.Errorf("Key %q in %s is not valid base64: %s", id, path, err)
+		}
+		keys.Keys[id] = key
+	}
+	return keys, nil
+}
+
+func decryptStream(in io.Reader, out io.Writer, keys logs.KeyProvider) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := scanner.Bytes()
+		if logs.IsEncryptedRecord(record) {
+			plaintext, err := logs.DecryptRecord(record, keys)
+			if err != nil {
+				return fmt.Errorf("line %d: %s", line, err)
+			}
+			record = plaintext
+		}
+		out.Write(record)
+		out.Write([]byte{'\n'})
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,128 @@
+package gologsgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatTraits describes the properties of an output format that determine which
+// sinks can carry it
+type FormatTraits struct {
+	// Binary formats may contain arbitrary bytes, including newlines
+	Binary bool
+	// Colored formats contain ANSI escape sequences
+	Colored bool
+	// MultiLine formats may contain raw newlines (for example, from a message)
+	MultiLine bool
+}
+
+// SinkTraits describes what a sink requires of the format written to it
+type SinkTraits struct {
+	// Terminal sinks are read by people, so can't display binary formats
+	Terminal bool
+	// NewlineDelimited sinks split entries on newlines, so can't carry binary or
+	// multi-line formats
+	NewlineDelimited bool
+	// Format, when set, is the only format the sink's protocol accepts
+	Format string
+}
+
+var compatlock sync.RWMutex
+
+// formatTraits holds the traits of the formats of this file's encoders. Formats
+// defined elsewhere register their traits with the format.
+var formatTraits = map[string]FormatTraits{
+	"text":     {MultiLine: true},
+	"color":    {Colored: true, MultiLine: true},
+	"json":     {},
+	"logstash": {},
+	"fluent":   {Binary: true},
+}
+
+// sinkTraits holds the traits of the sinks. Files are byte streams, so they
+// take multi-line formats, such as the default "text".
+var sinkTraits = map[string]SinkTraits{
+	"console":  {Terminal: true},
+	"file":     {},
+	"tcp":      {NewlineDelimited: true},
+	"udp":      {},
+	"unix":     {NewlineDelimited: true},
+	"unixgram": {},
+	"logstash": {NewlineDelimited: true, Format: "logstash"},
+	"fluent":   {Format: "fluent"},
+}
+
+// RegisterFormat declares the traits of a named format so it can be validated by
+// CheckCompatibility(). Registering an existing name replaces it.
+func RegisterFormat(name string, traits FormatTraits) {
+	compatlock.Lock()
+	defer compatlock.Unlock()
+	formatTraits[name] = traits
+}
+
+// RegisterSink declares the traits of a named sink so it can be validated by
+// CheckCompatibility(). Registering an existing name replaces it.
+func RegisterSink(name string, traits SinkTraits) {
+	compatlock.Lock()
+	defer compatlock.Unlock()
+	sinkTraits[name] = traits
+}
+
+// CheckCompatibility returns an error if entries in the named format can't be
+// written to the named sink without being garbled - for example, a binary format
+// to the console, or a colored format to a file. The outputs of the "handlers"
+// setting are checked when a config is parsed, so that mistakes fail fast at
+// setup with an actionable error instead of producing garbled output at runtime.
+func CheckCompatibility(sink string, format string) error {
+	return checkCompatibility(sink, format, false)
+}
+
+// checkCompatibility checks a format written to a sink. singleLine is set when
+// newlines in the format's entries are escaped, so it isn't multi-line.
+func checkCompatibility(sink string, format string, singleLine bool) error {
+	compatlock.RLock()
+	defer compatlock.RUnlock()
+
+	s, ok := sinkTraits[sink]
+	if !ok {
+		return fmt.Errorf("Unknown sink %q. Known sinks are: %s", sink, knownNames(sinkTraits))
+	}
+	f, ok := formatTraits[format]
+	if !ok {
+		return fmt.Errorf("Unknown format %q. Known formats are: %s", format, knownNames(formatTraits))
+	}
+
+	switch {
+	case len(s.Format) > 0 && s.Format != format:
+		return fmt.Errorf("The %q sink only accepts the %q format, not %q. Remove the format or set it to %q", sink, s.Format, format, s.Format)
+	case s.Terminal && f.Binary:
+		return fmt.Errorf("The binary %q format can't be written to the %q sink. Use a text format such as \"text\" or \"color\"", format, sink)
+	case !s.Terminal && f.Colored:
+		return fmt.Errorf("The %q format would write ANSI escape sequences to the %q sink. Use \"text\" or \"json\" instead", format, sink)
+	case s.NewlineDelimited && f.Binary:
+		return fmt.Errorf("The binary %q format can't be written to the newline delimited %q sink. Use \"json\", or a sink with length prefixed framing", format, sink)
+	case s.NewlineDelimited && f.MultiLine && !singleLine:
+		return fmt.Errorf("The %q format may split entries across lines in the newline delimited %q sink. Use \"json\", or set singleLine", format, sink)
+	}
+
+	return nil
+}
+
+// knownNames lists the keys of a traits map for error messages
+func knownNames(traits interface{}) string {
+	var names []string
+	switch t := traits.(type) {
+	case map[string]FormatTraits:
+		for name := range t {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+	case map[string]SinkTraits:
+		for name := range t {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,55 @@
+package gologsgo_test
+
+import (
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestCheckCompatibility(test *testing.T) {
+	cases := []struct {
+		sink, format string
+		errContains  string
+	}{
+		{"console", "color", ""},
+		{"file", "json", ""},
+		{"file", "text", ""},
+		{"udp", "text", ""},
+		{"console", "ecs", ""},
+		{"console", "protobuf", "binary"},
+		{"tcp", "template", "split entries across lines"},
+		{"console", "fluent", "binary"},
+		{"file", "color", "ANSI escape sequences"},
+		{"tcp", "text", "split entries across lines"},
+		{"fluent", "json", "only accepts"},
+		{"carrier-pigeon", "json", "Unknown sink"},
+		{"file", "yaml", "Unknown format"},
+	}
+
+	for _, c := range cases {
+		err := logs.CheckCompatibility(c.sink, c.format)
+		if len(c.errContains) == 0 {
+			if nil != err {
+				test.Errorf("Expected %s to %s to be compatible. Found: %s", c.format, c.sink, err)
+			}
+			continue
+		}
+		if nil == err || !strings.Contains(err.Error(), c.errContains) {
+			test.Errorf("Expected an error containing %q for %s to %s. Found: %v", c.errContains, c.format, c.sink, err)
+		}
+	}
+
+	// Outputs are checked when a config is parsed
+	if _, err := logs.JsonConfig([]byte(`{"handlers": [{"type": "network", "url": "tcp://collector:5000", "format": "text"}]}`)); nil == err || !strings.Contains(err.Error(), "split entries across lines") {
+		test.Errorf("Expected text to a TCP output to fail. Found: %v", err)
+	}
+	if _, err := logs.JsonConfig([]byte(`{"handlers": [{"type": "network", "url": "tcp://collector:5000", "format": "text", "singleLine": true}]}`)); nil != err {
+		test.Errorf("Expected single line text to a TCP output to be compatible. Found: %s", err)
+	}
+
+	logs.RegisterFormat("yaml", logs.FormatTraits{MultiLine: true})
+	if err := logs.CheckCompatibility("console", "yaml"); nil != err {
+		test.Errorf("Expected a registered format to be compatible with the console. Found: %s", err)
+	}
+}
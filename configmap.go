@@ -0,0 +1,37 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+// configMapResync is how often a mounted ConfigMap is read again, in case an
+// update was missed by the watcher
+const configMapResync = time.Minute
+
+// WatchConfigMap reloads the levels of the Logger and its descendants from key of
+// a Kubernetes ConfigMap or Secret mounted at dir, such as
+//
+//	logger.WatchConfigMap("/etc/my-app", "log.json")
+//
+// kubelet updates a mounted volume atomically: it writes the new files to a
+// timestamped directory, swaps the ..data symlink to point to it, and removes
+// the old directory, and the files in dir are symlinks through ..data. Watching
+// the file itself misses the update, as the file is never written. The directory
+// is watched instead, and the config is read again, following the symlinks, once
+// ..data is swapped, so files are never read halfway through an update. The
+// config is also read every minute, in case an update was missed, and is only
+// applied when its content changes. Otherwise it is WatchFileConfig() for the
+// key: the file is parsed in the format of its extension, only its levels are
+// applied, and a file that fails to parse, or is removed from the ConfigMap,
+// keeps the current levels. Volumes mounted with subPath are not updated by
+// kubelet, so they can't be watched. Watching stops when the returned
+// io.Closer, or the Logger, is closed.
+func (logger *Logger) WatchConfigMap(dir string, key string) (io.Closer, error) {
+	if len(key) == 0 || filepath.Base(key) != key {
+		return nil, fmt.Errorf("Invalid ConfigMap key %q", key)
+	}
+	return logger.watchFile(filepath.Join(dir, key), configMapResync)
+}
@@ -0,0 +1,76 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// writeConfigMap updates a directory the way kubelet updates a mounted ConfigMap:
+// the files are written to a new timestamped directory, and the ..data symlink is
+// swapped to point to it
+func writeConfigMap(test *testing.T, dir string, version string, files map[string]string) {
+	data := filepath.Join(dir, "..2026_10_17_"+version)
+	if err := os.Mkdir(data, 0755); err != nil {
+		test.Fatal(err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(data, name), []byte(content), 0644); err != nil {
+			test.Fatal(err)
+		}
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			if err := os.Symlink(filepath.Join("..data", name), link); err != nil {
+				test.Fatal(err)
+			}
+		}
+	}
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(filepath.Base(data), tmp); err != nil {
+		test.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		test.Fatal(err)
+	}
+}
+
+func TestWatchConfigMap(test *testing.T) {
+	dir, err := ioutil.TempDir("", "configmap")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeConfigMap(test, dir, "1", map[string]string{"log.json": `{"level": "INFO"}`})
+
+	config, err := logs.FileConfig(filepath.Join(dir, "log.json"))
+	if err != nil {
+		test.Fatal(err)
+	}
+	config.LogHandler = func(logs.LogMessage) {}
+	logger := logs.New(config)
+	db := logger.ChildLogger("db")
+
+	watcher, err := logger.WatchConfigMap(dir, "log.json")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer watcher.Close()
+
+	writeConfigMap(test, dir, "2", map[string]string{"log.json": `{"level": "WARN", "loggers": {"db": {"level": "TRACE"}}}`})
+	os.RemoveAll(filepath.Join(dir, "..2026_10_17_1"))
+
+	waitForLevel(db, logs.Trace)
+	if logger.Level() != logs.Warn || db.Level() != logs.Trace {
+		test.Errorf("Expected the levels to be reloaded. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(db.Level()))
+	}
+
+	if _, err := logger.WatchConfigMap(dir, "../log.json"); err == nil {
+		test.Error("Expected an error for a key with a path")
+	}
+	if err := watcher.Close(); err != nil {
+		test.Error(err)
+	}
+}
@@ -0,0 +1,113 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DatadogHandlerConfig configures a Datadog logs handler
+type DatadogHandlerConfig struct {
+	// HTTPHandlerConfig configures batching and delivery. URL defaults to the
+	// logs intake for Site, and Encoder is ignored.
+	HTTPHandlerConfig
+	// APIKey is sent as the DD-API-KEY header
+	APIKey string
+	// Site is the Datadog site. Defaults to "datadoghq.com".
+	Site string
+	// Service, Source and Hostname are sent with every entry. Hostname defaults
+	// to os.Hostname().
+	Service  string
+	Source   string
+	Hostname string
+	// Tags are sent as ddtags with every entry, as "key:value" pairs
+	Tags map[string]string
+}
+
+// datadogStatuses maps log levels to Datadog statuses
+var datadogStatuses = map[LogLevel]string{
+	Trace: "debug",
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warn",
+	Error: "error",
+}
+
+// NewDatadogHandler returns an HTTPHandler that ships entries to Datadog's HTTP
+// logs intake. Entries with a "dd.trace_id" or "trace_id" field (and optionally
+// "dd.span_id" or "span_id") are correlated with APM traces.
+func NewDatadogHandler(config DatadogHandlerConfig) (*HTTPHandler, error) {
+	if len(config.APIKey) == 0 {
+		return nil, fmt.Errorf("Datadog handler requires an APIKey")
+	}
+	if len(config.Site) == 0 {
+		config.Site = "datadoghq.com"
+	}
+	if len(config.URL) == 0 {
+		config.URL = fmt.Sprintf("https://http-intake.logs.%s/api/v2/logs", config.Site)
+	}
+	if len(config.Hostname) == 0 {
+		config.Hostname, _ = os.Hostname()
+	}
+	config.Headers = cloneHeader(config.Headers)
+	config.Headers.Set("DD-API-KEY", config.APIKey)
+
+	tags := make([]string, 0, len(config.Tags))
+	for k, v := range config.Tags {
+		tags = append(tags, k+":"+v)
+	}
+	sort.Strings(tags)
+	ddtags := strings.Join(tags, ",")
+
+	config.Encoder = func(msg LogMessage) ([]byte, error) {
+		return datadogEncode(msg, config, ddtags)
+	}
+	return NewHTTPHandler(config.HTTPHandlerConfig)
+}
+
+// datadogEncode encodes an entry with Datadog's reserved attributes. Fields are
+// added as attributes.
+func datadogEncode(msg LogMessage, config DatadogHandlerConfig, ddtags string) ([]byte, error) {
+	entry := make(map[string]interface{}, len(msg.Fields)+10)
+	for k, v := range msg.Fields {
+		entry[k] = v
+	}
+
+	for _, k := range []string{"dd.trace_id", "trace_id"} {
+		if v, ok := msg.Fields[k]; ok {
+			entry["dd.trace_id"] = fmt.Sprint(v)
+			break
+		}
+	}
+	for _, k := range []string{"dd.span_id", "span_id"} {
+		if v, ok := msg.Fields[k]; ok {
+			entry["dd.span_id"] = fmt.Sprint(v)
+			break
+		}
+	}
+
+	status, ok := datadogStatuses[msg.Level]
+	if !ok {
+		status = strings.ToLower(msg.LevelLabel)
+	}
+
+	entry["message"] = msg.Message
+	entry["status"] = status
+	entry["timestamp"] = msg.Time.UnixNano() / 1e6
+	entry["logger.name"] = msg.Logger
+	if len(config.Service) > 0 {
+		entry["service"] = config.Service
+	}
+	if len(config.Source) > 0 {
+		entry["ddsource"] = config.Source
+	}
+	if len(config.Hostname) > 0 {
+		entry["hostname"] = config.Hostname
+	}
+	if len(ddtags) > 0 {
+		entry["ddtags"] = ddtags
+	}
+	return json.Marshal(entry)
+}
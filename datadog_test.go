@@ -0,0 +1,60 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestDatadogHandler(test *testing.T) {
+	var entries []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("DD-API-KEY") != "api-key" {
+			test.Errorf("Expected the DD-API-KEY header. Found: %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&entries)
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewDatadogHandler(logs.DatadogHandlerConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{URL: server.URL},
+		APIKey:            "api-key",
+		Service:           "billing",
+		Source:            "go",
+		Hostname:          "host-1",
+		Tags:              map[string]string{"env": "prod", "team": "payments"},
+	})
+	if nil != err {
+		test.Fatalf("Error creating Datadog handler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.SetField("trace_id", uint64(1234567890))
+	logger.Warn("A warn log message")
+	handler.Close()
+
+	if len(entries) != 1 {
+		test.Fatalf("Expected 1 entry. Found: %v", entries)
+	}
+	expected := map[string]interface{}{
+		"message":     "A warn log message",
+		"status":      "warn",
+		"service":     "billing",
+		"ddsource":    "go",
+		"hostname":    "host-1",
+		"ddtags":      "env:prod,team:payments",
+		"logger.name": "main",
+		"dd.trace_id": "1234567890",
+	}
+	for k, v := range expected {
+		if entries[0][k] != v {
+			test.Errorf("Expected %s to be %v. Found: %v", k, v, entries[0][k])
+		}
+	}
+}
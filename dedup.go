@@ -0,0 +1,114 @@
+package gologsgo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// dedupKey identifies duplicate entries
+type dedupKey struct {
+	logger  string
+	level   LogLevel
+	message string
+}
+
+type dedupEntry struct {
+	last    LogMessage
+	repeats int
+	timer   *time.Timer
+}
+
+// DedupHandler suppresses identical entries - the same logger, level and message -
+// within a window. The first entry is passed on immediately. Duplicates within the
+// window are counted, and when it ends the last of them is passed on once with its
+// message annotated with the repeat count and a "repeated" field, similar to
+// syslog's "last message repeated N times".
+type DedupHandler struct {
+	handler LogHandler
+	window  time.Duration
+	mu      sync.Mutex
+	entries map[dedupKey]*dedupEntry
+}
+
+// Dedup wraps handler in a DedupHandler with the given window
+func Dedup(handler LogHandler, window time.Duration) *DedupHandler {
+	return &DedupHandler{
+		handler: handler,
+		window:  window,
+		entries: make(map[dedupKey]*dedupEntry),
+	}
+}
+
+// LogHandler passes on or counts a LogMessage. It is a LogHandler.
+func (d *DedupHandler) LogHandler(msg LogMessage) {
+	key := dedupKey{logger: msg.Logger, level: msg.Level, message: msg.Message}
+
+	d.mu.Lock()
+	if entry, ok := d.entries[key]; ok {
+		entry.last = msg
+		entry.repeats++
+		d.mu.Unlock()
+		return
+	}
+	entry := &dedupEntry{}
+	entry.timer = time.AfterFunc(d.window, func() {
+		d.expire(key, entry)
+	})
+	d.entries[key] = entry
+	d.mu.Unlock()
+
+	d.handler(msg)
+}
+
+// Flush passes on the annotated entries for all pending duplicates and starts new
+// windows. It is part of FlushCloser.
+func (d *DedupHandler) Flush() error {
+	d.mu.Lock()
+	entries := d.entries
+	d.entries = make(map[dedupKey]*dedupEntry)
+	d.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.timer.Stop()
+		d.summarize(entry)
+	}
+	return nil
+}
+
+// Close flushes pending duplicates. It is part of FlushCloser.
+func (d *DedupHandler) Close() error {
+	return d.Flush()
+}
+
+// expire ends the window of an entry
+func (d *DedupHandler) expire(key dedupKey, entry *dedupEntry) {
+	d.mu.Lock()
+	if d.entries[key] != entry {
+		// Already flushed
+		d.mu.Unlock()
+		return
+	}
+	delete(d.entries, key)
+	d.mu.Unlock()
+
+	d.summarize(entry)
+}
+
+// summarize passes on the last duplicate of an entry, annotated with the repeat
+// count
+func (d *DedupHandler) summarize(entry *dedupEntry) {
+	if entry.repeats < 1 {
+		return
+	}
+
+	msg := entry.last
+	fields := make(map[string]interface{}, len(msg.Fields)+1)
+	for k, v := range msg.Fields {
+		fields[k] = v
+	}
+	fields["repeated"] = entry.repeats
+	msg.Fields = fields
+	msg.Message = fmt.Sprintf("%s (repeated %d times)", msg.Message, entry.repeats)
+	d.handler(msg)
+}
@@ -0,0 +1,52 @@
+package gologsgo_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestDedup(test *testing.T) {
+	var mu sync.Mutex
+	var received []logs.LogMessage
+	dedup := logs.Dedup(func(msg logs.LogMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, msg)
+	}, 30*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		dedup.LogHandler(logs.LogMessage{Logger: "db", Level: logs.Warn, Message: "Connection reset"})
+	}
+	dedup.LogHandler(logs.LogMessage{Logger: "db", Level: logs.Error, Message: "Connection reset"})
+
+	mu.Lock()
+	if len(received) != 2 {
+		test.Errorf("Expected the first of each distinct entry to pass immediately. Found: %v", received)
+	}
+	mu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for collapsed := 0; collapsed < 3 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		collapsed = len(received)
+		mu.Unlock()
+	}
+	mu.Lock()
+	if len(received) != 3 || received[2].Message != "Connection reset (repeated 4 times)" || received[2].Fields["repeated"] != 4 {
+		test.Errorf("Expected duplicates to collapse when the window ends. Found: %v", received)
+	}
+	mu.Unlock()
+
+	dedup.LogHandler(logs.LogMessage{Logger: "db", Level: logs.Warn, Message: "Connection reset"})
+	dedup.LogHandler(logs.LogMessage{Logger: "db", Level: logs.Warn, Message: "Connection reset"})
+	dedup.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 5 || received[4].Fields["repeated"] != 1 {
+		test.Errorf("Expected a new window after expiry, flushed by Close(). Found: %v", received)
+	}
+}
@@ -0,0 +1,83 @@
+package gologsgo
+
+// DetachSubtree creates an independent root Logger seeded from the subtree of
+// the named child (which may be a dotted path, as with ChildLogger()). The new
+// root has the child's label, level and fields, and a copy of the configuration
+// of it and its descendants, so loggers created from it have the same labels and
+// levels as before. It shares nothing with the original tree, so a long-running
+// background component can be given its own logging pipeline mid-process.
+//
+// config supplies the new root's LogHandler and Closers. Its Level and Loggers,
+// when set, override the seeded values. It may be nil.
+func (logger *Logger) DetachSubtree(name string, config *RootLogConfig) *Logger {
+	child := logger.ChildLogger(name)
+
+	childlock.Lock()
+	seed := child.snapshot()
+	childlock.Unlock()
+
+	root := &RootLogConfig{}
+	if nil != config {
+		*root = *config
+	}
+	root.Label = child.Label()
+	if root.Level == NotSet {
+		root.Level = seed.Level
+	}
+	if nil == root.Loggers {
+		root.Loggers = seed.Loggers
+	}
+	if nil == root.LabelFormat {
+		root.LabelFormat = child.labelFormat
+	}
+
+	detached := New(root)
+
+	fieldlock.RLock()
+	for k, f := range child.fields {
+		if nil == detached.fields {
+			detached.fields = make(map[string]field)
+		}
+		detached.fields[k] = f
+	}
+	fieldlock.RUnlock()
+
+	return detached
+}
+
+// snapshot deep copies the configuration of a Logger and its descendants,
+// including children created without explicit configuration. childlock must be
+// held.
+func (logger *Logger) snapshot() *LogConfig {
+	config := copyLogConfig(logger.logConfig)
+	config.Level = logger.Level()
+	for name, child := range logger.children {
+		if nil == config.Loggers {
+			config.Loggers = make(map[string]*LogConfig)
+		}
+		config.Loggers[name] = child.snapshot()
+	}
+	return config
+}
+
+// copyLogConfig deep copies a LogConfig
+func copyLogConfig(config *LogConfig) *LogConfig {
+	if nil == config {
+		return nil
+	}
+
+	c := &LogConfig{
+		Level:           config.Level,
+		Handler:         config.Handler,
+		LogHandler:      config.LogHandler,
+		Sample:          config.Sample,
+		RateLimitPerSec: config.RateLimitPerSec,
+	}
+	if nil != config.Loggers {
+		c.Loggers = make(map[string]*LogConfig, len(config.Loggers))
+		for name, child := range config.Loggers {
+			c.Loggers[name] = copyLogConfig(child)
+		}
+	}
+	return c
+}
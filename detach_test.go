@@ -0,0 +1,59 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestDetachSubtree(test *testing.T) {
+	jsonCfg, err := logs.JsonConfig([]byte(`
+	{ "level": "INFO",
+	  "label": "main",
+	  "loggers": {
+	    "worker": {
+	      "level": "WARN",
+	      "loggers": {
+	        "jobs": { "level": "DEBUG" }
+	      }
+	    }
+	  }
+	}
+`))
+	if nil != err {
+		test.Fatalf("Error preparing RootLogConfig: %s", err)
+	}
+	var original []logs.LogMessage
+	jsonCfg.LogHandler = func(msg logs.LogMessage) {
+		original = append(original, msg)
+	}
+	root := logs.New(jsonCfg)
+	root.ChildLogger("worker").SetField("pool", "a")
+	root.ChildLogger("worker.jobs.cleanup")
+
+	var detached []logs.LogMessage
+	worker := root.DetachSubtree("worker", &logs.RootLogConfig{
+		LogHandler: func(msg logs.LogMessage) {
+			detached = append(detached, msg)
+		},
+	})
+
+	if worker.Label() != "main.worker" || worker.Level() != logs.Warn {
+		test.Errorf("Expected detached root main.worker at WARN. Found: %s at %v", worker.Label(), worker.Level())
+	}
+	cleanup := worker.ChildLogger("jobs.cleanup")
+	if cleanup.Label() != "main.worker.jobs.cleanup" || cleanup.Level() != logs.Debug {
+		test.Errorf("Expected main.worker.jobs.cleanup at DEBUG. Found: %s at %v", cleanup.Label(), cleanup.Level())
+	}
+
+	cleanup.Debug("Cleaning up")
+	if len(detached) != 1 || len(original) != 0 {
+		test.Fatalf("Expected records to go only to the detached handler. Found %d detached, %d original", len(detached), len(original))
+	}
+	if detached[0].Fields["pool"] != "a" {
+		test.Errorf("Expected fields to be copied to the detached root. Found: %v", detached[0].Fields)
+	}
+	if root.ChildLogger("worker") == worker {
+		test.Error("Expected the detached root to be independent of the original tree")
+	}
+}
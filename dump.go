@@ -0,0 +1,165 @@
+package gologsgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigDump is the resolved configuration of a live Logger and its descendants,
+// as returned by DumpConfig()
+type ConfigDump struct {
+	// Label is the label of the Logger
+	Label string `json:"label"`
+	// Level is the effective level of the Logger
+	Level string `json:"level"`
+	// LevelSource is where the level comes from: "root config", "config" for the
+	// Logger's entry in its parent's Loggers, `pattern "http.*"`, `rule "sql$"`,
+	// "temporary until <time>" for SetLevelFor(), or "inherited from <label>"
+	LevelSource string `json:"levelSource"`
+	// Handler describes the handler the Logger's entries are written to: the
+	// name of a registered handler, the types of the outputs of Handlers, a
+	// preset, "LogHandler" or "LogHandlerE" for handlers set in code, or
+	// "default"
+	Handler string `json:"handler"`
+	// HandlerSource is "config", or "inherited from <label>"
+	HandlerSource string `json:"handlerSource"`
+	// Loggers are the child loggers that have been created, by name
+	Loggers map[string]*ConfigDump `json:"loggers,omitempty"`
+}
+
+// DumpConfig returns the resolved configuration of the Logger and of the
+// descendants that have been created from it, with where their levels and
+// handlers come from, for debugging why a logger is, or isn't, logging. Marshal
+// it with encoding/json, or print it as an indented tree:
+//
+//	fmt.Print(logger.DumpConfig())
+func (logger *Logger) DumpConfig() *ConfigDump {
+	if nil != logger.base {
+		return logger.base.DumpConfig()
+	}
+	childlock.Lock()
+	defer childlock.Unlock()
+	return logger.dump()
+}
+
+// dump describes a Logger and its descendants. childlock must be held.
+func (logger *Logger) dump() *ConfigDump {
+	d := &ConfigDump{
+		Label:       logger.label,
+		Level:       LogLevels.Label(logger.Level()),
+		LevelSource: logger.levelSource(),
+	}
+	for l := logger; nil != l; l = l.parent {
+		if len(l.handler) > 0 || nil == l.parent {
+			d.Handler = l.handler
+			d.HandlerSource = "config"
+			if l != logger {
+				d.HandlerSource = "inherited from " + l.displayLabel()
+			}
+			break
+		}
+	}
+
+	for name, child := range logger.children {
+		if nil == d.Loggers {
+			d.Loggers = make(map[string]*ConfigDump, len(logger.children))
+		}
+		d.Loggers[name] = child.dump()
+	}
+	return d
+}
+
+// levelSource describes where the level of a Logger comes from. childlock must
+// be held.
+func (logger *Logger) levelSource() string {
+	if nil != logger.temporary {
+		return fmt.Sprintf("temporary until %s", logger.temporary.expires.Format(time.RFC3339))
+	}
+	if nil == logger.parent {
+		return "root config"
+	}
+	if logger.logConfig.Level != NotSet {
+		if _, pattern := logger.parent.lookupChildConfig(logger.name); len(pattern) > 0 {
+			return fmt.Sprintf("pattern %q", pattern)
+		}
+		return "config"
+	}
+	if rule, ok := logger.parent.matchRule(logger.name); ok {
+		return fmt.Sprintf("rule %q", rule.match.String())
+	}
+	return "inherited from " + logger.parent.displayLabel()
+}
+
+// displayLabel returns the label of a Logger, or "root" for a root Logger
+// without one
+func (logger *Logger) displayLabel() string {
+	if len(logger.label) == 0 && nil == logger.parent {
+		return "root"
+	}
+	return logger.label
+}
+
+// String renders the dump as an indented tree, one logger per line:
+//
+//	app: INFO (root config), handler json-stderr (config)
+//	  db: DEBUG (config), handler json-stderr (inherited from app)
+func (d *ConfigDump) String() string {
+	var b strings.Builder
+	label := d.Label
+	if len(label) == 0 {
+		label = "root"
+	}
+	d.write(&b, label, 0)
+	return b.String()
+}
+
+func (d *ConfigDump) write(b *strings.Builder, name string, depth int) {
+	fmt.Fprintf(b, "%s%s: %s (%s), handler %s (%s)\n", strings.Repeat("  ", depth), name, d.Level, d.LevelSource, d.Handler, d.HandlerSource)
+
+	names := make([]string, 0, len(d.Loggers))
+	for n := range d.Loggers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		d.Loggers[n].write(b, n, depth+1)
+	}
+}
+
+// describeRootHandler describes the handler a root config selects
+func describeRootHandler(config *RootLogConfig) string {
+	// JsonConfig() resolves Handler and Handlers to a LogHandler, so they are
+	// described first
+	switch {
+	case nil != config.LogHandlerE:
+		return "LogHandlerE"
+	case nil != config.Handler:
+		return config.Handler.Name
+	case len(config.Handlers) > 0:
+		types := make([]string, len(config.Handlers))
+		for i, output := range config.Handlers {
+			types[i] = output.Type
+		}
+		return "handlers " + strings.Join(types, ", ")
+	case nil != config.LogHandler:
+		return "LogHandler"
+	case len(config.Preset) > 0:
+		return "preset " + config.Preset
+	default:
+		return "default"
+	}
+}
+
+// describeChildHandler describes the handler a child config selects, if any
+func describeChildHandler(config *LogConfig) string {
+	switch {
+	case nil != config.Handler:
+		return config.Handler.Name
+	case nil != config.LogHandler:
+		return "LogHandler"
+	default:
+		return ""
+	}
+}
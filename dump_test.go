@@ -0,0 +1,53 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestDumpConfig(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(`{
+		"label": "app",
+		"level": "INFO",
+		"handler": "json-stderr",
+		"rules": [{"match": "sql$", "level": "DEBUG"}],
+		"loggers": {
+			"db": {"level": "WARN", "handler": "text-stderr"},
+			"http.*": {"level": "ERROR"}
+		}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	logger := logs.New(config)
+	logger.ChildLogger("db.sql")
+	logger.ChildLogger("db.pool")
+	logger.ChildLogger("http.client")
+	logger.ChildLogger("jobs").SetLevelFor(logs.Trace, time.Hour)
+
+	dump := logger.DumpConfig()
+	expected := strings.Join([]string{
+		`app: INFO (root config), handler json-stderr (config)`,
+		`  db: WARN (config), handler text-stderr (config)`,
+		`    pool: WARN (inherited from app.db), handler text-stderr (inherited from app.db)`,
+		`    sql: DEBUG (rule "sql$"), handler text-stderr (inherited from app.db)`,
+		`  http: INFO (inherited from app), handler json-stderr (inherited from app)`,
+		`    client: ERROR (pattern "http.*"), handler json-stderr (inherited from app)`,
+		`  jobs: TRACE (temporary until `,
+	}, "\n")
+	if found := dump.String(); !strings.HasPrefix(found, expected) {
+		test.Errorf("Expected:\n%s\nFound:\n%s", expected, found)
+	}
+
+	data, err := json.Marshal(dump.Loggers["db"].Loggers["sql"])
+	if err != nil {
+		test.Fatal(err)
+	}
+	if string(data) != `{"label":"app.db.sql","level":"DEBUG","levelSource":"rule \"sql$\"","handler":"text-stderr","handlerSource":"inherited from app.db"}` {
+		test.Errorf("Unexpected JSON: %s", data)
+	}
+}
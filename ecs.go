@@ -0,0 +1,44 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ecsVersion is the version of the Elastic Common Schema ECSEncoder follows
+const ecsVersion = "8.11.0"
+
+func init() {
+	RegisterFormat("ecs", FormatTraits{})
+}
+
+// ECSEncoder encodes a LogMessage as an Elastic Common Schema (ECS) document, with
+// `@timestamp`, `log.level`, `log.logger`, `message`, `ecs.version` and, when a
+// stack was captured, `error.stack_trace`, so entries land in Elasticsearch
+// dashboards and detections without an ingest pipeline remap. Fields are added at
+// the top level and can't replace the standard keys; use ECS names (such as
+// `trace.id` or `user.name`) for them to be recognized.
+func ECSEncoder(msg LogMessage) ([]byte, error) {
+	doc := make(map[string]interface{}, len(msg.Fields)+5)
+	for k, v := range msg.Fields {
+		doc[k] = v
+	}
+
+	log := map[string]interface{}{
+		"level": strings.ToLower(msg.LevelLabel),
+	}
+	if len(msg.Logger) > 0 {
+		log["logger"] = msg.Logger
+	}
+	doc["@timestamp"] = msg.Time.UTC().Format(time.RFC3339Nano)
+	doc["log"] = log
+	doc["message"] = msg.Message
+	doc["ecs"] = map[string]string{"version": ecsVersion}
+	if len(msg.Stack) > 0 {
+		doc["error"] = map[string]interface{}{
+			"stack_trace": formatStack(msg.Stack),
+		}
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,52 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestECSEncoder(test *testing.T) {
+	data, err := logs.ECSEncoder(logs.LogMessage{
+		Level:      logs.Error,
+		LevelLabel: "ERROR",
+		Logger:     "main.db",
+		Message:    "Query failed",
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC),
+		Fields:     map[string]interface{}{"trace.id": "abc", "message": "ignored"},
+		Stack:      []logs.StackFrame{{Function: "main.query", File: "/src/main.go", Line: 42}},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var doc struct {
+		Timestamp string `json:"@timestamp"`
+		Log       struct {
+			Level  string `json:"level"`
+			Logger string `json:"logger"`
+		} `json:"log"`
+		Message string `json:"message"`
+		ECS     struct {
+			Version string `json:"version"`
+		} `json:"ecs"`
+		Error struct {
+			StackTrace string `json:"stack_trace"`
+		} `json:"error"`
+		TraceID string `json:"trace.id"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		test.Fatalf("Unable to parse %q as JSON: %s", data, err)
+	}
+
+	if doc.Timestamp != "2020-01-02T03:04:05.006Z" || doc.Log.Level != "error" || doc.Log.Logger != "main.db" ||
+		doc.Message != "Query failed" || len(doc.ECS.Version) == 0 || doc.TraceID != "abc" {
+		test.Errorf("Unexpected ECS document: %s", data)
+	}
+	if !strings.Contains(doc.Error.StackTrace, "main.query\n\t/src/main.go:42") {
+		test.Errorf("Expected error.stack_trace. Found: %q", doc.Error.StackTrace)
+	}
+}
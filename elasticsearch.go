@@ -0,0 +1,132 @@
+package gologsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ElasticsearchHandlerConfig configures an Elasticsearch (or OpenSearch) handler
+type ElasticsearchHandlerConfig struct {
+	// HTTPHandlerConfig configures batching and delivery. URL is the base URL of
+	// the cluster (for example http://elasticsearch:9200); the bulk API path is
+	// added if the URL has no path. Use Headers for authentication. Encoder
+	// produces each document and defaults to LogstashEncoder(), which includes
+	// an @timestamp field.
+	HTTPHandlerConfig
+	// IndexPrefix names the indices entries are written to. Defaults to "logs".
+	IndexPrefix string
+	// IndexDateLayout is a time layout appended to IndexPrefix (using each
+	// entry's time, in UTC) to roll over to a new index every day. Defaults to
+	// "2006.01.02", for indices like logs-2020.01.02.
+	IndexDateLayout string
+}
+
+// NewElasticsearchHandler returns an HTTPHandler that writes entries to
+// Elasticsearch or OpenSearch with the bulk API, in daily indices. Rejected
+// requests (429) are retried with backoff. When only some documents of a batch
+// fail, the rest are indexed: those rejected with 429 are retried on their own,
+// and the others are reported to OnDeliveryFailure.
+func NewElasticsearchHandler(config ElasticsearchHandlerConfig) (*HTTPHandler, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Elasticsearch URL: %s", err)
+	}
+	if len(strings.Trim(u.Path, "/")) == 0 {
+		u.Path = "/_bulk"
+		config.URL = u.String()
+	}
+	if len(config.IndexPrefix) == 0 {
+		config.IndexPrefix = "logs"
+	}
+	if len(config.IndexDateLayout) == 0 {
+		config.IndexDateLayout = "2006.01.02"
+	}
+	if nil == config.Encoder {
+		config.Encoder = LogstashEncoder()
+	}
+	config.Headers = cloneHeader(config.Headers)
+	config.Headers.Set("Content-Type", "application/x-ndjson")
+
+	h, err := NewHTTPHandler(config.HTTPHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+	h.body = func(batch []LogMessage) ([]byte, error) {
+		return elasticsearchBulkBody(batch, config)
+	}
+	h.response = elasticsearchBulkResponse
+	return h, nil
+}
+
+// elasticsearchBulkBody builds a bulk API request: an action line and a document
+// line for each entry
+func elasticsearchBulkBody(batch []LogMessage, config ElasticsearchHandlerConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, msg := range batch {
+		doc, err := config.Encoder(msg)
+		if err != nil {
+			return nil, err
+		}
+		index := config.IndexPrefix + "-" + msg.Time.UTC().Format(config.IndexDateLayout)
+		action, err := json.Marshal(map[string]map[string]string{
+			"create": {"_index": index},
+		})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(doc)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// elasticsearchBulkResponse reports the documents that failed in a bulk
+// response, as a partialFailure. Documents rejected due to backpressure (429)
+// are retried.
+func elasticsearchBulkResponse(body []byte) (bool, error) {
+	var response struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return false, fmt.Errorf("Unable to parse bulk response: %s", err)
+	}
+	if !response.Errors {
+		return false, nil
+	}
+
+	partial := &partialFailure{}
+	var reason string
+	for i, item := range response.Items {
+		for _, result := range item {
+			if result.Status >= 200 && result.Status < 300 {
+				continue
+			}
+			if result.Status == 429 {
+				partial.retry = append(partial.retry, i)
+			} else {
+				partial.failed = append(partial.failed, i)
+			}
+			if len(reason) == 0 {
+				reason = fmt.Sprintf("%s: %s", result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+	if len(partial.retry) == 0 && len(partial.failed) == 0 {
+		return false, nil
+	}
+	failed := len(partial.retry) + len(partial.failed)
+	partial.err = fmt.Errorf("%d of %d documents failed to index, the first with %s", failed, len(response.Items), reason)
+	return false, partial
+}
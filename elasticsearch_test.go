@@ -0,0 +1,129 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestElasticsearchHandler(test *testing.T) {
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			test.Errorf("Unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		// Reject the second document
+		w.Write([]byte(`{"errors": true, "items": [
+			{"create": {"status": 201}},
+			{"create": {"status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse"}}}
+		]}`))
+	}))
+	defer server.Close()
+
+	var failure error
+	handler, err := logs.NewElasticsearchHandler(logs.ElasticsearchHandlerConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{
+			URL: server.URL,
+			OnDeliveryFailure: func(batch []logs.LogMessage, err error) {
+				failure = err
+			},
+		},
+	})
+	if nil != err {
+		test.Fatalf("Error creating ElasticsearchHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("First")
+	logger.Info("Second")
+	handler.Close()
+
+	if len(lines) != 4 {
+		test.Fatalf("Expected an action and document line per entry. Found: %v", lines)
+	}
+	var action map[string]map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &action); nil != err {
+		test.Fatalf("Unable to parse action %q: %s", lines[0], err)
+	}
+	if !strings.HasPrefix(action["create"]["_index"], "logs-") {
+		test.Errorf("Expected a daily logs index. Found: %v", action)
+	}
+	if !strings.Contains(lines[1], `"message":"First"`) {
+		test.Errorf("Unexpected document: %s", lines[1])
+	}
+	if nil == failure || !strings.Contains(failure.Error(), "1 of 2 documents failed") {
+		test.Errorf("Expected the rejected document to be reported. Found: %v", failure)
+	}
+}
+
+func TestElasticsearchHandlerRetriesRejectedDocuments(test *testing.T) {
+	var requests [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var lines []string
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		requests = append(requests, lines)
+		if len(requests) > 1 {
+			w.Write([]byte(`{"errors": false, "items": [{"create": {"status": 201}}]}`))
+			return
+		}
+		// Index the first document, push back on the second and reject the third
+		w.Write([]byte(`{"errors": true, "items": [
+			{"create": {"status": 201}},
+			{"create": {"status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "queue full"}}},
+			{"create": {"status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse"}}}
+		]}`))
+	}))
+	defer server.Close()
+
+	var failed []logs.LogMessage
+	handler, err := logs.NewElasticsearchHandler(logs.ElasticsearchHandlerConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{
+			URL:        server.URL,
+			MinBackoff: time.Millisecond,
+			OnDeliveryFailure: func(batch []logs.LogMessage, err error) {
+				failed = append(failed, batch...)
+			},
+		},
+	})
+	if nil != err {
+		test.Fatalf("Error creating ElasticsearchHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("Indexed")
+	logger.Info("Pushed back")
+	logger.Info("Rejected")
+	handler.Close()
+
+	if len(requests) != 2 {
+		test.Fatalf("Expected the pushed back document to be retried. Found: %v", requests)
+	}
+	if len(requests[1]) != 2 || !strings.Contains(requests[1][1], `"message":"Pushed back"`) {
+		test.Errorf("Expected only the pushed back document to be retried. Found: %v", requests[1])
+	}
+	if len(failed) != 1 || failed[0].Message != "Rejected" {
+		test.Errorf("Expected only the rejected document to be reported. Found: %v", failed)
+	}
+	if handler.Dropped() != 1 {
+		test.Errorf("Expected 1 dropped entry. Found: %d", handler.Dropped())
+	}
+}
@@ -0,0 +1,127 @@
+package gologsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Encoder serializes a LogMessage for handlers that write to a byte stream or
+// remote collector instead of the terminal. Encoders should not add a trailing
+// newline - framing is the responsibility of the handler.
+type Encoder func(LogMessage) ([]byte, error)
+
+// jsonMessage is the wire format written by JSONEncoder
+type jsonMessage struct {
+	Time    string                 `json:"time"`
+	Level   string                 `json:"level"`
+	Logger  string                 `json:"logger,omitempty"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// JSONEncoder encodes a LogMessage as a single line JSON object with `time`,
+// `level`, `logger`, `message` and (if there are any) `fields` keys.
+func JSONEncoder(msg LogMessage) ([]byte, error) {
+	return json.Marshal(jsonMessage{
+		Time:    msg.Time.Format(time.RFC3339Nano),
+		Level:   levelLabel(msg),
+		Logger:  msg.Logger,
+		Message: msg.Message,
+		Fields:  msg.Fields,
+	})
+}
+
+// jsonKeys are the keys written by JSONEncoder, in order
+var jsonKeys = []string{"time", "level", "logger", "message", "fields"}
+
+// JSONEncoderWithKeys returns an Encoder like JSONEncoder that renames its keys,
+// so output matches the schema expected downstream without a transform step. For
+// example, {"level": "severity", "message": "msg"} suits Google Cloud Logging.
+// Keys that aren't renamed keep their names.
+func JSONEncoderWithKeys(keys map[string]string) (Encoder, error) {
+	names := make([]string, len(jsonKeys))
+	seen := make(map[string]bool, len(jsonKeys))
+	for i, key := range jsonKeys {
+		names[i] = key
+		if name, ok := keys[key]; ok {
+			if len(name) == 0 {
+				return nil, fmt.Errorf("The %q key can't be renamed to an empty name", key)
+			}
+			names[i] = name
+		}
+		if seen[names[i]] {
+			return nil, fmt.Errorf("More than one key is named %q", names[i])
+		}
+		seen[names[i]] = true
+	}
+	for key := range keys {
+		if !containsString(jsonKeys, key) {
+			return nil, fmt.Errorf("Unknown key %q. Keys that can be renamed are: %s", key, strings.Join(jsonKeys, ", "))
+		}
+	}
+
+	// Encode the names once, escaped and followed by a colon
+	prefixes := make([][]byte, len(names))
+	for i, name := range names {
+		encoded, _ := json.Marshal(name)
+		prefixes[i] = append(encoded, ':')
+	}
+
+	return func(msg LogMessage) ([]byte, error) {
+		values := []interface{}{
+			msg.Time.Format(time.RFC3339Nano),
+			levelLabel(msg),
+			msg.Logger,
+			msg.Message,
+			msg.Fields,
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, v := range values {
+			// logger and fields are omitted when empty, like JSONEncoder
+			if (i == 2 && len(msg.Logger) == 0) || (i == 4 && len(msg.Fields) == 0) {
+				continue
+			}
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			if buf.Len() > 1 {
+				buf.WriteByte(',')
+			}
+			buf.Write(prefixes[i])
+			buf.Write(data)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	}, nil
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// TextEncoder encodes a LogMessage as plain, uncolored text in the same layout
+// the DefaultLogHandler uses, preceded by an RFC3339 timestamp and followed by
+// any fields as key=value pairs.
+func TextEncoder(msg LogMessage) ([]byte, error) {
+	ts := msg.Time.Format(time.RFC3339Nano)
+	level := levelLabel(msg)
+	message := msg.Message
+	if len(msg.Fields) > 0 {
+		message = message + " " + formatFields(msg.Fields)
+	}
+	if len(msg.Logger) == 0 {
+		return []byte(fmt.Sprintf("%s %s: %s", ts, level, message)), nil
+	}
+	return []byte(fmt.Sprintf("%s %s [%s]: %s", ts, level, msg.Logger, message)), nil
+}
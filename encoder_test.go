@@ -0,0 +1,55 @@
+package gologsgo_test
+
+import (
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestJSONEncoderWithKeys(test *testing.T) {
+	msg := logs.LogMessage{
+		Level:      logs.Warn,
+		LevelLabel: "warn",
+		Logger:     "api",
+		Message:    "Slow request",
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Fields:     map[string]interface{}{"ms": 1200},
+	}
+
+	// With no renames, the output matches JSONEncoder
+	encode, err := logs.JSONEncoderWithKeys(nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+	expected, _ := logs.JSONEncoder(msg)
+	if actual, _ := encode(msg); string(actual) != string(expected) {
+		test.Errorf("Expected %s. Found: %s", expected, actual)
+	}
+
+	encode, err = logs.JSONEncoderWithKeys(map[string]string{"level": "severity", "message": "msg", "time": "ts"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	actual, _ := encode(msg)
+	if string(actual) != `{"ts":"2020-01-02T03:04:05Z","severity":"WARN","logger":"api","msg":"Slow request","fields":{"ms":1200}}` {
+		test.Errorf("Unexpected output: %s", actual)
+	}
+
+	for _, keys := range []map[string]string{
+		{"severity": "level"},
+		{"level": ""},
+		{"level": "message"},
+	} {
+		if _, err := logs.JSONEncoderWithKeys(keys); err == nil {
+			test.Errorf("Expected an error for %v", keys)
+		}
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"handler": {"name": "json-stdout", "options": {"keys": {"level": "severity"}}}}`)); err != nil {
+		test.Error(err)
+	}
+	if _, err := logs.JsonConfig([]byte(`{"handlers": [{"type": "stdout", "format": "text", "keys": {"level": "severity"}}]}`)); err == nil {
+		test.Error("Expected an error for keys with the text format")
+	}
+}
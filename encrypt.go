@@ -0,0 +1,241 @@
+package gologsgo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// encryptedPrefix starts every record produced by EncryptingEncoder, so encrypted
+// and plain records can be told apart in the same stream
+const encryptedPrefix = "enc:v1:"
+
+// DataKey is a 256 bit AES key used to encrypt records, and the ID that is
+// written alongside each record to find the key again for decryption
+type DataKey struct {
+	ID  string
+	Key []byte
+}
+
+// KeyProvider supplies the data key records are encrypted with, and finds keys by
+// ID for decryption. It is the integration point for key management systems: a
+// KMS backed provider can generate a data key, use the KMS wrapped (encrypted)
+// copy of it as the ID, and unwrap IDs with the KMS in Key(). IDs may not contain
+// a colon.
+type KeyProvider interface {
+	CurrentKey() (DataKey, error)
+	Key(id string) (DataKey, error)
+}
+
+// StaticKeys is a KeyProvider for keys held in configuration. Keeping retired keys
+// in Keys allows older records to be decrypted after rotating Current.
+type StaticKeys struct {
+	// Current is the ID of the key new records are encrypted with
+	Current string
+	// Keys maps key IDs to 32 byte keys
+	Keys map[string][]byte
+}
+
+// CurrentKey returns the key new records are encrypted with
+func (s StaticKeys) CurrentKey() (DataKey, error) {
+	return s.Key(s.Current)
+}
+
+// Key returns the key with the given ID
+func (s StaticKeys) Key(id string) (DataKey, error) {
+	key, ok := s.Keys[id]
+	if !ok {
+		return DataKey{}, fmt.Errorf("Unknown encryption key %q", id)
+	}
+	return DataKey{ID: id, Key: key}, nil
+}
+
+// gcmCache avoids rebuilding the cipher for every record
+type gcmCache struct {
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD
+}
+
+func (c *gcmCache) aead(key DataKey) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cacheKey := key.ID + ":" + string(key.Key)
+	if aead, ok := c.aeads[cacheKey]; ok {
+		return aead, nil
+	}
+
+	if len(key.Key) != 32 {
+		return nil, fmt.Errorf("Encryption key %q must be 32 bytes. Found: %d", key.ID, len(key.Key))
+	}
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if nil == c.aeads {
+		c.aeads = make(map[string]cipher.AEAD)
+	}
+	c.aeads[cacheKey] = aead
+	return aead, nil
+}
+
+// EncryptingEncoder wraps an Encoder so each record is encrypted with AES-GCM
+// using the KeyProvider's current key. Encrypted records are single line text -
+// "enc:v1:<key id>:<base64 nonce and ciphertext>" - so they can be written with
+// NewlineFraming to files or any network handler. Use DecryptRecord, or the
+// `gologsgo decrypt` command, to read them.
+func EncryptingEncoder(encoder Encoder, keys KeyProvider) Encoder {
+	cache := &gcmCache{}
+	return func(msg LogMessage) ([]byte, error) {
+		plaintext, err := encoder(msg)
+		if err != nil {
+			return nil, err
+		}
+		return encryptRecord(plaintext, keys, cache)
+	}
+}
+
+func encryptRecord(plaintext []byte, keys KeyProvider, cache *gcmCache) ([]byte, error) {
+	key, err := keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(key.ID, ":") {
+		return nil, fmt.Errorf("Encryption key IDs may not contain a colon: %q", key.ID)
+	}
+
+	aead, err := cache.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+
+	record := make([]byte, 0, len(encryptedPrefix)+len(key.ID)+1+base64.StdEncoding.EncodedLen(len(sealed)))
+	record = append(record, encryptedPrefix...)
+	record = append(record, key.ID...)
+	record = append(record, ':')
+	return append(record, base64.StdEncoding.EncodeToString(sealed)...), nil
+}
+
+// EncryptingWriter encrypts newline delimited entries written to it - by
+// WriterHandler, BufferedHandler or any other handler that writes to an
+// io.Writer - before writing them to the underlying Writer, so log content is
+// protected at rest. Each line is encrypted as a separate record, in the same
+// format as EncryptingEncoder.
+type EncryptingWriter struct {
+	w       io.Writer
+	keys    KeyProvider
+	cache   gcmCache
+	mu      sync.Mutex
+	partial []byte
+}
+
+// NewEncryptingWriter returns an EncryptingWriter that writes to w with keys
+func NewEncryptingWriter(w io.Writer, keys KeyProvider) *EncryptingWriter {
+	return &EncryptingWriter{w: w, keys: keys}
+}
+
+// Write encrypts each complete line in p. An incomplete final line is held until
+// it is completed by a later Write, or until Close().
+func (e *EncryptingWriter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data := append(e.partial, p...)
+	e.partial = nil
+	var out []byte
+	for {
+		newline := bytes.IndexByte(data, '\n')
+		if newline < 0 {
+			break
+		}
+		if newline > 0 {
+			record, err := encryptRecord(data[:newline], e.keys, &e.cache)
+			if err != nil {
+				return 0, err
+			}
+			out = append(append(out, record...), '\n')
+		}
+		data = data[newline+1:]
+	}
+	if len(data) > 0 {
+		e.partial = append([]byte(nil), data...)
+	}
+
+	if len(out) > 0 {
+		if _, err := e.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close encrypts any incomplete final line and closes the underlying Writer if it
+// is an io.Closer
+func (e *EncryptingWriter) Close() error {
+	e.mu.Lock()
+	partial := e.partial
+	e.partial = nil
+	e.mu.Unlock()
+
+	if len(partial) > 0 {
+		if _, err := e.Write(append(partial, '\n')); err != nil {
+			return err
+		}
+	}
+	if closer, ok := e.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// IsEncryptedRecord reports whether a record was produced by EncryptingEncoder
+func IsEncryptedRecord(record []byte) bool {
+	return bytes.HasPrefix(record, []byte(encryptedPrefix))
+}
+
+// DecryptRecord decrypts a record produced by EncryptingEncoder, finding its key
+// with the KeyProvider
+func DecryptRecord(record []byte, keys KeyProvider) ([]byte, error) {
+	if !IsEncryptedRecord(record) {
+		return nil, fmt.Errorf("Not an encrypted record")
+	}
+
+	parts := strings.SplitN(string(bytes.TrimSpace(record[len(encryptedPrefix):])), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Malformed encrypted record")
+	}
+
+	key, err := keys.Key(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("Malformed encrypted record: %s", err)
+	}
+
+	aead, err := (&gcmCache{}).aead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("Malformed encrypted record: too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+}
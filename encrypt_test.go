@@ -0,0 +1,80 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestEncryptingEncoder(test *testing.T) {
+	keys := logs.StaticKeys{
+		Current: "2020-01",
+		Keys: map[string][]byte{
+			"2020-01": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	var records [][]byte
+	encode := logs.EncryptingEncoder(logs.TextEncoder, keys)
+	logger := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		LogHandler: func(msg logs.LogMessage) {
+			record, err := encode(msg)
+			if nil != err {
+				test.Fatalf("Error encrypting record: %s", err)
+			}
+			records = append(records, record)
+		},
+	})
+	logger.Info("Card number 4111111111111111")
+
+	record := records[0]
+	if !strings.HasPrefix(string(record), "enc:v1:2020-01:") || bytes.Contains(record, []byte("4111")) {
+		test.Fatalf("Expected an encrypted record. Found: %s", record)
+	}
+
+	plaintext, err := logs.DecryptRecord(record, keys)
+	if nil != err {
+		test.Fatalf("Error decrypting record: %s", err)
+	}
+	if !strings.HasSuffix(string(plaintext), "INFO [main]: Card number 4111111111111111") {
+		test.Errorf("Unexpected decrypted record: %s", plaintext)
+	}
+
+	wrongKeys := logs.StaticKeys{Keys: map[string][]byte{"2020-01": bytes.Repeat([]byte{2}, 32)}}
+	if _, err := logs.DecryptRecord(record, wrongKeys); nil == err {
+		test.Error("Expected an error decrypting with the wrong key")
+	}
+}
+
+func TestEncryptingWriter(test *testing.T) {
+	keys := logs.StaticKeys{
+		Current: "k1",
+		Keys:    map[string][]byte{"k1": bytes.Repeat([]byte{3}, 32)},
+	}
+
+	var out bytes.Buffer
+	writer := logs.NewEncryptingWriter(&out, keys)
+	handler := logs.WriterHandler(writer, logs.JSONEncoder)
+	handler(logs.LogMessage{Level: logs.Info, Message: "secret one"})
+	handler(logs.LogMessage{Level: logs.Info, Message: "secret two"})
+	writer.Write([]byte("partial"))
+	writer.Close()
+
+	if bytes.Contains(out.Bytes(), []byte("secret")) || bytes.Contains(out.Bytes(), []byte("partial")) {
+		test.Fatalf("Expected only encrypted records. Found: %s", out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		test.Fatalf("Expected one record per line. Found: %q", lines)
+	}
+	plaintext, err := logs.DecryptRecord([]byte(lines[1]), keys)
+	if err != nil || !strings.Contains(string(plaintext), "secret two") {
+		test.Errorf("Expected to decrypt the second entry. Found: %s, %v", plaintext, err)
+	}
+	if plaintext, _ := logs.DecryptRecord([]byte(lines[2]), keys); string(plaintext) != "partial" {
+		test.Errorf("Expected Close() to encrypt the incomplete line. Found: %s", plaintext)
+	}
+}
@@ -0,0 +1,307 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// envPrefixMap builds the JSON object described by the environment variables
+// that start with prefix. All the variables that can't be parsed are returned as
+// ConfigErrors. See EnvPrefixConfig().
+func envPrefixMap(prefix string) (map[string]interface{}, error) {
+	return envMap(prefix, os.Environ())
+}
+
+// envMap builds the JSON object described by the variables in environ, a list of
+// name=value pairs, that start with prefix
+func envMap(prefix string, environ []string) (map[string]interface{}, error) {
+	cfg := make(map[string]interface{})
+	var errs ConfigErrors
+	var output envOutput
+	fullprefix := prefix + "_"
+
+	// Variables are applied in order of their names, so the result doesn't
+	// depend on the order of the environment. The variable matching the prefix
+	// exactly sorts first, so the others override it.
+	environ = append([]string{}, environ...)
+	sort.Strings(environ)
+	for _, envpair := range environ {
+		pair := strings.SplitN(envpair, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		envname, envvalue := pair[0], pair[1]
+
+		// Support JSON in the environment variable matching the prefix exactly
+		if strings.EqualFold(envname, prefix) {
+			if len(strings.TrimSpace(envvalue)) == 0 {
+				continue
+			}
+			root := make(map[string]interface{})
+			if err := json.Unmarshal([]byte(envvalue), &root); err != nil {
+				errs.add(envname, "Unable to parse as a JSON object. %s", err)
+				continue
+			}
+			mergeMaps(cfg, root)
+			continue
+		}
+		if len(envname) <= len(fullprefix) || !strings.EqualFold(envname[:len(fullprefix)], fullprefix) {
+			continue
+		}
+
+		envkeys := strings.Split(envname[len(fullprefix):], "__")
+		if len(envkeys) == 1 && output.set(envKey(envkeys[0]), envname, envvalue) {
+			continue
+		}
+		if err := setEnv(cfg, envkeys, envvalue); err != nil {
+			errs.add(envname, "%s", err)
+		}
+	}
+	output.apply(cfg, &errs)
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envOutput is the handler selected by the <PREFIX>_FORMAT, <PREFIX>_OUTPUT and
+// <PREFIX>_COLOR environment variables
+type envOutput struct {
+	format, output, color          string
+	formatEnv, outputEnv, colorEnv string
+}
+
+// set records the variable envname if key is one of format, output and color
+func (o *envOutput) set(key string, envname string, value string) bool {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "format":
+		o.format, o.formatEnv = value, envname
+	case "output":
+		o.output, o.outputEnv = value, envname
+	case "color":
+		o.color, o.colorEnv = value, envname
+	default:
+		return false
+	}
+	return true
+}
+
+// apply adds the output to the "handlers" of cfg. Output is "stderr", "stdout"
+// or the path of a file, and defaults to stderr. Format is any format of an
+// OutputConfig. Color is a boolean that turns the colors of the "color" format
+// on or off.
+func (o *envOutput) apply(cfg map[string]interface{}, errs *ConfigErrors) {
+	if len(o.formatEnv) == 0 && len(o.outputEnv) == 0 && len(o.colorEnv) == 0 {
+		return
+	}
+	for _, key := range []string{"handler", "handlers"} {
+		if _, ok := cfg[key]; ok {
+			for _, envname := range []string{o.formatEnv, o.outputEnv, o.colorEnv} {
+				if len(envname) > 0 {
+					errs.add(envname, "May not be set with %q", key)
+				}
+			}
+			return
+		}
+	}
+
+	handler := map[string]interface{}{"type": "console"}
+	switch strings.ToLower(o.output) {
+	case "", "stderr":
+	case "stdout":
+		handler["type"] = "stdout"
+	default:
+		handler["type"] = "file"
+		handler["path"] = o.output
+	}
+	format := strings.ToLower(o.format)
+	if len(format) > 0 {
+		handler["format"] = format
+	}
+
+	if len(o.colorEnv) > 0 {
+		color, err := strconv.ParseBool(o.color)
+		colored := format == "color" || (len(format) == 0 && handler["type"] != "file")
+		switch {
+		case err != nil:
+			errs.add(o.colorEnv, "Expected true or false. Found %q", o.color)
+		case colored && color:
+			handler["colors"] = map[string]interface{}{"mode": ColorAlways}
+		case colored:
+			handler["colors"] = map[string]interface{}{"mode": ColorNever}
+		case color:
+			errs.add(o.colorEnv, "Only applies to the \"color\" format")
+		}
+	}
+	cfg["handlers"] = []interface{}{handler}
+}
+
+// setEnv sets the config value at the path of keys to value, converting the
+// value to the type of the setting
+func setEnv(cfg map[string]interface{}, envkeys []string, value string) error {
+	t := reflect.TypeOf(RootLogConfig{})
+	lvlCfg := cfg
+	for i, k := range envkeys {
+		if len(k) == 0 {
+			return fmt.Errorf("Empty key. Separate keys with \"__\"")
+		}
+		key := envKey(k)
+		t = settingType(t, key)
+
+		if i == len(envkeys)-1 {
+			v, err := envValue(t, value)
+			if err != nil {
+				return err
+			}
+			if existing, ok := lvlCfg[key].(map[string]interface{}); ok {
+				if v, ok := v.(map[string]interface{}); ok {
+					// An object merges with the settings of other variables
+					mergeMaps(existing, v)
+					return nil
+				}
+			}
+			lvlCfg[key] = v
+			return nil
+		}
+
+		// Descend into the child object
+		child, ok := lvlCfg[key].(map[string]interface{})
+		if !ok {
+			if _, exists := lvlCfg[key]; exists {
+				return fmt.Errorf("%q is already set to a value that isn't an object", key)
+			}
+			child = make(map[string]interface{})
+			lvlCfg[key] = child
+		}
+		lvlCfg = child
+	}
+	return nil
+}
+
+// envKey converts a key of an environment variable name to a JSON key. Keys in a
+// single case are converted from ENV_CASE or snake_case to camelCase, so
+// JSON_CHILD and json_child are both jsonChild. Keys in mixed case, such as
+// myChild, are used as they are.
+func envKey(k string) string {
+	if strings.ToUpper(k) != k && strings.ToLower(k) != k {
+		return k
+	}
+	words := strings.Split(strings.ToLower(k), "_")
+	for i := 1; i < len(words); i++ {
+		if len(words[i]) > 0 {
+			runes := []rune(words[i])
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// settingType returns the type of the setting at key of a setting of type t, or
+// nil if it isn't known
+func settingType(t reflect.Type, key string) reflect.Type {
+	if nil == t {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == handlerConfigType {
+			// The options of a handler depend on its name
+			return nil
+		}
+		if field, ok := jsonField(t, key); ok {
+			return field.Type
+		}
+	case reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+// envValue converts the value of an environment variable to the JSON value of a
+// setting of type t. Numbers, booleans, arrays and objects are parsed. Values of
+// settings that aren't known are parsed if they look like JSON, and kept as
+// strings if they don't.
+func envValue(t reflect.Type, value string) (interface{}, error) {
+	if nil != t {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	trimmed := strings.TrimSpace(value)
+
+	var kind reflect.Kind
+	if nil != t {
+		kind = t.Kind()
+	}
+	switch {
+	case nil == t || t == rawMessageType || kind == reflect.Interface:
+		return guessValue(value)
+	case t == logLevelType || t == handlerConfigType && !strings.HasPrefix(trimmed, "{"):
+		return value, nil
+	case kind == reflect.String:
+		return value, nil
+	case kind == reflect.Bool:
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("Expected true or false. Found %q", value)
+		}
+		return b, nil
+	case kind >= reflect.Int && kind <= reflect.Float64:
+		n, ok := parseNumber(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("Expected a number. Found %q", value)
+		}
+		return n, nil
+	case kind == reflect.Slice || kind == reflect.Array:
+		var v []interface{}
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return nil, fmt.Errorf("Unable to parse as a JSON array. %s", err)
+		}
+		return v, nil
+	default:
+		v := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return nil, fmt.Errorf("Unable to parse as a JSON object. %s", err)
+		}
+		return v, nil
+	}
+}
+
+// guessValue parses a value that looks like a JSON object, array, number or
+// boolean, and keeps other values as strings
+func guessValue(value string) (interface{}, error) {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return nil, fmt.Errorf("Unable to parse as JSON. %s", err)
+		}
+		return v, nil
+	}
+	if trimmed == "true" || trimmed == "false" {
+		return trimmed == "true", nil
+	}
+	if n, ok := parseNumber(trimmed); ok {
+		return n, nil
+	}
+	return value, nil
+}
+
+// parseNumber parses a finite number
+func parseNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	return n, err == nil && !math.IsInf(n, 0) && !math.IsNaN(n)
+}
@@ -0,0 +1,132 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// setenv sets environment variables, returning a func that unsets them
+func setenv(vars map[string]string) func() {
+	for name, value := range vars {
+		os.Setenv(name, value)
+	}
+	return func() {
+		for name := range vars {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestEnvPrefixConfigValues(test *testing.T) {
+	defer setenv(map[string]string{
+		"ENV_TEST_LABEL":                   "app=1",
+		"env_test_loggers__db__level":      "DEBUG",
+		"Env_Test_LOGGERS__myChild__LEVEL": "WARN",
+		"ENV_TEST_RATE_LIMIT_PER_SEC":      "250",
+		"ENV_TEST_SAMPLE__EVERY":           "10",
+		"ENV_TEST_SAMPLE__LEVELS":          `["DEBUG", "TRACE"]`,
+		"ENV_TEST_RULES":                   `[{"match": "sql$", "level": "TRACE"}]`,
+	})()
+
+	config, err := logs.EnvPrefixConfig("ENV_TEST")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Label != "app=1" {
+		test.Errorf("Expected values containing = to be kept whole. Found: %q", config.Label)
+	}
+	if nil == config.Loggers["db"] || config.Loggers["db"].Level != logs.Debug {
+		test.Errorf("Expected a lowercase variable to set loggers.db. Found: %+v", config.Loggers)
+	}
+	if nil == config.Loggers["myChild"] || config.Loggers["myChild"].Level != logs.Warn {
+		test.Errorf("Expected a mixed case key to be used as it is. Found: %+v", config.Loggers)
+	}
+	if config.RateLimitPerSec != 250 || nil == config.Sample || config.Sample.Every != 10 || len(config.Sample.Levels) != 2 {
+		test.Errorf("Expected numbers and arrays to be parsed. Found: %v, %+v", config.RateLimitPerSec, config.Sample)
+	}
+	if len(config.Rules) != 1 || config.Rules[0].Match != "sql$" {
+		test.Errorf("Expected the rules array to be parsed. Found: %+v", config.Rules)
+	}
+}
+
+func TestEnvPrefixConfigErrors(test *testing.T) {
+	defer setenv(map[string]string{
+		"ENV_ERR_TEST_RATE_LIMIT_PER_SEC": "fast",
+		"ENV_ERR_TEST_SAMPLE__LEVELS":     "DEBUG",
+		"ENV_ERR_TEST_LOGGERS____LEVEL":   "DEBUG",
+		"ENV_ERR_TEST_LOGGERS__DB":        "{broken",
+	})()
+
+	_, err := logs.EnvPrefixConfig("ENV_ERR_TEST")
+	errs, ok := err.(logs.ConfigErrors)
+	if !ok || len(errs) != 4 {
+		test.Fatalf("Expected all 4 variables to be reported. Found: %v", err)
+	}
+	for i, name := range []string{
+		"ENV_ERR_TEST_LOGGERS__DB",
+		"ENV_ERR_TEST_LOGGERS____LEVEL",
+		"ENV_ERR_TEST_RATE_LIMIT_PER_SEC",
+		"ENV_ERR_TEST_SAMPLE__LEVELS",
+	} {
+		if errs[i].Path != name {
+			test.Errorf("Expected an error for %s. Found: %s", name, errs[i])
+		}
+	}
+}
+
+func TestEnvPrefixConfigOutput(test *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	unset := setenv(map[string]string{
+		"ENV_OUT_TEST_FORMAT": "json",
+		"ENV_OUT_TEST_OUTPUT": path,
+		"ENV_OUT_TEST_COLOR":  "false",
+		"ENV_OUT_TEST_LABEL":  "app",
+	})
+	config, err := logs.EnvPrefixConfig("ENV_OUT_TEST")
+	unset()
+	if err != nil {
+		test.Fatal(err)
+	}
+	logger := logs.New(config)
+	logger.Info("To the file")
+	logger.Close()
+	out, _ := ioutil.ReadFile(path)
+	if !strings.Contains(string(out), `"message":"To the file"`) {
+		test.Errorf("Expected JSON in %s. Found: %q", path, out)
+	}
+
+	defer setenv(map[string]string{"ENV_OUT_TEST_COLOR": "false"})()
+	config, err = logs.EnvPrefixConfig("ENV_OUT_TEST")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if len(config.Handlers) != 1 || config.Handlers[0].Type != "console" || nil == config.Handlers[0].Colors || config.Handlers[0].Colors.Mode != logs.ColorNever {
+		test.Errorf("Expected uncolored console output. Found: %+v", config.Handlers)
+	}
+}
+
+func TestEnvPrefixConfigOutputErrors(test *testing.T) {
+	for _, vars := range []map[string]string{
+		{"ENV_OUT_ERR_TEST_COLOR": "sometimes"},
+		{"ENV_OUT_ERR_TEST_COLOR": "true", "ENV_OUT_ERR_TEST_FORMAT": "json"},
+		{"ENV_OUT_ERR_TEST_FORMAT": "json", "ENV_OUT_ERR_TEST_HANDLER": "text-stderr"},
+		{"ENV_OUT_ERR_TEST_FORMAT": "yaml"},
+	} {
+		unset := setenv(vars)
+		if _, err := logs.EnvPrefixConfig("ENV_OUT_ERR_TEST"); err == nil {
+			test.Errorf("Expected an error for %v", vars)
+		}
+		unset()
+	}
+}
@@ -0,0 +1,137 @@
+package gologsgo
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// ErrorContextConfig configures an ErrorContextHandler
+type ErrorContextConfig struct {
+	// Size is the number of entries held for each scope. Defaults to 100.
+	Size int
+	// PassLevel is the lowest level passed on immediately. Entries below it are
+	// held. Defaults to Info.
+	PassLevel LogLevel
+	// TriggerLevel is the lowest level at which the held entries of the scope are
+	// passed on, before the triggering entry. Defaults to Error.
+	TriggerLevel LogLevel
+	// ScopeField, when set, scopes entries by the value of this field (such as a
+	// request ID) instead of by logger. Entries without it are scoped by logger.
+	ScopeField string
+	// MaxScopes is the number of scopes held at once. When exceeded, the entries
+	// of the least recently used scope are discarded. Defaults to 1000.
+	MaxScopes int
+}
+
+type errorScope struct {
+	key     string
+	entries *RingBuffer
+	element *list.Element
+}
+
+// ErrorContextHandler holds verbose entries in a ring buffer for each logger (or
+// request) and only passes them on when an error occurs in the same scope, giving
+// full diagnostic context on failure without verbose steady-state logs. The
+// Logger must be configured at a level that lets the verbose entries through,
+// such as Trace.
+type ErrorContextHandler struct {
+	handler LogHandler
+	config  ErrorContextConfig
+	mu      sync.Mutex
+	scopes  map[string]*errorScope
+	lru     *list.List
+}
+
+// ErrorContext wraps handler in an ErrorContextHandler
+func ErrorContext(handler LogHandler, config ErrorContextConfig) *ErrorContextHandler {
+	if config.Size < 1 {
+		config.Size = 100
+	}
+	if config.PassLevel == NotSet {
+		config.PassLevel = Info
+	}
+	if config.TriggerLevel == NotSet {
+		config.TriggerLevel = Error
+	}
+	if config.MaxScopes < 1 {
+		config.MaxScopes = 1000
+	}
+	return &ErrorContextHandler{
+		handler: handler,
+		config:  config,
+		scopes:  make(map[string]*errorScope),
+		lru:     list.New(),
+	}
+}
+
+// LogHandler holds or passes on a LogMessage. It is a LogHandler.
+func (h *ErrorContextHandler) LogHandler(msg LogMessage) {
+	key := h.scope(msg)
+
+	if msg.Level < h.config.PassLevel {
+		h.mu.Lock()
+		h.get(key).entries.LogHandler(msg)
+		h.mu.Unlock()
+		return
+	}
+
+	if msg.Level >= h.config.TriggerLevel {
+		h.mu.Lock()
+		scope, ok := h.scopes[key]
+		if ok {
+			h.remove(scope)
+		}
+		h.mu.Unlock()
+		if ok {
+			for _, held := range scope.entries.Drain() {
+				h.handler(held)
+			}
+		}
+	}
+	h.handler(msg)
+}
+
+// EndScope discards the entries held for a scope - the value of ScopeField, such
+// as a request ID - when it ends without an error
+func (h *ErrorContextHandler) EndScope(value interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if scope, ok := h.scopes["field:"+fmt.Sprint(value)]; ok {
+		h.remove(scope)
+	}
+}
+
+func (h *ErrorContextHandler) scope(msg LogMessage) string {
+	if len(h.config.ScopeField) > 0 {
+		if value, ok := msg.Fields[h.config.ScopeField]; ok {
+			return "field:" + fmt.Sprint(value)
+		}
+	}
+	return "logger:" + msg.Logger
+}
+
+// get returns the scope for key, creating it if necessary. h.mu must be held.
+func (h *ErrorContextHandler) get(key string) *errorScope {
+	if scope, ok := h.scopes[key]; ok {
+		h.lru.MoveToFront(scope.element)
+		return scope
+	}
+
+	if len(h.scopes) >= h.config.MaxScopes {
+		h.remove(h.lru.Back().Value.(*errorScope))
+	}
+	scope := &errorScope{
+		key:     key,
+		entries: RingBufferHandler(h.config.Size),
+	}
+	scope.element = h.lru.PushFront(scope)
+	h.scopes[key] = scope
+	return scope
+}
+
+// remove forgets a scope. h.mu must be held.
+func (h *ErrorContextHandler) remove(scope *errorScope) {
+	h.lru.Remove(scope.element)
+	delete(h.scopes, scope.key)
+}
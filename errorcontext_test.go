@@ -0,0 +1,66 @@
+package gologsgo_test
+
+import (
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestErrorContext(test *testing.T) {
+	var received []string
+	handler := logs.ErrorContext(func(msg logs.LogMessage) {
+		received = append(received, msg.Message)
+	}, logs.ErrorContextConfig{Size: 2})
+
+	root := logs.New(&logs.RootLogConfig{
+		Level:      logs.Trace,
+		LogHandler: handler.LogHandler,
+	})
+	db := root.ChildLogger("db")
+	api := root.ChildLogger("api")
+
+	db.Debug("db 1")
+	db.Trace("db 2")
+	db.Debug("db 3")
+	api.Debug("api 1")
+	db.Info("db info")
+	if strings.Join(received, ",") != "db info" {
+		test.Fatalf("Expected only Info to pass before an error. Found: %v", received)
+	}
+
+	db.Error("db failed")
+	if strings.Join(received, ",") != "db info,db 2,db 3,db failed" {
+		test.Errorf("Expected the last 2 held db entries before the error. Found: %v", received)
+	}
+
+	received = nil
+	api.Error("api failed")
+	if strings.Join(received, ",") != "api 1,api failed" {
+		test.Errorf("Expected only the api scope to be flushed. Found: %v", received)
+	}
+}
+
+func TestErrorContextScopeField(test *testing.T) {
+	var received []string
+	handler := logs.ErrorContext(func(msg logs.LogMessage) {
+		received = append(received, msg.Message)
+	}, logs.ErrorContextConfig{ScopeField: "request_id"})
+
+	request := func(id string, message string, level logs.LogLevel) {
+		handler.LogHandler(logs.LogMessage{
+			Level:   level,
+			Message: message,
+			Fields:  map[string]interface{}{"request_id": id},
+		})
+	}
+	request("a", "a debug", logs.Debug)
+	request("b", "b debug", logs.Debug)
+	handler.EndScope("a")
+	request("a", "a failed", logs.Error)
+	request("b", "b failed", logs.Error)
+
+	if strings.Join(received, ",") != "a failed,b debug,b failed" {
+		test.Errorf("Expected entries to be scoped by request. Found: %v", received)
+	}
+}
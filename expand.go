@@ -0,0 +1,59 @@
+package gologsgo
+
+import (
+	"bytes"
+	"os"
+	"strings"
+)
+
+// ExpandEnv replaces ${VAR} in config data with the value of the environment
+// variable VAR, and ${VAR:-default} with its value, or default when it is unset
+// or empty, so one config file can be reused across environments:
+//
+//	{"level": "${LOG_LEVEL:-INFO}", "handler": {"name": "file", "options": {"path": "${LOG_DIR}/app.log"}}}
+//
+// Unset variables without a default expand to the empty string. Values are
+// substituted as text, before the data is parsed. $${ is written as a literal ${.
+// Other uses of $, such as in regular expressions, are left alone. The config
+// files read by FileConfig(), NamedConfig() and WatchFileConfig() are expanded.
+func ExpandEnv(data []byte) []byte {
+	if !bytes.Contains(data, []byte("${")) {
+		return data
+	}
+
+	var b bytes.Buffer
+	for {
+		i := bytes.Index(data, []byte("${"))
+		if i < 0 {
+			break
+		}
+		if i > 0 && data[i-1] == '$' {
+			// $${ escapes ${
+			b.Write(data[:i-1])
+			b.WriteString("${")
+			data = data[i+2:]
+			continue
+		}
+		end := bytes.IndexByte(data[i+2:], '}')
+		if end < 0 {
+			break
+		}
+		b.Write(data[:i])
+		b.WriteString(expandVar(string(data[i+2 : i+2+end])))
+		data = data[i+3+end:]
+	}
+	b.Write(data)
+	return b.Bytes()
+}
+
+// expandVar returns the value of a VAR or VAR:-default expression
+func expandVar(expr string) string {
+	name, def := expr, ""
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		name, def = expr[:i], expr[i+2:]
+	}
+	if value := os.Getenv(name); len(value) > 0 {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,57 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestExpandEnv(test *testing.T) {
+	os.Setenv("EXPAND_TEST_LEVEL", "DEBUG")
+	defer os.Unsetenv("EXPAND_TEST_LEVEL")
+	os.Setenv("EXPAND_TEST_EMPTY", "")
+	defer os.Unsetenv("EXPAND_TEST_EMPTY")
+
+	cases := map[string]string{
+		`${EXPAND_TEST_LEVEL}`:                 `DEBUG`,
+		`${EXPAND_TEST_LEVEL:-INFO}`:           `DEBUG`,
+		`${EXPAND_TEST_UNSET:-INFO}`:           `INFO`,
+		`${EXPAND_TEST_EMPTY:-INFO}`:           `INFO`,
+		`[${EXPAND_TEST_UNSET}]`:               `[]`,
+		`${EXPAND_TEST_UNSET:-/var/log}/a.log`: `/var/log/a.log`,
+		`$${EXPAND_TEST_LEVEL}`:                `${EXPAND_TEST_LEVEL}`,
+		`sql$ $HOME ${unterminated`:            `sql$ $HOME ${unterminated`,
+	}
+	for data, expected := range cases {
+		if found := string(logs.ExpandEnv([]byte(data))); found != expected {
+			test.Errorf("Expected %q to expand to %q. Found: %q", data, expected, found)
+		}
+	}
+}
+
+func TestFileConfigExpandsEnv(test *testing.T) {
+	dir, err := ioutil.TempDir("", "expand")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.json")
+	ioutil.WriteFile(path, []byte(`{
+		"level": "${EXPAND_TEST_LEVEL:-WARN}",
+		"label": "${EXPAND_TEST_LABEL:-app}",
+		"rules": [{"match": "sql$", "level": "DEBUG"}]
+	}`), 0644)
+
+	os.Setenv("EXPAND_TEST_LEVEL", "TRACE")
+	defer os.Unsetenv("EXPAND_TEST_LEVEL")
+	config, err := logs.FileConfig(path)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Trace || config.Label != "app" || config.Rules[0].Match != "sql$" {
+		test.Errorf("Unexpected config: %+v", config)
+	}
+}
@@ -0,0 +1,133 @@
+package gologsgo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FailoverHandler tries a chain of handlers in order, falling through to the next
+// when one fails - for example, NetworkHandler, then a local file, then stderr.
+// NetworkHandler, HTTPHandler and KafkaHandler queue entries, so use their
+// LogHandlerE methods, which fail while the sink is unavailable.
+// A handler that fails is skipped until its retry interval has passed, after
+// which it is tried again, so delivery recovers to the primary once it is healthy.
+type FailoverHandler struct {
+	handlers []LogHandlerE
+	mu       sync.Mutex
+	failed   []time.Time
+	retry    time.Duration
+}
+
+// Failover returns a FailoverHandler for the handlers, in order of preference.
+// Failed handlers are retried after 30 seconds; use SetRetryInterval() to change
+// this.
+func Failover(primary LogHandlerE, others ...LogHandlerE) *FailoverHandler {
+	handlers := append([]LogHandlerE{primary}, others...)
+	return &FailoverHandler{
+		handlers: handlers,
+		failed:   make([]time.Time, len(handlers)),
+		retry:    30 * time.Second,
+	}
+}
+
+// SetRetryInterval sets how long a failed handler is skipped before it is tried
+// again
+func (f *FailoverHandler) SetRetryInterval(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retry = d
+}
+
+// LogHandlerE delivers a LogMessage to the first healthy handler that accepts it.
+// If every handler fails, the last error is returned. It is a LogHandlerE.
+func (f *FailoverHandler) LogHandlerE(msg LogMessage) error {
+	healthy := f.healthy()
+
+	var last error
+	for i, handler := range f.handlers {
+		if !healthy[i] {
+			continue
+		}
+		err := handler(msg)
+		f.mu.Lock()
+		if err != nil {
+			f.failed[i] = time.Now()
+		} else {
+			f.failed[i] = time.Time{}
+		}
+		f.mu.Unlock()
+		if nil == err {
+			return nil
+		}
+		last = err
+	}
+	return fmt.Errorf("All %d failover handlers failed. Last error: %s", len(f.handlers), last)
+}
+
+// Active returns the index of the first handler that is currently considered
+// healthy, or -1 if none are
+func (f *FailoverHandler) Active() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for i, failed := range f.failed {
+		if failed.IsZero() || now.Sub(failed) >= f.retry {
+			return i
+		}
+	}
+	return -1
+}
+
+// healthy reports which handlers should be tried. If every handler has failed
+// recently, they are all tried rather than dropping the record.
+func (f *FailoverHandler) healthy() []bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	healthy := make([]bool, len(f.handlers))
+	any := false
+	now := time.Now()
+	for i, failed := range f.failed {
+		healthy[i] = failed.IsZero() || now.Sub(failed) >= f.retry
+		any = any || healthy[i]
+	}
+	if !any {
+		for i := range healthy {
+			healthy[i] = true
+		}
+	}
+	return healthy
+}
+
+// health records whether a queued handler's sink is failing, so that its
+// LogHandlerE can report the failure to a FailoverHandler instead of queuing
+// entries that won't be delivered
+type health struct {
+	mu     sync.Mutex
+	err    error
+	failed time.Time
+}
+
+// set records the outcome of the last delivery. A nil err marks the sink
+// healthy.
+func (h *health) set(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.err = err
+	if nil != err {
+		h.failed = time.Now()
+	}
+}
+
+// check returns the error of the last delivery if it failed. After probe has
+// passed, nil is returned so the next entries find out whether the sink has
+// recovered. A probe of 0 returns the error until set(nil) is called.
+func (h *health) check(probe time.Duration) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if nil == h.err || (probe > 0 && time.Since(h.failed) >= probe) {
+		return nil
+	}
+	return h.err
+}
@@ -0,0 +1,143 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestFailover(test *testing.T) {
+	primaryDown := true
+	var primary, secondary int
+	failover := logs.Failover(
+		func(logs.LogMessage) error {
+			if primaryDown {
+				return errors.New("connection refused")
+			}
+			primary++
+			return nil
+		},
+		func(logs.LogMessage) error {
+			secondary++
+			return nil
+		},
+	)
+	failover.SetRetryInterval(20 * time.Millisecond)
+
+	if err := failover.LogHandlerE(logs.LogMessage{}); err != nil {
+		test.Fatalf("Expected the secondary to accept the record. Found: %v", err)
+	}
+	if secondary != 1 || failover.Active() != 1 {
+		test.Fatalf("Expected to fail over to the secondary. Found: %d, active %d", secondary, failover.Active())
+	}
+
+	primaryDown = false
+	failover.LogHandlerE(logs.LogMessage{})
+	if primary != 0 || secondary != 2 {
+		test.Errorf("Expected the failed primary to be skipped until the retry interval. Found: %d, %d", primary, secondary)
+	}
+
+	// Entries go to the secondary until the retry interval has passed
+	deadline := time.Now().Add(5 * time.Second)
+	for primary == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		failover.LogHandlerE(logs.LogMessage{})
+	}
+	if primary != 1 || failover.Active() != 0 {
+		test.Errorf("Expected to recover to the primary. Found: %d, active %d", primary, failover.Active())
+	}
+}
+
+func TestFailoverAllFailing(test *testing.T) {
+	attempts := 0
+	failing := func(logs.LogMessage) error {
+		attempts++
+		return errors.New("down")
+	}
+	failover := logs.Failover(failing, failing)
+
+	if err := failover.LogHandlerE(logs.LogMessage{}); err == nil {
+		test.Errorf("Expected an error when every handler fails")
+	}
+	if err := failover.LogHandlerE(logs.LogMessage{}); err == nil || attempts != 4 {
+		test.Errorf("Expected every handler to be tried when none are healthy. Found %d attempts", attempts)
+	}
+	if failover.Active() != -1 {
+		test.Errorf("Expected no active handler. Found: %d", failover.Active())
+	}
+}
+
+func TestFailoverNetworkHandler(test *testing.T) {
+	// Reserve an address with nothing listening on it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		test.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	network, err := logs.NewNetworkHandler(logs.NetworkHandlerConfig{
+		Network:    "tcp",
+		Address:    address,
+		MinBackoff: 5 * time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		test.Fatalf("Error creating NetworkHandler: %s", err)
+	}
+	defer network.Close()
+	var local []string
+	failover := logs.Failover(network.LogHandlerE, func(msg logs.LogMessage) error {
+		local = append(local, msg.Message)
+		return nil
+	})
+	failover.SetRetryInterval(10 * time.Millisecond)
+
+	// The first entry is queued, and fails to connect
+	failover.LogHandlerE(logs.LogMessage{Message: "Queued"})
+	deadline := time.Now().Add(5 * time.Second)
+	for len(local) == 0 && time.Now().Before(deadline) {
+		failover.LogHandlerE(logs.LogMessage{Message: "Local"})
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(local) == 0 {
+		test.Fatal("Expected entries to fail over while the collector is down")
+	}
+
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		test.Skipf("Unable to listen on %s again: %s", address, err)
+	}
+	defer listener.Close()
+	received := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			received <- scanner.Text()
+		}
+	}()
+
+	// Once the queued entry is delivered, entries return to the collector
+	for time.Now().Before(deadline) {
+		failover.LogHandlerE(logs.LogMessage{Message: "Recovered"})
+		select {
+		case line := <-received:
+			if !strings.Contains(line, "Recovered") {
+				continue
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	test.Error("Expected entries to return to the collector once it recovered")
+}
@@ -0,0 +1,166 @@
+package gologsgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fieldlock guards the fields attached to every Logger
+var fieldlock sync.RWMutex
+
+// field is a value attached to a Logger, optionally with an expiry
+type field struct {
+	value   interface{}
+	expires time.Time
+}
+
+func (f field) expired(now time.Time) bool {
+	return !f.expires.IsZero() && now.After(f.expires)
+}
+
+// SetField attaches a field to the Logger. The field is added to every record
+// logged by the Logger and its children. A child's field overrides a field with
+// the same key on its parent.
+func (logger *Logger) SetField(key string, value interface{}) {
+	logger.setField(key, field{value: value})
+}
+
+// SetFieldWithTTL attaches a field to the Logger, like SetField, that stops being
+// added to records once ttl has elapsed. This is useful for temporary context
+// (such as a deployment marker for the first hour after startup) that shouldn't
+// permanently inflate every record.
+func (logger *Logger) SetFieldWithTTL(key string, value interface{}, ttl time.Duration) {
+	logger.setField(key, field{value: value, expires: time.Now().Add(ttl)})
+}
+
+// RemoveField removes a field previously attached to the Logger
+func (logger *Logger) RemoveField(key string) {
+	fieldlock.Lock()
+	defer fieldlock.Unlock()
+	delete(logger.fields, key)
+}
+
+func (logger *Logger) setField(key string, f field) {
+	fieldlock.Lock()
+	defer fieldlock.Unlock()
+	if nil == logger.fields {
+		logger.fields = make(map[string]field)
+	}
+	logger.fields[key] = f
+
+	// Expired fields are never needed again
+	now := time.Now()
+	for k, f := range logger.fields {
+		if f.expired(now) {
+			delete(logger.fields, k)
+		}
+	}
+}
+
+// WithFields returns a Logger that logs as logger does, with fields added to
+// its records, for context that is only known for a while, such as the ID of a
+// request:
+//
+//	reqLogger := logger.WithFields(map[string]interface{}{"request_id": id})
+//
+// The returned Logger is not part of the tree of logger, so it is released
+// with the last reference to it, however many are created. Its level is that of
+// logger, and setting its level sets the level of logger. Its ChildLogger()
+// returns the child of logger with the same fields. The fields override those
+// of logger with the same keys, and SetField() on the returned Logger only
+// affects its own records.
+func (logger *Logger) WithFields(fields map[string]interface{}) *Logger {
+	base := logger
+	own := make(map[string]field, len(fields))
+	if nil != logger.base {
+		base = logger.base
+		fieldlock.RLock()
+		for k, f := range logger.fields {
+			own[k] = f
+		}
+		fieldlock.RUnlock()
+	}
+	for k, v := range fields {
+		own[k] = field{value: v}
+	}
+
+	return &Logger{
+		parent:      base,
+		base:        base,
+		name:        base.name,
+		logConfig:   base.logConfig,
+		label:       base.label,
+		logHandler:  base.logHandler,
+		hooks:       base.hooks,
+		fields:      own,
+		lifecycle:   base.lifecycle,
+		labelFormat: base.labelFormat,
+		rendered:    base.rendered,
+		rules:       base.rules,
+	}
+}
+
+// ownFields returns the unexpired fields attached to the Logger itself
+func (logger *Logger) ownFields() map[string]interface{} {
+	now := time.Now()
+	fieldlock.RLock()
+	defer fieldlock.RUnlock()
+	fields := make(map[string]interface{}, len(logger.fields))
+	for k, f := range logger.fields {
+		if !f.expired(now) {
+			fields[k] = f.value
+		}
+	}
+	return fields
+}
+
+// Fields returns the unexpired fields that will be added to records logged by
+// the Logger, including those inherited from its parents. It returns nil if
+// there are none.
+func (logger *Logger) Fields() map[string]interface{} {
+	now := time.Now()
+	var fields map[string]interface{}
+
+	fieldlock.RLock()
+	defer fieldlock.RUnlock()
+	for l := logger; nil != l; l = l.parent {
+		for k, f := range l.fields {
+			if f.expired(now) {
+				continue
+			}
+			if _, ok := fields[k]; ok {
+				// Already set by a child
+				continue
+			}
+			if nil == fields {
+				fields = make(map[string]interface{})
+			}
+			fields[k] = f.value
+		}
+	}
+
+	return fields
+}
+
+// formatFields renders fields as space separated key=value pairs, sorted by key
+func formatFields(fields map[string]interface{}) string {
+	keys := fieldKeys(fields)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, fields[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// fieldKeys returns the keys of fields, sorted
+func fieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+package gologsgo_test
+
+import (
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestFieldsWithTTL(test *testing.T) {
+	var messages []logs.LogMessage
+	root := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		LogHandler: func(msg logs.LogMessage) {
+			messages = append(messages, msg)
+		},
+	})
+	child := root.ChildLogger("child")
+
+	root.SetField("service", "billing")
+	root.SetFieldWithTTL("deploy", "v1.2.3", 50*time.Millisecond)
+	child.SetField("service", "billing-worker")
+
+	child.Info("Before the TTL")
+	before := messages[0].Fields
+	if before["deploy"] != "v1.2.3" || before["service"] != "billing-worker" {
+		test.Errorf("Unexpected fields before the TTL: %v", before)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		child.Info("After the TTL")
+		if _, ok := messages[len(messages)-1].Fields["deploy"]; !ok || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	after := messages[len(messages)-1].Fields
+	if _, ok := after["deploy"]; ok || after["service"] != "billing-worker" {
+		test.Errorf("Unexpected fields after the TTL: %v", after)
+	}
+}
+
+func TestFieldsInDefaultLogHandler(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{Label: "main"})
+	logger.SetField("b", 2)
+	logger.SetField("a", "one")
+
+	expectedOut := "INFO [main]: An info log message a=one b=2\n"
+	actualOut := captureStdLog(test, func() {
+		logger.Info("An info log message")
+	})
+	if actualOut != expectedOut {
+		test.Errorf("Did not receive expected log message:\n%s\nShould be:\n%s", actualOut, expectedOut)
+	}
+}
+
+func TestWithFields(test *testing.T) {
+	var messages []logs.LogMessage
+	root := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		LogHandler: func(msg logs.LogMessage) {
+			messages = append(messages, msg)
+		},
+	})
+	http := root.ChildLogger("http")
+	http.SetField("service", "api")
+
+	req := http.WithFields(map[string]interface{}{"request_id": "r1"})
+	req.SetField("user", "jane")
+	req.Info("Handled")
+	http.Info("Unrelated")
+	req.WithFields(map[string]interface{}{"status": 200}).ChildLogger("db").Info("Queried")
+
+	if len(messages) != 3 {
+		test.Fatalf("Expected 3 messages. Found: %d", len(messages))
+	}
+	if f := messages[0].Fields; messages[0].Logger != "main.http" || f["request_id"] != "r1" || f["user"] != "jane" || f["service"] != "api" {
+		test.Errorf("Unexpected request entry: %v", messages[0])
+	}
+	if f := messages[1].Fields; len(f) != 1 || f["service"] != "api" {
+		test.Errorf("Expected the request fields to stay off the parent. Found: %v", f)
+	}
+	if f := messages[2].Fields; messages[2].Logger != "main.http.db" || f["request_id"] != "r1" || f["user"] != "jane" || f["status"] != 200 {
+		test.Errorf("Expected the child to keep the request fields. Found: %v", messages[2])
+	}
+
+	req.SetLevel(logs.Warn)
+	if http.Level() != logs.Warn || req.Level() != logs.Warn {
+		test.Errorf("Expected the level to be set on the parent. Found: %v", http.Level())
+	}
+	http.SetLevel(logs.Debug)
+	if req.Level() != logs.Debug {
+		test.Errorf("Expected the level to follow the parent. Found: %v", req.Level())
+	}
+	if levels := root.Levels(); len(levels.Loggers) != 1 || len(levels.Loggers["http"].Loggers) != 1 {
+		test.Errorf("Expected WithFields() to add no loggers to the tree. Found: %+v", levels)
+	}
+}
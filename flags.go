@@ -0,0 +1,173 @@
+package gologsgo
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// FlagConfig registers logging flags with fs (flag.CommandLine when nil) and
+// returns the RootLogConfig they configure. Its settings are filled in as fs is
+// parsed, so pass it to New() after fs.Parse():
+//
+//	config := logs.FlagConfig(nil)
+//	flag.Parse()
+//	logger := logs.New(config)
+//
+// The flags are:
+//
+//	--log-level <level>           the root level
+//	--log-level <logger>=<level>  the level of a logger, such as db.cache=TRACE
+//	--log-format <format>         "color", "text", "json", "logstash" or "ecs"
+//	--log-output <output>         "stderr" (the default), "stdout" or a file path
+//	-v                            lowers the level from INFO to DEBUG, and
+//	                              again to TRACE when repeated: -v -v
+//
+// --log-level may be repeated. -v lowers the root level from the one set by
+// --log-level before it, if any.
+func FlagConfig(fs *flag.FlagSet) *RootLogConfig {
+	if nil == fs {
+		fs = flag.CommandLine
+	}
+	f := &flagConfig{config: &RootLogConfig{}}
+	fs.Var(levelFlag{f}, "log-level", "Log `level`, or <logger>=<level> for a logger. May be repeated.")
+	fs.Var(formatFlag{f}, "log-format", "Log `format`: color, text, json, logstash or ecs")
+	fs.Var(outputFlag{f}, "log-output", "Log `output`: stderr, stdout or a file path")
+	fs.Var(verbosityFlag{f}, "v", "Verbose logging. Repeat for more detail.")
+	return f.config
+}
+
+// flagConfig is the state shared by the flags registered by FlagConfig()
+type flagConfig struct {
+	config *RootLogConfig
+	output *OutputConfig
+}
+
+// setOutput updates the config's handler after --log-format or --log-output
+func (f *flagConfig) setOutput(update func(output *OutputConfig)) {
+	if nil == f.output {
+		f.output = &OutputConfig{Type: "console"}
+	}
+	update(f.output)
+	f.config.Handlers = []OutputConfig{*f.output}
+}
+
+type levelFlag struct{ *flagConfig }
+
+func (l levelFlag) String() string {
+	if nil == l.flagConfig || l.config.Level == NotSet {
+		return ""
+	}
+	return LogLevels.Label(l.config.Level)
+}
+
+func (l levelFlag) Set(value string) error {
+	eq := strings.LastIndex(value, "=")
+	level, ok := LogLevels.Level(value[eq+1:])
+	if !ok {
+		return fmt.Errorf("Unknown level %q", value[eq+1:])
+	}
+	if eq < 0 {
+		l.config.Level = level
+		return nil
+	}
+
+	name := value[:eq]
+	loggers := &l.config.Loggers
+	var cfg *LogConfig
+	for _, n := range strings.Split(name, ".") {
+		if len(n) == 0 {
+			return fmt.Errorf("Invalid logger %q. Use <level> or <logger>=<level>", name)
+		}
+		if nil == *loggers {
+			*loggers = make(map[string]*LogConfig)
+		}
+		cfg = (*loggers)[n]
+		if nil == cfg {
+			cfg = &LogConfig{}
+			(*loggers)[n] = cfg
+		}
+		loggers = &cfg.Loggers
+	}
+	cfg.Level = level
+	return nil
+}
+
+type formatFlag struct{ *flagConfig }
+
+func (f formatFlag) String() string {
+	if nil == f.flagConfig || nil == f.output {
+		return ""
+	}
+	return f.output.Format
+}
+
+func (f formatFlag) Set(value string) error {
+	switch value {
+	case "color", "text", "json", "logstash", "ecs":
+	default:
+		return fmt.Errorf("Unknown format %q. Use color, text, json, logstash or ecs", value)
+	}
+	if value == "color" && nil != f.output && f.output.Type == "file" {
+		return fmt.Errorf("The \"color\" format can't be written to a file")
+	}
+	f.setOutput(func(output *OutputConfig) {
+		output.Format = value
+	})
+	return nil
+}
+
+type outputFlag struct{ *flagConfig }
+
+func (o outputFlag) String() string {
+	if nil == o.flagConfig || nil == o.output {
+		return ""
+	}
+	if o.output.Type == "file" {
+		return o.output.Path
+	}
+	return o.output.Type
+}
+
+func (o outputFlag) Set(value string) error {
+	if len(value) == 0 {
+		return fmt.Errorf("The output must be stderr, stdout or a file path")
+	}
+	if nil != o.output && o.output.Format == "color" && value != "stderr" && value != "stdout" {
+		return fmt.Errorf("The \"color\" format can't be written to a file")
+	}
+	o.setOutput(func(output *OutputConfig) {
+		switch value {
+		case "stderr":
+			output.Type, output.Path = "console", ""
+		case "stdout":
+			output.Type, output.Path = "stdout", ""
+		default:
+			output.Type, output.Path = "file", value
+		}
+	})
+	return nil
+}
+
+type verbosityFlag struct{ *flagConfig }
+
+func (v verbosityFlag) IsBoolFlag() bool {
+	return true
+}
+
+func (v verbosityFlag) String() string {
+	return ""
+}
+
+func (v verbosityFlag) Set(value string) error {
+	if value != "true" {
+		return fmt.Errorf("-v doesn't take a value. Repeat it for more detail: -v -v")
+	}
+	if v.config.Level == NotSet {
+		v.config.Level = Info
+	}
+	if lower, ok := LogLevels.Previous(v.config.Level); ok {
+		v.config.Level = lower
+	}
+	return nil
+}
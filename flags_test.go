@@ -0,0 +1,71 @@
+package gologsgo_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestFlagConfig(test *testing.T) {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	config := logs.FlagConfig(fs)
+	if config.Level != logs.NotSet || len(config.Handlers) > 0 {
+		test.Errorf("Expected an empty config before parsing. Found: %+v", config)
+	}
+	err := fs.Parse([]string{"--log-level=db.cache=trace", "--log-format", "json", "--log-output=stdout", "-v", "serve"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Debug {
+		test.Errorf("Expected -v to set the DEBUG level. Found: %s", logs.LogLevels.Label(config.Level))
+	}
+	if len(config.Handlers) != 1 || config.Handlers[0].Type != "stdout" || config.Handlers[0].Format != "json" {
+		test.Errorf("Expected a json stdout handler. Found: %+v", config.Handlers)
+	}
+	db := config.Loggers["db"]
+	if nil == db || nil == db.Loggers["cache"] || db.Loggers["cache"].Level != logs.Trace {
+		test.Errorf("Expected the db.cache level to be set. Found: %+v", db)
+	}
+	if fs.Arg(0) != "serve" {
+		test.Errorf("Expected the arguments to be left. Found: %v", fs.Args())
+	}
+}
+
+func TestFlagConfigVerbosity(test *testing.T) {
+	for args, expected := range map[string]logs.LogLevel{
+		"":                    logs.NotSet,
+		"-v":                  logs.Debug,
+		"-v -v":               logs.Trace,
+		"-v -v -v":            logs.Trace,
+		"--log-level=WARN -v": logs.Info,
+	} {
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		config := logs.FlagConfig(fs)
+		if err := fs.Parse(strings.Fields(args)); err != nil {
+			test.Fatal(err)
+		}
+		if config.Level != expected {
+			test.Errorf("Expected %q to set the %s level. Found: %s", args, logs.LogLevels.Label(expected), logs.LogLevels.Label(config.Level))
+		}
+	}
+}
+
+func TestFlagConfigErrors(test *testing.T) {
+	for _, args := range [][]string{
+		{"--log-level", "loud"},
+		{"--log-level", ".db=DEBUG"},
+		{"--log-format", "xml"},
+		{"--log-format", "color", "--log-output", "app.log"},
+		{"-v=false"},
+	} {
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		logs.FlagConfig(fs)
+		if err := fs.Parse(args); err == nil {
+			test.Errorf("Expected an error for %v", args)
+		}
+	}
+}
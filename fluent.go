@@ -0,0 +1,134 @@
+package gologsgo
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// fluentChunkSize is the length of the base64 encoded chunk ids sent with each
+// entry when acks are required. Being under 32 bytes, the chunk id is always
+// encoded as a fixstr and is always the last fluentChunkSize bytes of an entry.
+const fluentChunkSize = 24
+
+// FluentHandlerConfig configures a Fluentd forward protocol handler
+type FluentHandlerConfig struct {
+	// NetworkHandlerConfig configures the connection to Fluentd or Fluent Bit.
+	// Network defaults to "tcp". Encoder and Framing are ignored.
+	NetworkHandlerConfig
+	// TagPrefix is prepended to the logger label to form each record's tag. The
+	// root logger (with an empty label) is tagged with just the TagPrefix, or
+	// "root" if there is none.
+	TagPrefix string
+	// RequireAck asks the collector to acknowledge each entry. Unacknowledged
+	// entries are resent after reconnecting.
+	RequireAck bool
+}
+
+// NewFluentHandler returns a NetworkHandler that speaks the Fluentd forward
+// protocol (https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1),
+// sending each entry in Message mode tagged with the logger label.
+func NewFluentHandler(config FluentHandlerConfig) (*NetworkHandler, error) {
+	if len(config.Network) == 0 {
+		config.Network = "tcp"
+	}
+	if strings.HasPrefix(config.Network, "udp") || config.Network == "unixgram" {
+		return nil, fmt.Errorf("The forward protocol requires a stream network. Found: %s", config.Network)
+	}
+
+	prefix := config.TagPrefix
+	requireAck := config.RequireAck
+	config.Encoder = func(msg LogMessage) ([]byte, error) {
+		return fluentEncode(msg, fluentTag(prefix, msg.Logger), requireAck)
+	}
+	config.Framing = func(data []byte) []byte {
+		return data
+	}
+
+	h, err := NewNetworkHandler(config.NetworkHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+	if requireAck {
+		h.confirm = func(conn net.Conn, data []byte) error {
+			return fluentConfirm(conn, data, h.config.WriteTimeout)
+		}
+	}
+	return h, nil
+}
+
+func fluentTag(prefix string, label string) string {
+	switch {
+	case len(label) == 0 && len(prefix) == 0:
+		return "root"
+	case len(label) == 0:
+		return prefix
+	case len(prefix) == 0:
+		return label
+	default:
+		return prefix + "." + label
+	}
+}
+
+// fluentEncode encodes a LogMessage as a forward protocol Message mode entry:
+// [tag, time, record, option]
+func fluentEncode(msg LogMessage, tag string, requireAck bool) ([]byte, error) {
+	b := make([]byte, 0, 128+len(msg.Message))
+	if requireAck {
+		b = appendMsgpackArrayHeader(b, 4)
+	} else {
+		b = appendMsgpackArrayHeader(b, 3)
+	}
+	b = appendMsgpackString(b, tag)
+	b = appendMsgpackEventTime(b, msg.Time)
+
+	// Fields can't override the standard keys
+	fields := make(map[string]interface{}, len(msg.Fields))
+	for k, v := range msg.Fields {
+		if k != "level" && k != "logger" && k != "message" {
+			fields[k] = v
+		}
+	}
+	b = appendMsgpackMapHeader(b, 3+len(fields))
+	for k, v := range fields {
+		b = appendMsgpackString(b, k)
+		b = appendMsgpackValue(b, v)
+	}
+	b = appendMsgpackString(b, "level")
+	b = appendMsgpackString(b, levelLabel(msg))
+	b = appendMsgpackString(b, "logger")
+	b = appendMsgpackString(b, msg.Logger)
+	b = appendMsgpackString(b, "message")
+	b = appendMsgpackString(b, msg.Message)
+
+	if requireAck {
+		id := make([]byte, fluentChunkSize*3/4)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		b = appendMsgpackMapHeader(b, 1)
+		b = appendMsgpackString(b, "chunk")
+		b = appendMsgpackString(b, base64.StdEncoding.EncodeToString(id))
+	}
+
+	return b, nil
+}
+
+// fluentConfirm waits for the collector to acknowledge the chunk id at the end of
+// an entry
+func fluentConfirm(conn net.Conn, data []byte, timeout time.Duration) error {
+	chunk := string(data[len(data)-fluentChunkSize:])
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	response, err := readMsgpackStringMap(conn)
+	if err != nil {
+		return err
+	}
+	if response["ack"] != chunk {
+		return fmt.Errorf("Expected ack for chunk %s. Received: %v", chunk, response)
+	}
+	return nil
+}
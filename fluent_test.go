@@ -0,0 +1,76 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestFluentHandlerAck(test *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		test.Fatalf("Unable to listen: %s", err)
+	}
+	defer listener.Close()
+
+	entries := make(chan []byte, 10)
+	go func() {
+		defer close(entries)
+		conn, err := listener.Accept()
+		if nil != err {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buf)
+			if nil != err {
+				return
+			}
+			entry := append([]byte(nil), buf[:n]...)
+			entries <- entry
+			// Acknowledge the chunk id, which is the last 24 bytes of the entry:
+			// {"ack": "<chunk>"}
+			ack := append([]byte{0x81, 0xa3, 'a', 'c', 'k', 0xa0 | 24}, entry[n-24:]...)
+			conn.Write(ack)
+		}
+	}()
+
+	handler, err := logs.NewFluentHandler(logs.FluentHandlerConfig{
+		NetworkHandlerConfig: logs.NetworkHandlerConfig{
+			Address: listener.Addr().String(),
+		},
+		TagPrefix:  "app",
+		RequireAck: true,
+	})
+	if nil != err {
+		test.Fatalf("Error creating FluentHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("An info log message")
+	logger.Warn("A warn log message")
+
+	if err := handler.Close(); nil != err {
+		test.Errorf("Error closing FluentHandler: %s", err)
+	}
+
+	count := 0
+	for entry := range entries {
+		count++
+		if !bytes.Contains(entry, []byte("app.main")) {
+			test.Errorf("Expected entry to be tagged app.main: %q", entry)
+		}
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		test.Errorf("Expected 2 acknowledged entries. Found: %d", count)
+	}
+}
@@ -3,6 +3,7 @@ package gologsgo
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -10,8 +11,8 @@ import (
 	"runtime"
 	"strings"
 	"sync"
-
-	"github.com/fatih/color"
+	"sync/atomic"
+	"time"
 )
 
 var defaultLeveledLogHandler LeveledLogHandler
@@ -53,40 +54,38 @@ func init() {
 	defaultLeveledLogHandler = LeveledLogHandler{
 		Format:     "%s [%s]: %s",
 		RootFormat: "%s: %s",
-		Levels: map[LogLevel]Formatter{
-			Trace: greyString,
-			Debug: greyString,
-			Info:  color.WhiteString,
-			Warn:  color.YellowString,
-			Error: color.RedString,
-		},
+		Levels:     themes["default"],
 	}
 }
 
 type LogLevel int
 
+// UnmarshalJSON accepts a level label, such as "DEBUG", the number of a level, or
+// null for NotSet
 func (ll *LogLevel) UnmarshalJSON(b []byte) error {
 	var i interface{}
 	if err := json.Unmarshal(b, &i); err != nil {
 		return err
 	}
 
-	switch i.(type) {
-	case int:
-		ord := i.(int)
-		if ord > 0 && ord < len(LogLevels.order) {
-			*ll = LogLevel(ord)
+	switch value := i.(type) {
+	case float64:
+		// Numbers are decoded as float64, and are written by MarshalJSON() for
+		// levels without a label
+		if ord := LogLevel(value); float64(ord) == value && len(LogLevels.Label(ord)) > 0 {
+			*ll = ord
+			return nil
 		}
-		return nil
 	case string:
-		label := strings.ToUpper(i.(string))
-		level, ok := LogLevels.Level(label)
+		label := strings.ToUpper(value)
+		level, ok := parseLevel(label)
 		if ok {
 			*ll = level
 			return nil
 		}
 	case nil:
 		*ll = NotSet
+		return nil
 	default:
 		// Do nothing. We'll be returning an error
 	}
@@ -94,6 +93,18 @@ func (ll *LogLevel) UnmarshalJSON(b []byte) error {
 	return fmt.Errorf("Invalid JSON value for LogLevel %s", i)
 }
 
+// MarshalJSON writes a level as its label, so it can be read by UnmarshalJSON().
+// NotSet is written as null, and a level without a label as its number.
+func (ll LogLevel) MarshalJSON() ([]byte, error) {
+	if ll == NotSet {
+		return []byte("null"), nil
+	}
+	if label := LogLevels.Label(ll); len(label) > 0 {
+		return json.Marshal(label)
+	}
+	return json.Marshal(int(ll))
+}
+
 // Log Constants
 // NotSet is literally our "zero value"
 // NOTE: go does _not_ recommend using ALL_CAPS for constants, as these
@@ -122,24 +133,41 @@ type orderedLogLevels struct {
 	labels        map[LogLevel]string
 	indexCache    map[LogLevel]int
 	ordinalsCache map[string]LogLevel
+	// aliases are labels that were replaced by SetLabel(), which are still
+	// accepted by Level()
+	aliases map[string]LogLevel
+	mu      sync.RWMutex
 }
 
 func (ll *orderedLogLevels) Label(level LogLevel) string {
+	ll.mu.RLock()
+	defer ll.mu.RUnlock()
 	return ll.labels[level]
 }
 
+// Level returns the level with a label, ignoring case. Labels replaced by
+// SetLabel() are still accepted.
 func (ll *orderedLogLevels) Level(label string) (LogLevel, bool) {
-	if nil == ll.ordinalsCache {
-		ll.ordinalsCache = make(map[string]LogLevel)
-	}
-
+	ll.mu.RLock()
 	lvl, ok := ll.ordinalsCache[label]
+	ll.mu.RUnlock()
 	if ok {
 		return lvl, true
 	}
 
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+	if nil == ll.ordinalsCache {
+		ll.ordinalsCache = make(map[string]LogLevel)
+	}
 	for k, v := range ll.labels {
-		if v == label {
+		if strings.EqualFold(v, label) {
+			ll.ordinalsCache[label] = k
+			return k, true
+		}
+	}
+	for alias, k := range ll.aliases {
+		if strings.EqualFold(alias, label) {
 			ll.ordinalsCache[label] = k
 			return k, true
 		}
@@ -149,6 +177,8 @@ func (ll *orderedLogLevels) Level(label string) (LogLevel, bool) {
 }
 
 func (ll *orderedLogLevels) Index(level LogLevel) (int, bool) {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
 	if nil == ll.indexCache {
 		ll.indexCache = make(map[LogLevel]int)
 	}
@@ -198,6 +228,17 @@ type LogMessage struct {
 	LevelLabel string
 	Logger     string
 	Message    string
+	// Format is the unformatted format string the Message was built from. It is
+	// the same for every occurrence of a log statement, so it works as a
+	// fingerprint.
+	Format string
+	Time   time.Time
+	// Fields holds the fields attached to the logger and its parents. It is nil
+	// when there are none.
+	Fields map[string]interface{}
+	// Stack is the stack trace of the log call, most recent call first, for
+	// handlers that capture one. It is nil otherwise.
+	Stack []StackFrame
 }
 
 // LogHandler receives a LogMessage and ensures it is properly written to the logs.
@@ -205,8 +246,8 @@ type LogMessage struct {
 // color coded log messages to stdout with timestamps.
 type LogHandler func(LogMessage)
 
-// DefaultLogHandler is a LogHandler that writes color coded log messages to stdout with
-// UTC timestamps.
+// DefaultLogHandler is a LogHandler that writes color coded log messages to stderr,
+// timestamped by the standard library's log flags.
 func DefaultLogHandler(msg LogMessage) {
 	defaultLeveledLogHandler.LogHandler(msg)
 }
@@ -217,13 +258,42 @@ type LeveledLogHandler struct {
 	Format     string
 	RootFormat string
 	Levels     map[LogLevel]Formatter
+	// Prefixes, when set, tags each line with a short per-logger prefix
+	Prefixes *LoggerPrefixes
+	// Logger receives the formatted lines. Defaults to the standard library's
+	// default logger, which writes to stderr.
+	Logger *log.Logger
+	// TimeFormat, when set, makes the handler timestamp each line itself from
+	// the entry's Time, instead of relying on the flags of Logger. It is a time
+	// layout or one of the special formats, such as TimeEpochMillis.
+	TimeFormat string
+	// Location, when set, is the timezone timestamps are rendered in, such as
+	// time.UTC. The handler then timestamps each line itself, in the layout of
+	// the standard library's log flags unless TimeFormat is set.
+	Location *time.Location
+	// ColorMode decides whether Levels and Prefixes colors are written. Defaults
+	// to ColorAuto, which colors output only when Logger writes to a terminal.
+	ColorMode ColorMode
+	// ExpandFields writes each field on its own indented line below the message,
+	// instead of as key=value pairs after it
+	ExpandFields bool
+	// SingleLine escapes newlines and other control characters in messages and
+	// fields, so each entry is exactly one line. It overrides ExpandFields.
+	SingleLine bool
+	// Stack, when set, renders the captured stack of entries that have one
+	Stack *StackFormat
+	// Symbols, when set, prefixes entries with a symbol for their level, such as
+	// DefaultSymbols. SymbolMode decides whether they are written, and defaults
+	// to ColorAuto, which writes them only to terminals.
+	Symbols    map[LogLevel]string
+	SymbolMode ColorMode
 }
 
 func (h *LeveledLogHandler) LogHandler(msg LogMessage) {
 	var levelFn Formatter
 	lvl := msg.Level
 	for {
-		levelFn := h.Levels[lvl]
+		levelFn = h.Levels[lvl]
 		if nil != levelFn {
 			break
 		}
@@ -234,65 +304,313 @@ func (h *LeveledLogHandler) LogHandler(msg LogMessage) {
 		lvl = prev
 	}
 
-	if nil == levelFn {
-		// TODO: find the Formatter for the next lower log level
+	logger := h.Logger
+	if nil == logger {
+		// Without a Logger, the standard library's logger is written to, unless
+		// HijackStdlib() has redirected it to a Logger
+		logger = stdlibLogger()
+	}
+	var w io.Writer
+	if nil != logger {
+		w = logger.Writer()
+	} else {
+		w = log.Writer()
+	}
+	colored := h.ColorMode.colored(w)
+	if nil == levelFn || !colored {
 		levelFn = fmt.Sprintf
 	}
 
-	if len(h.RootFormat) > 0 && len(msg.Logger) == 0 {
-		log.Println(levelFn(
-			h.RootFormat,
-			strings.ToUpper(msg.LevelLabel),
-			msg.Message,
+	message := msg.Message
+	if h.SingleLine {
+		message = EscapeLine(message)
+		if len(msg.Fields) > 0 {
+			message = message + " " + EscapeLine(formatFields(msg.Fields))
+		}
+	} else if len(msg.Fields) > 0 && h.ExpandFields {
+		for _, k := range fieldKeys(msg.Fields) {
+			message += fmt.Sprintf("\n    %s: %v", k, msg.Fields[k])
+		}
+	} else if len(msg.Fields) > 0 {
+		message = message + " " + formatFields(msg.Fields)
+	}
+	if nil != h.Stack && len(msg.Stack) > 0 {
+		message = message + appendStack(*h.Stack, msg.Stack, h.SingleLine)
+	}
+
+	prefix := ""
+	if nil != h.Prefixes {
+		prefix = h.Prefixes.Prefix(msg.Logger)
+		if !colored {
+			prefix = stripColors(prefix)
+		}
+	}
+
+	println := log.Println
+	if nil != logger {
+		println = logger.Println
+	}
+	if len(h.TimeFormat) > 0 || nil != h.Location {
+		t := msg.Time
+		if nil != h.Location {
+			t = t.In(h.Location)
+		}
+		format := h.TimeFormat
+		if len(format) == 0 {
+			format = "2006/01/02 15:04:05"
+		}
+		ts := FormatTime(t, format)
+		println = func(v ...interface{}) {
+			fmt.Fprintln(w, ts+" "+fmt.Sprint(v...))
+		}
+	}
+
+	rootFormat, format := h.RootFormat, h.Format
+	if symbol := h.Symbols[msg.Level]; len(symbol) > 0 && h.SymbolMode.symbolsShown(w) {
+		// Escape any % in the symbol - it becomes part of the format
+		symbol = strings.Replace(symbol, "%", "%%", -1) + " "
+		if len(rootFormat) > 0 {
+			rootFormat = symbol + rootFormat
+		}
+		format = symbol + format
+	}
+
+	if len(rootFormat) > 0 && len(msg.Logger) == 0 {
+		println(prefix + levelFn(
+			rootFormat,
+			levelLabel(msg),
+			message,
 		))
 		return
 	}
 
-	log.Println(levelFn(
-		h.Format,
-		strings.ToUpper(msg.LevelLabel),
+	println(prefix + levelFn(
+		format,
+		levelLabel(msg),
 		msg.Logger,
-		msg.Message,
+		message,
 	))
 }
 
-// greyString is a private method supporting the DefaultLogHandler
-func greyString(format string, args ...interface{}) string {
-	return "\x1b[90;1m" + fmt.Sprintf(format, args...) + "\033[0m"
-}
-
 type RootLogConfig struct {
 	Loggers map[string]*LogConfig `json:"loggers"`
 	Level   LogLevel              `json:"level"`
 	Label   string                `json:"label"`
+	// Handler selects a handler registered with RegisterHandler(). It is ignored
+	// when LogHandler or LogHandlerE is set.
+	Handler *HandlerConfig `json:"handler,omitempty"`
+	// Handlers declares outputs that every entry is written to. It is ignored
+	// when LogHandler or LogHandlerE is set, and may not be used with Handler.
+	// Handler and Handlers are checked when a config is parsed, and built by New(),
+	// which adds what they open to Closers.
+	Handlers []OutputConfig `json:"handlers,omitempty"`
 	// Don't try to Marshall/Unmarshall a function
 	LogHandler LogHandler `json:"-"`
+	// LogHandlerE, when set, is used instead of LogHandler. Errors it returns are
+	// passed to ErrorCallback.
+	LogHandlerE LogHandlerE `json:"-"`
+	// ErrorCallback is called when LogHandlerE fails to deliver a record. Defaults
+	// to DefaultErrorCallback.
+	ErrorCallback ErrorCallback `json:"-"`
+	// Closers are closed when the root Logger is closed. See Logger.RegisterCloser().
+	Closers []io.Closer `json:"-"`
+	// TimeFormat, when set, is the timestamp format of the default handler. It is
+	// a time layout or one of the special formats, such as "epoch-millis".
+	TimeFormat string `json:"timeFormat,omitempty"`
+	// Timezone, when set, is the timezone of the default handler's timestamps:
+	// "UTC", "Local" or an IANA name such as "America/New_York"
+	Timezone string `json:"timezone,omitempty"`
+	// Colors, when set, configures the colors of the default handler
+	Colors *ColorConfig `json:"colors,omitempty"`
+	// LevelLabels replaces the labels printed for levels, keyed by their current
+	// labels, such as {"WARN": "WARNING"}. The new labels are also accepted by
+	// the level settings of the config. They are set globally by New(). See
+	// LogLevels.SetLabel().
+	LevelLabels map[string]string `json:"levelLabels,omitempty"`
+	// LabelFormat, when set, controls how logger labels are rendered
+	LabelFormat *LabelFormat `json:"labelFormat,omitempty"`
+	// CaptureStacks, when set, captures the stack of entries at or above the
+	// level, for handlers that render stacks. See StackHook().
+	CaptureStacks LogLevel `json:"captureStacks,omitempty"`
+	// Symbols, when set, prefixes the entries of the default handler with a
+	// symbol for their level
+	Symbols *SymbolConfig `json:"symbols,omitempty"`
+	// Preset, when set, replaces the default handler and level with those of a
+	// preset: "development" or "production". See NewDevelopment() and
+	// NewProduction().
+	Preset string `json:"preset,omitempty"`
+	// Hooks can modify or drop each LogMessage before it reaches a handler. See Hook.
+	Hooks []Hook `json:"-"`
+	// Redaction, when set, masks sensitive values before any other hook runs.
+	// See RedactionHook().
+	Redaction *RedactionConfig `json:"redaction,omitempty"`
+	// Sample, when set, samples the entries of the logger tree. See
+	// SampleOptions.
+	Sample *SampleOptions `json:"sample,omitempty"`
+	// RateLimitPerSec, when positive, limits the entries of the logger tree to
+	// this many a second. See RateLimit().
+	RateLimitPerSec float64 `json:"rateLimitPerSec,omitempty"`
+	// Profiles are partial configs for different environments, such as "dev",
+	// "staging" and "prod", merged over the rest of the config when selected.
+	// See WithProfile().
+	Profiles map[string]*RootLogConfig `json:"profiles,omitempty"`
+	// Profile selects one of Profiles. When it is empty, the ProfileEnv
+	// environment variable selects one.
+	Profile string `json:"profile,omitempty"`
+	// Rules set the levels of loggers whose names match regular expressions. See
+	// LevelRule.
+	Rules []LevelRule `json:"rules,omitempty"`
+	// Updates, when set, delivers new configs whose levels are applied to the
+	// logger tree as they arrive, so levels can be changed by a control plane,
+	// such as Redis, without a restart. See Logger.ApplyLevels(). Only levels are
+	// applied, so the Closers of each config are closed. It is read until it is
+	// closed or the Logger is closed.
+	Updates <-chan *RootLogConfig `json:"-"`
 }
 
 type LogConfig struct {
 	Loggers map[string]*LogConfig `json:"loggers"`
 	Level   LogLevel              `json:"level"`
+	// Handler selects a handler registered with RegisterHandler() for this
+	// logger and its children, instead of the handler of its parent. It is
+	// ignored when LogHandler is set.
+	Handler *HandlerConfig `json:"handler,omitempty"`
+	// LogHandler, when set, is used by this logger and its children instead of
+	// the handler of its parent
+	LogHandler LogHandler `json:"-"`
+	// Sample, when set, samples the entries of this logger and of the children
+	// that share its handler. See SampleOptions.
+	Sample *SampleOptions `json:"sample,omitempty"`
+	// RateLimitPerSec, when positive, limits the entries of this logger and of
+	// the children that share its handler to this many a second. See
+	// RateLimit().
+	RateLimitPerSec float64 `json:"rateLimitPerSec,omitempty"`
+}
+
+// MarshalJSON writes the config in the form read by JsonConfig(), leaving out the
+// settings that aren't set, so a config built in code, or changed by operators,
+// can be saved and read back. Settings that are set in code, such as LogHandler,
+// Hooks and Updates, are not written.
+func (config RootLogConfig) MarshalJSON() ([]byte, error) {
+	type plain RootLogConfig
+	return json.Marshal(struct {
+		Loggers map[string]*LogConfig `json:"loggers,omitempty"`
+		Level   LogLevel              `json:"level,omitempty"`
+		Label   string                `json:"label,omitempty"`
+		plain
+	}{config.Loggers, config.Level, config.Label, plain(config)})
 }
 
-// JsonConfig creates a RootLogConfig from JSON data
+// MarshalJSON writes the config in the form read by JsonConfig(), leaving out the
+// settings that aren't set. LogHandler is not written.
+func (config LogConfig) MarshalJSON() ([]byte, error) {
+	type plain LogConfig
+	return json.Marshal(struct {
+		Loggers map[string]*LogConfig `json:"loggers,omitempty"`
+		Level   LogLevel              `json:"level,omitempty"`
+		plain
+	}{config.Loggers, config.Level, plain(config)})
+}
+
+// JsonConfig creates a RootLogConfig from JSON data. Levels may be written with
+// the labels set by "levelLabels", which are only applied by New().
 func JsonConfig(data []byte) (*RootLogConfig, error) {
+	var config *RootLogConfig
+	err := withConfigLabels(data, func() error {
+		var err error
+		config, err = jsonConfig(data)
+		return err
+	})
+	return config, err
+}
+
+// jsonConfig parses and checks a config. It is called by withConfigLabels().
+func jsonConfig(data []byte) (*RootLogConfig, error) {
 	config := RootLogConfig{}
 	err := json.Unmarshal(data, &config)
 	if err != nil {
 		return nil, err
 	}
+	if err := config.checkHandlers(); err != nil {
+		return nil, err
+	}
+	if nil != config.Redaction {
+		if _, err := RedactionHook(*config.Redaction); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.Timezone) > 0 {
+		if _, err := time.LoadLocation(config.Timezone); err != nil {
+			return nil, fmt.Errorf("Invalid timezone: %s", err)
+		}
+	}
+	if nil != config.Colors {
+		if _, err := NewTheme(*config.Colors); err != nil {
+			return nil, err
+		}
+	}
+	if err := validatePreset(config.Preset); err != nil {
+		return nil, err
+	}
+	if nil != config.Symbols {
+		if _, err := NewSymbols(*config.Symbols); err != nil {
+			return nil, err
+		}
+	}
+	if nil != config.LabelFormat {
+		if err := config.LabelFormat.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := compileRules(config.Rules); err != nil {
+		return nil, err
+	}
+	if err := validateVolumeControls(config.Sample, config.RateLimitPerSec); err != nil {
+		return nil, err
+	}
+	if err := validateLoggersVolumeControls(config.Loggers); err != nil {
+		return nil, err
+	}
+	if err := validateProfiles(config.Profiles); err != nil {
+		return nil, err
+	}
+	if _, err := config.WithProfile(""); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
 
-// FileConfig reads a file path and creates a RootLogConfig from it's JSON data
+// MapConfig creates a RootLogConfig from a decoded config document, such as a
+// section of an application's own config. It has the same structure as the JSON
+// accepted by JsonConfig().
+func MapConfig(cfg map[string]interface{}) (*RootLogConfig, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return JsonConfig(data)
+}
+
+// FileConfig reads a file path and creates a RootLogConfig from it's JSON data,
+// after expanding environment variables in it with ExpandEnv(). The file may
+// layer itself over shared files by listing them in "include" or "extends":
+//
+//	{"include": ["base-logging.json"], "loggers": {"db": {"level": "DEBUG"}}}
+//
+// Included files are read in order, each merged over the last, and the file is
+// merged over them: objects, such as "loggers", are merged key by key, and other
+// values, including lists, replace those of the files below. Relative paths are
+// relative to the directory of the including file, and included files may
+// include others.
 func FileConfig(configFile string) (*RootLogConfig, error) {
 	data, err := ioutil.ReadFile(configFile)
 	if err != nil {
 		return nil, err
 	}
 
-	return JsonConfig(data)
+	return jsonFileConfig(configFile, data)
 }
 
 // PathEnvConfig gets a file path from the specified environment variable, reads it's contents
@@ -305,71 +623,23 @@ func PathEnvConfig(env string) (*RootLogConfig, error) {
 // and uses them to build a RootLogConfig. After the prefix, a single underscore ("_")
 // is treated as a word seperator. Two successive underscores ("__") are treated as
 // a struct seperator - the left side is the parent struct, the right is a field name.
+// Names are matched regardless of case: keys in a single case, such as JSON_CHILD or
+// json_child, are converted to camelCase, and keys in mixed case, such as myChild,
+// are used as they are. Values are converted to the type of their setting, so
+// numbers, booleans, JSON arrays and JSON objects may be set. The variable matching
+// the prefix exactly may hold the whole config as a JSON object.
+//
+// <PREFIX>_OUTPUT, <PREFIX>_FORMAT and <PREFIX>_COLOR select the handler, as a single
+// output of Handlers. OUTPUT is "stderr" (the default), "stdout" or the path of a
+// file, FORMAT is one of the formats of an OutputConfig, such as "json", and COLOR
+// is "true" or "false" to turn the colors of the "color" format on or off. They
+// may not be combined with the "handler" and "handlers" settings. All the variables
+// that can't be parsed are returned as ConfigErrors, named by the variable.
 func EnvPrefixConfig(prefix string) (*RootLogConfig, error) {
-	cfg := make(map[string]interface{})
-	// Support JSON in environment variable matching the prefix exactly
-	rootenvvalue := os.Getenv(prefix)
-	// Parse things that look like JSON
-	if len(rootenvvalue) > 0 && []rune(rootenvvalue)[0] == []rune("{")[0] {
-		err := json.Unmarshal([]byte(rootenvvalue), &cfg)
-		if err != nil {
-			log.Println(fmt.Sprintf("Unable to parse %s as JSON. %s\n%s", prefix, err, rootenvvalue))
-		}
-	}
-
-	for _, envpair := range os.Environ() {
-		fullprefix := fmt.Sprintf("%s_", prefix)
-		if strings.HasPrefix(envpair, fullprefix) {
-			envsplit := strings.Split(envpair, "=")
-			envname, envvalue := envsplit[0], envsplit[1]
-
-			envkeys := strings.Split(strings.TrimPrefix(envname, fullprefix), "__")
-			lvlCfg := cfg
-			for i, k := range envkeys {
-				// Convert k from ENV_CASE to camelCase
-				key := strings.Replace(
-					strings.Join(
-						strings.Split(
-							strings.Title(
-								strings.ToLower(
-									strings.Replace(k, "_", " ", -1), // replace all
-								),
-							),
-							" ",
-						),
-						"",
-					),
-					string(k[0]),
-					strings.ToLower(string(k[0])),
-					1,
-				)
-
-				if i == len(envkeys)-1 {
-					// Set the value
-					// Parse things that look like JSON
-					if []rune(envvalue)[0] == []rune("{")[0] {
-						v := make(map[string]interface{})
-						err := json.Unmarshal([]byte(envvalue), &v)
-						if err == nil {
-							lvlCfg[key] = v
-							continue
-						}
-						log.Println(fmt.Sprintf("Unable to parse %s as JSON. %s", envname, err))
-					}
-
-					// Fallback to just setting the value
-					lvlCfg[key] = envvalue
-				} else {
-					// descend in to the child object
-					if _, ok := lvlCfg[key]; !ok {
-						lvlCfg[key] = make(map[string]interface{})
-					}
-					lvlCfg = lvlCfg[key].(map[string]interface{})
-				}
-			}
-		}
+	cfg, err := envPrefixMap(prefix)
+	if err != nil {
+		return nil, err
 	}
-
 	config, err := json.Marshal(cfg)
 	if err != nil {
 		return nil, err
@@ -378,24 +648,41 @@ func EnvPrefixConfig(prefix string) (*RootLogConfig, error) {
 	return JsonConfig(config)
 }
 
-// TODO: Implement a NamedConfig method that takes defaults, searches for files in the
-// current working directory, etc/, and ~/, uses environment vairables, and parses CLI args
-
-// TODO: Implement an optional channel as part of the RootLogConfig on which to receive updated
-// RootLogConfig instances so log levels can be updated via Redis or some other means that
-// didn't entail a restart. This enables turning on debug or trace level logging for a code path
-// that is exhibiting errors.
-
 // Logger is the primary structure in this package. It supplies the log level functions.
 // A Logger only has a `parent` if it was created by Logger.ChildLogger(). If so, it's
 // `logConfig` will be a reference to it's config from the parent - the only place it
 // can get a config.
 type Logger struct {
+	// level is the effective level. It is read and written atomically, so it can
+	// be changed while logging. See ApplyLevels().
+	level      int32
 	parent     *Logger
 	logConfig  *LogConfig
 	label      string
 	logHandler LogHandler
 	children   map[string]*Logger
+	captures   []*Capture
+	hooks      []Hook
+	fields     map[string]field
+	bootstrap  *bootstrapState
+	lifecycle  *lifecycle
+	// labelFormat, when set, renders label as LogMessage.Logger
+	labelFormat *LabelFormat
+	rendered    string
+	// name is the name the Logger was created with by its parent's ChildLogger()
+	name string
+	// temporary is the level set by SetLevelFor(), until it expires. childlock
+	// guards it.
+	temporary *temporaryLevel
+	// rules are the compiled Rules of the root Logger's config. childlock guards
+	// them.
+	rules []levelRule
+	// handler describes the handler of the Logger for DumpConfig(). It is empty
+	// when the handler is its parent's.
+	handler string
+	// base is the Logger of the tree that a Logger returned by WithFields() logs
+	// as. It is nil for the Loggers of the tree.
+	base *Logger
 }
 
 // New returns a new root Logger
@@ -403,8 +690,24 @@ func New(logConfig *RootLogConfig) *Logger {
 	if logConfig == nil {
 		logConfig = &RootLogConfig{}
 	}
+	if withProfile, err := logConfig.WithProfile(""); err != nil {
+		lastResortWarning("%s. Using the config without a profile.", err)
+	} else {
+		logConfig = withProfile
+	}
+
+	if err := setLabels(logConfig.LevelLabels); err != nil {
+		lastResortWarning("%s", err)
+	}
 
-	if logConfig.Level == NotSet {
+	if err := validatePreset(logConfig.Preset); err != nil {
+		lastResortWarning("%s. Using the default handler.", err)
+		logConfig.Preset = ""
+	}
+
+	if logConfig.Level == NotSet && logConfig.Preset == PresetDevelopment {
+		logConfig.Level = Debug
+	} else if logConfig.Level == NotSet {
 		// Default to the INFO log level
 		logConfig.Level = Info
 	}
@@ -414,10 +717,12 @@ func New(logConfig *RootLogConfig) *Logger {
 		logConfig.Label = ""
 	}
 
-	if logConfig.LogHandler == nil {
-		// Default to the INFO log level
-		logConfig.LogHandler = DefaultLogHandler
+	handler := describeRootHandler(logConfig)
+	if err := logConfig.resolveHandler(); err != nil {
+		lastResortWarning("%s. Using the default handler.", err)
+		handler = "default"
 	}
+	logConfig.LogHandler = rootLogHandler(logConfig)
 
 	logger := &Logger{
 		parent: nil,
@@ -425,17 +730,132 @@ func New(logConfig *RootLogConfig) *Logger {
 			Loggers: logConfig.Loggers,
 			Level:   logConfig.Level,
 		},
-		label:      logConfig.Label,
-		logHandler: logConfig.LogHandler,
-		children:   make(map[string]*Logger),
+		level:       int32(logConfig.Level),
+		label:       logConfig.Label,
+		logHandler:  volumeControls(logConfig.LogHandler, logConfig.Sample, logConfig.RateLimitPerSec),
+		children:    make(map[string]*Logger),
+		hooks:       rootHooks(logConfig),
+		lifecycle:   &lifecycle{},
+		handler:     handler,
+		labelFormat: rootLabelFormat(logConfig.LabelFormat),
+	}
+	logger.rendered = logger.labelFormat.render(logger.label)
+	if len(logConfig.Rules) > 0 {
+		rules, err := compileRules(logConfig.Rules)
+		if err != nil {
+			lastResortWarning("%s. Ignoring the rule.", err)
+		}
+		logger.rules = rules
+	}
+	for _, closer := range logConfig.Closers {
+		logger.RegisterCloser(closer)
+	}
+	if nil != logConfig.Updates {
+		logger.RegisterCloser(logger.consumeUpdates(logConfig.Updates))
 	}
 
 	return logger
 }
 
+// rootLogHandler returns the LogHandler of a root Logger: LogHandlerE, the
+// LogHandler set or resolved for the config, the production handler, or a
+// LeveledLogHandler with the config's formatting settings
+func rootLogHandler(logConfig *RootLogConfig) LogHandler {
+	switch {
+	case logConfig.LogHandlerE != nil:
+		return logConfig.LogHandlerE.LogHandler(logConfig.ErrorCallback)
+	case logConfig.LogHandler != nil:
+		return logConfig.LogHandler
+	case logConfig.Preset == PresetProduction:
+		return productionHandler(logConfig)
+	case len(logConfig.TimeFormat) > 0 || len(logConfig.Timezone) > 0 || nil != logConfig.Colors || nil != logConfig.Symbols || logConfig.Preset == PresetDevelopment:
+		h := leveledLogHandler(logConfig)
+		return h.LogHandler
+	default:
+		return DefaultLogHandler
+	}
+}
+
+// leveledLogHandler returns the default LeveledLogHandler with the formatting
+// settings of a config. Invalid settings are reported and left at their
+// defaults.
+func leveledLogHandler(logConfig *RootLogConfig) LeveledLogHandler {
+	h := defaultLeveledLogHandler
+	if logConfig.Preset == PresetDevelopment {
+		h.TimeFormat = developmentTimeFormat
+		h.ExpandFields = true
+	}
+	if nil != logConfig.Colors {
+		theme, err := NewTheme(*logConfig.Colors)
+		if err != nil {
+			lastResortWarning("%s. Using the default colors.", err)
+		} else {
+			h.Levels = theme
+		}
+		h.ColorMode = logConfig.Colors.Mode
+	}
+	if nil != logConfig.Symbols {
+		symbols, err := NewSymbols(*logConfig.Symbols)
+		if err != nil {
+			lastResortWarning("%s. Symbols are disabled.", err)
+		} else {
+			h.Symbols = symbols
+			h.SymbolMode = logConfig.Symbols.Mode
+		}
+	}
+	if len(logConfig.TimeFormat) > 0 {
+		h.TimeFormat = logConfig.TimeFormat
+	}
+	if len(logConfig.Timezone) > 0 {
+		loc, err := time.LoadLocation(logConfig.Timezone)
+		if err != nil {
+			lastResortWarning("Unknown timezone %q. Using UTC.", logConfig.Timezone)
+			loc = time.UTC
+		}
+		h.Location = loc
+	}
+	return h
+}
+
+// rootLabelFormat returns a copy of a config's LabelFormat, or nil, which
+// renders labels in full, if it is not set or invalid
+func rootLabelFormat(labelFormat *LabelFormat) *LabelFormat {
+	if nil == labelFormat {
+		return nil
+	}
+	if err := labelFormat.Validate(); err != nil {
+		lastResortWarning("%s. Labels will be rendered in full.", err)
+		return nil
+	}
+	format := *labelFormat
+	return &format
+}
+
+// rootHooks returns the hooks of a config, preceded by its redaction and
+// followed by stack capture
+func rootHooks(logConfig *RootLogConfig) []Hook {
+	hooks := logConfig.Hooks
+	if nil != logConfig.Redaction {
+		redact, err := RedactionHook(*logConfig.Redaction)
+		if err != nil {
+			// Never let unredacted entries through because of a bad rule
+			redact = func(*LogMessage) bool { return false }
+			lastResortError("%s. All entries will be dropped.", err)
+		}
+		hooks = append([]Hook{redact}, logConfig.Hooks...)
+	}
+	if logConfig.CaptureStacks != NotSet {
+		hooks = append(hooks[:len(hooks):len(hooks)], StackHook(logConfig.CaptureStacks))
+	}
+	return hooks
+}
+
 // Level returns the effective log level of the Logger below which log messages will be ignored
 func (logger *Logger) Level() LogLevel {
-	return logger.logConfig.Level
+	if nil != logger.base {
+		return logger.base.Level()
+	}
+	return LogLevel(atomic.LoadInt32(&logger.level))
 }
 
 // Label returns the label of the logger
@@ -450,7 +870,14 @@ func (logger *Logger) ChildLogger(name string) *Logger {
 	if len(name) < 1 {
 		panic(fmt.Errorf("Child loggers require a name"))
 	}
+	if nil != logger.base {
+		return logger.base.ChildLogger(name).WithFields(logger.ownFields())
+	}
 
+	if nil != logger.labelFormat && len(logger.labelFormat.Separator) > 0 {
+		// Names may also be written with the separator labels are rendered with
+		name = strings.Replace(name, logger.labelFormat.Separator, ".", -1)
+	}
 	if strings.Contains(name, ".") {
 		parts := strings.SplitN(name, ".", 2)
 		parent := logger.ChildLogger(parts[0])
@@ -462,14 +889,23 @@ func (logger *Logger) ChildLogger(name string) *Logger {
 	defer childlock.Unlock()
 	child, ok := logger.children[name]
 	if !ok {
-		config, ok := logger.logConfig.Loggers[name]
-		if !ok || nil == config {
-			config = &LogConfig{}
-		}
-
-		if config.Level == NotSet {
-			config.Level = logger.logConfig.Level
+		config := logger.childConfig(name)
+		level := logger.childLevel(name, config)
+
+		handler := logger.logHandler
+		handlerName := describeChildHandler(config)
+		if closer, err := config.resolveHandler(); err != nil {
+			lastResortWarning("%s. Using the handler of %q.", err, logger.label)
+			handlerName = ""
+		} else {
+			if nil != closer {
+				logger.RegisterCloser(closer)
+			}
+			if nil != config.LogHandler {
+				handler = config.LogHandler
+			}
 		}
+		handler = volumeControls(handler, config.Sample, config.RateLimitPerSec)
 
 		parts := []string{}
 		if len(logger.label) > 1 {
@@ -479,11 +915,19 @@ func (logger *Logger) ChildLogger(name string) *Logger {
 		label := strings.Join(parts, ".")
 
 		child = &Logger{
-			parent:     logger,
-			logConfig:  config,
-			label:      label,
-			logHandler: logger.logHandler,
-			children:   make(map[string]*Logger),
+			level:       int32(level),
+			parent:      logger,
+			name:        name,
+			logConfig:   config,
+			label:       label,
+			logHandler:  handler,
+			children:    make(map[string]*Logger),
+			hooks:       logger.hooks,
+			lifecycle:   logger.lifecycle,
+			labelFormat: logger.labelFormat,
+			rendered:    logger.labelFormat.render(label),
+			rules:       logger.rules,
+			handler:     handlerName,
 		}
 
 		logger.children[name] = child
@@ -557,17 +1001,46 @@ func (logger *Logger) PackageLogger(opts ...PackageLoggerOpts) *Logger {
 // log is a private method that supports all of the exported log level
 // methods
 func (logger *Logger) log(level LogLevel, format string, args ...interface{}) {
-	if level < logger.Level() {
+	capturing := atomic.LoadInt32(&activeCaptures) > 0
+	if level < logger.Level() && !capturing {
+		atomic.AddUint64(&filteredCount, 1)
 		return
 	}
 
-	msg := fmt.Sprintf(format, args...)
-	logger.logHandler(LogMessage{
+	logger.emit(LogMessage{
 		Level:      level,
 		LevelLabel: LogLevels.Label(level),
-		Logger:     logger.Label(),
-		Message:    msg,
-	})
+		Logger:     logger.rendered,
+		Message:    fmt.Sprintf(format, args...),
+		Format:     format,
+		Time:       time.Now(),
+		Fields:     logger.Fields(),
+	}, capturing)
+}
+
+// emit passes a LogMessage at or above the level of the Logger, or any
+// LogMessage while capturing, through the hooks to the handler of the Logger
+func (logger *Logger) emit(msg LogMessage, capturing bool) {
+	if !logger.runHooks(&msg) {
+		atomic.AddUint64(&hookDroppedCount, 1)
+		return
+	}
+
+	if capturing {
+		logger.capture(msg)
+	}
+	if msg.Level < logger.Level() {
+		atomic.AddUint64(&filteredCount, 1)
+		return
+	}
+
+	if logger.Closed() {
+		logger.lifecycle.lastResort(msg)
+		return
+	}
+
+	atomic.AddUint64(&emittedCount, 1)
+	logger.logHandler(msg)
 }
 
 // Trace logs a message at the TRACE level
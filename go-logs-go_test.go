@@ -3,6 +3,7 @@ package gologsgo_test
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"log"
 	"os"
 	"testing"
@@ -337,3 +338,45 @@ func TestPackageLogger(test *testing.T) {
 		test.Errorf("Expected log label to be go-logs-go_test for package logger. Found: %v", pkglogger.Label())
 	}
 }
+
+func TestConfigMarshalJSON(test *testing.T) {
+	config := &logs.RootLogConfig{
+		Level: logs.Info,
+		Loggers: map[string]*logs.LogConfig{
+			"db":   {Level: logs.Debug, Loggers: map[string]*logs.LogConfig{"sql": {Level: logs.Trace}}},
+			"http": {Handler: &logs.HandlerConfig{Name: "json-stderr"}, RateLimitPerSec: 10},
+		},
+		CaptureStacks: logs.Error,
+		Rules:         []logs.LevelRule{{Match: "cache$", Level: logs.Warn}},
+		Profiles:      map[string]*logs.RootLogConfig{"dev": {Level: logs.Debug}},
+		LogHandler:    func(logs.LogMessage) {},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		test.Fatal(err)
+	}
+	expected := `{"loggers":{"db":{"loggers":{"sql":{"level":"TRACE"}},"level":"DEBUG"},"http":{"handler":"json-stderr","rateLimitPerSec":10}},"level":"INFO","captureStacks":"ERROR","profiles":{"dev":{"level":"DEBUG"}},"rules":[{"match":"cache$","level":"WARN"}]}`
+	if string(data) != expected {
+		test.Errorf("Unexpected JSON:\n%s", data)
+	}
+
+	parsed, err := logs.StrictJsonConfig(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	again, _ := json.Marshal(parsed)
+	if string(again) != string(data) {
+		test.Errorf("Expected the config to round trip. Found:\n%s\nShould be:\n%s", again, data)
+	}
+
+	var level logs.LogLevel
+	if err := json.Unmarshal([]byte(`5`), &level); err != nil || level != logs.Warn {
+		test.Errorf("Expected levels to be read from numbers. Found: %d, %v", level, err)
+	}
+	if err := json.Unmarshal([]byte(`null`), &level); err != nil || level != logs.NotSet {
+		test.Errorf("Expected null to be NotSet. Found: %d, %v", level, err)
+	}
+	if err := json.Unmarshal([]byte(`99`), &level); err == nil {
+		test.Error("Expected an error for an unknown level number")
+	}
+}
@@ -1,8 +1,12 @@
 module github.com/big-squid/go-logs-go
 
-go 1.12
+go 1.21
 
 require (
-	github.com/big-squid/go-logging v0.0.2
-	github.com/fatih/color v1.6.0
+	github.com/BurntSushi/toml v0.3.0
+	github.com/fsnotify/fsnotify v1.4.7
+	github.com/hashicorp/hcl v1.0.0
+	github.com/mattn/go-isatty v0.0.4
 )
+
+require golang.org/x/sys v0.0.0-20190830142957-1e83adbbebd0 // indirect
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: admin.proto
+
+package logsgrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LogLevelAdmin_ListLoggers_FullMethodName = "/gologsgo.admin.v1.LogLevelAdmin/ListLoggers"
+	LogLevelAdmin_SetLevel_FullMethodName    = "/gologsgo.admin.v1.LogLevelAdmin/SetLevel"
+	LogLevelAdmin_ResetLevel_FullMethodName  = "/gologsgo.admin.v1.LogLevelAdmin/ResetLevel"
+)
+
+// LogLevelAdminClient is the client API for LogLevelAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LogLevelAdmin inspects and changes the levels of a live logger tree
+type LogLevelAdminClient interface {
+	// ListLoggers lists a logger and the descendants that have been created from
+	// it, sorted by name
+	ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error)
+	// SetLevel sets the level of a logger, optionally for a duration
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error)
+	// ResetLevel makes a logger inherit the level of its parent again
+	ResetLevel(ctx context.Context, in *ResetLevelRequest, opts ...grpc.CallOption) (*ResetLevelResponse, error)
+}
+
+type logLevelAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLogLevelAdminClient(cc grpc.ClientConnInterface) LogLevelAdminClient {
+	return &logLevelAdminClient{cc}
+}
+
+func (c *logLevelAdminClient) ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoggersResponse)
+	err := c.cc.Invoke(ctx, LogLevelAdmin_ListLoggers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logLevelAdminClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLevelResponse)
+	err := c.cc.Invoke(ctx, LogLevelAdmin_SetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logLevelAdminClient) ResetLevel(ctx context.Context, in *ResetLevelRequest, opts ...grpc.CallOption) (*ResetLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetLevelResponse)
+	err := c.cc.Invoke(ctx, LogLevelAdmin_ResetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LogLevelAdminServer is the server API for LogLevelAdmin service.
+// All implementations must embed UnimplementedLogLevelAdminServer
+// for forward compatibility.
+//
+// LogLevelAdmin inspects and changes the levels of a live logger tree
+type LogLevelAdminServer interface {
+	// ListLoggers lists a logger and the descendants that have been created from
+	// it, sorted by name
+	ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error)
+	// SetLevel sets the level of a logger, optionally for a duration
+	SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error)
+	// ResetLevel makes a logger inherit the level of its parent again
+	ResetLevel(context.Context, *ResetLevelRequest) (*ResetLevelResponse, error)
+	mustEmbedUnimplementedLogLevelAdminServer()
+}
+
+// UnimplementedLogLevelAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLogLevelAdminServer struct{}
+
+func (UnimplementedLogLevelAdminServer) ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoggers not implemented")
+}
+func (UnimplementedLogLevelAdminServer) SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLevel not implemented")
+}
+func (UnimplementedLogLevelAdminServer) ResetLevel(context.Context, *ResetLevelRequest) (*ResetLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetLevel not implemented")
+}
+func (UnimplementedLogLevelAdminServer) mustEmbedUnimplementedLogLevelAdminServer() {}
+func (UnimplementedLogLevelAdminServer) testEmbeddedByValue()                       {}
+
+// UnsafeLogLevelAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LogLevelAdminServer will
+// result in compilation errors.
+type UnsafeLogLevelAdminServer interface {
+	mustEmbedUnimplementedLogLevelAdminServer()
+}
+
+func RegisterLogLevelAdminServer(s grpc.ServiceRegistrar, srv LogLevelAdminServer) {
+	// If the following call pancis, it indicates UnimplementedLogLevelAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LogLevelAdmin_ServiceDesc, srv)
+}
+
+func _LogLevelAdmin_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoggersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogLevelAdminServer).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogLevelAdmin_ListLoggers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogLevelAdminServer).ListLoggers(ctx, req.(*ListLoggersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogLevelAdmin_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogLevelAdminServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogLevelAdmin_SetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogLevelAdminServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogLevelAdmin_ResetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogLevelAdminServer).ResetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogLevelAdmin_ResetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogLevelAdminServer).ResetLevel(ctx, req.(*ResetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LogLevelAdmin_ServiceDesc is the grpc.ServiceDesc for LogLevelAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LogLevelAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gologsgo.admin.v1.LogLevelAdmin",
+	HandlerType: (*LogLevelAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListLoggers",
+			Handler:    _LogLevelAdmin_ListLoggers_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LogLevelAdmin_SetLevel_Handler,
+		},
+		{
+			MethodName: "ResetLevel",
+			Handler:    _LogLevelAdmin_ResetLevel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
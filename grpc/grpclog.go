@@ -0,0 +1,141 @@
+package logsgrpc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	logs "github.com/big-squid/go-logs-go"
+	"google.golang.org/grpc/grpclog"
+)
+
+// NewLoggerV2 returns a grpclog.LoggerV2 that writes gRPC's internal logs, such
+// as transport errors and balancer chatter, to the "grpc" child of logger, so
+// their level is set by the "grpc" entry of the config:
+//
+//	{"loggers": {"grpc": {"level": "WARN", "loggers": {"transport": {"level": "ERROR"}}}}}
+//
+// Messages of gRPC components, which start with the component's name in
+// brackets, such as "[transport] ", are logged by the child of "grpc" named after
+// the component, without the prefix. gRPC's verbosity follows the level of the
+// "grpc" logger: V(0) is enabled at Info, V(1) and V(2) at Debug, and higher
+// verbosities at Trace. Fatal messages are logged at the Error level, and the
+// logger tree is closed before the process exits.
+func NewLoggerV2(logger *logs.Logger) grpclog.LoggerV2 {
+	return &loggerV2{logger: logger.ChildLogger("grpc")}
+}
+
+// SetLoggerV2 makes gRPC log through the "grpc" child of logger. See
+// NewLoggerV2(). Like grpclog.SetLoggerV2(), it must be called before any gRPC
+// functions, and is not safe for concurrent use with them.
+func SetLoggerV2(logger *logs.Logger) {
+	grpclog.SetLoggerV2(NewLoggerV2(logger))
+}
+
+// loggerV2 implements grpclog.LoggerV2 and grpclog.DepthLoggerV2
+type loggerV2 struct {
+	logger *logs.Logger
+}
+
+func (l *loggerV2) Info(args ...interface{}) {
+	l.log(logs.Info, fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Infoln(args ...interface{}) {
+	l.log(logs.Info, fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) Infof(format string, args ...interface{}) {
+	l.log(logs.Info, fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) Warning(args ...interface{}) {
+	l.log(logs.Warn, fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Warningln(args ...interface{}) {
+	l.log(logs.Warn, fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) Warningf(format string, args ...interface{}) {
+	l.log(logs.Warn, fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) Error(args ...interface{}) {
+	l.log(logs.Error, fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Errorln(args ...interface{}) {
+	l.log(logs.Error, fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) Errorf(format string, args ...interface{}) {
+	l.log(logs.Error, fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) Fatal(args ...interface{}) {
+	l.fatal(fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Fatalln(args ...interface{}) {
+	l.fatal(fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) Fatalf(format string, args ...interface{}) {
+	l.fatal(fmt.Sprintf(format, args...))
+}
+
+// The depth variants are used by gRPC when they are implemented, with the
+// arguments separated by spaces like the ln variants. Entries don't record their
+// caller, so depth is ignored.
+
+func (l *loggerV2) InfoDepth(depth int, args ...interface{}) {
+	l.log(logs.Info, fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) WarningDepth(depth int, args ...interface{}) {
+	l.log(logs.Warn, fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) ErrorDepth(depth int, args ...interface{}) {
+	l.log(logs.Error, fmt.Sprintln(args...))
+}
+
+func (l *loggerV2) FatalDepth(depth int, args ...interface{}) {
+	l.fatal(fmt.Sprintln(args...))
+}
+
+// V reports whether the verbosity level is enabled by the level of the "grpc"
+// logger
+func (l *loggerV2) V(verbosity int) bool {
+	level := l.logger.Level()
+	switch {
+	case verbosity <= 0:
+		return level <= logs.Info
+	case verbosity <= 2:
+		return level <= logs.Debug
+	default:
+		return level <= logs.Trace
+	}
+}
+
+// log logs a message with the logger of its component
+func (l *loggerV2) log(level logs.LogLevel, message string) {
+	target := l.logger
+	message = strings.TrimSuffix(message, "\n")
+	if strings.HasPrefix(message, "[") {
+		if end := strings.Index(message, "] "); end > 1 && !strings.ContainsAny(message[1:end], " .[") {
+			target = target.ChildLogger(message[1:end])
+			message = message[end+2:]
+		}
+	}
+	logAt(target, level, "%s", message)
+}
+
+// fatal logs a message at the Error level, closes the logger tree to flush it,
+// and exits, as gRPC expects of Fatal
+func (l *loggerV2) fatal(message string) {
+	l.log(logs.Error, message)
+	l.logger.Close()
+	os.Exit(1)
+}
@@ -0,0 +1,63 @@
+package logsgrpc_test
+
+import (
+	"sync"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+	logsgrpc "github.com/big-squid/go-logs-go/grpc"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestLoggerV2(test *testing.T) {
+	var mu sync.Mutex
+	var messages []logs.LogMessage
+	logger := logs.New(&logs.RootLogConfig{
+		Label: "app",
+		LogHandler: func(msg logs.LogMessage) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, msg)
+		},
+		Loggers: map[string]*logs.LogConfig{
+			"grpc": {
+				Level:   logs.Warn,
+				Loggers: map[string]*logs.LogConfig{"transport": {Level: logs.Info}},
+			},
+		},
+	})
+	defer logger.Close()
+	l := logsgrpc.NewLoggerV2(logger)
+
+	l.Info("balancer ", "picked")
+	l.Warningln("dns", "failed")
+	l.Infof("[transport] closing %d streams", 2)
+	l.Errorf("[core] [Channel #1] failed: %s", "EOF")
+	logsgrpc.SetLoggerV2(logger)
+	grpclog.Component("transport").Infof("draining")
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []struct{ logger, message string }{
+		{"app.grpc", "dns failed"},
+		{"app.grpc.transport", "closing 2 streams"},
+		{"app.grpc.core", "[Channel #1] failed: EOF"},
+		{"app.grpc.transport", "draining"},
+	}
+	if len(messages) != len(expected) {
+		test.Fatalf("Expected %d messages. Found: %v", len(expected), messages)
+	}
+	for i, e := range expected {
+		if messages[i].Logger != e.logger || messages[i].Message != e.message {
+			test.Errorf("Expected %q from %q. Found %q from %q", e.message, e.logger, messages[i].Message, messages[i].Logger)
+		}
+	}
+
+	if l.V(0) {
+		test.Error("Expected V(0) to be disabled at WARN")
+	}
+	logger.ChildLogger("grpc").SetLevel(logs.Debug)
+	if !l.V(0) || !l.V(2) || l.V(3) {
+		test.Error("Expected V(0) through V(2) to be enabled at DEBUG")
+	}
+}
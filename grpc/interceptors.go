@@ -0,0 +1,244 @@
+package logsgrpc
+
+import (
+	"context"
+	"io"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// InterceptorConfig configures the levels calls are logged at by the
+// interceptors of this package
+type InterceptorConfig struct {
+	// OKLevel is the level of calls that succeed. Defaults to Debug.
+	OKLevel logs.LogLevel
+	// ErrorLevel is the level of calls that fail. Defaults to Error.
+	ErrorLevel logs.LogLevel
+	// CodeLevels sets the level of calls that fail with specific codes, such as
+	// codes.NotFound at Info, overriding ErrorLevel
+	CodeLevels map[codes.Code]logs.LogLevel
+}
+
+// level returns the level a call that ended with code is logged at
+func (c InterceptorConfig) level(code codes.Code) logs.LogLevel {
+	if code == codes.OK {
+		if c.OKLevel == logs.NotSet {
+			return logs.Debug
+		}
+		return c.OKLevel
+	}
+	if level, ok := c.CodeLevels[code]; ok {
+		return level
+	}
+	if c.ErrorLevel == logs.NotSet {
+		return logs.Error
+	}
+	return c.ErrorLevel
+}
+
+// UnaryServerInterceptor logs each unary call served, with its method, peer,
+// duration, status code and error, at the level config sets for its code:
+//
+//	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+//		logsgrpc.UnaryServerInterceptor(logger.ChildLogger("rpc"), logsgrpc.InterceptorConfig{}),
+//	))
+//
+// When logger is at the Trace level, requests and responses are also logged,
+// protobuf messages as JSON. Payloads may hold sensitive data, so Trace should
+// only be enabled briefly, such as with SetLevelFor().
+func UnaryServerInterceptor(logger *logs.Logger, config InterceptorConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		from := peerAddr(ctx)
+		logPayload(logger, info.FullMethod, "request from "+from, req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			logPayload(logger, info.FullMethod, "response to "+from, resp)
+		}
+		logCall(logger, config, info.FullMethod, "from "+from, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs each streaming call served, like
+// UnaryServerInterceptor(), when the stream ends. At the Trace level, each
+// message received and sent is logged.
+func StreamServerInterceptor(logger *logs.Logger, config InterceptorConfig) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		from := peerAddr(ss.Context())
+		err := handler(srv, &serverStream{
+			ServerStream: ss,
+			logger:       logger,
+			method:       info.FullMethod,
+			peer:         from,
+		})
+		logCall(logger, config, info.FullMethod, "from "+from, time.Since(start), err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs each unary call made, with its method, target,
+// duration, status code and error, like UnaryServerInterceptor():
+//
+//	conn, err := grpc.NewClient(target,
+//		grpc.WithChainUnaryInterceptor(logsgrpc.UnaryClientInterceptor(logger.ChildLogger("rpc"), logsgrpc.InterceptorConfig{})),
+//	)
+func UnaryClientInterceptor(logger *logs.Logger, config InterceptorConfig) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		logPayload(logger, method, "request to "+cc.Target(), req)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			logPayload(logger, method, "response from "+cc.Target(), reply)
+		}
+		logCall(logger, config, method, "to "+cc.Target(), time.Since(start), err)
+		return err
+	}
+}
+
+// StreamClientInterceptor logs each streaming call made, like
+// UnaryClientInterceptor(), once RecvMsg() returns its reply, for calls without
+// a server stream, or an error, including io.EOF once the server has finished.
+// Calls abandoned before then are not logged. At the Trace level, each message
+// sent and received is logged.
+func StreamClientInterceptor(logger *logs.Logger, config InterceptorConfig) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(logger, config, method, "to "+cc.Target(), time.Since(start), err)
+			return nil, err
+		}
+		return &clientStream{
+			ClientStream: cs,
+			logger:       logger,
+			config:       config,
+			method:       method,
+			target:       cc.Target(),
+			start:        start,
+			single:       !desc.ServerStreams,
+		}, nil
+	}
+}
+
+// serverStream logs the messages of a stream served
+type serverStream struct {
+	grpc.ServerStream
+	logger *logs.Logger
+	method string
+	peer   string
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		logPayload(s.logger, s.method, "message to "+s.peer, m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		logPayload(s.logger, s.method, "message from "+s.peer, m)
+	}
+	return err
+}
+
+// clientStream logs the messages of a stream made, and the call once it ends
+type clientStream struct {
+	grpc.ClientStream
+	logger *logs.Logger
+	config InterceptorConfig
+	method string
+	target string
+	start  time.Time
+	// single is set when the server replies with a single message
+	single bool
+	ended  bool
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		logPayload(s.logger, s.method, "message to "+s.target, m)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		logPayload(s.logger, s.method, "message from "+s.target, m)
+	}
+	// The call ends with its single reply, or the first error RecvMsg returns.
+	// Messages are only received by one goroutine at a time, so ended needs no
+	// lock.
+	if (err != nil || s.single) && !s.ended {
+		s.ended = true
+		ended := err
+		if ended == io.EOF {
+			ended = nil
+		}
+		logCall(s.logger, s.config, s.method, "to "+s.target, time.Since(s.start), ended)
+	}
+	return err
+}
+
+// logCall logs a call that ended after d with err
+func logCall(logger *logs.Logger, config InterceptorConfig, method string, peer string, d time.Duration, err error) {
+	st := status.Convert(err)
+	level := config.level(st.Code())
+	if err == nil {
+		logAt(logger, level, "%s %s %s in %s", method, st.Code(), peer, d)
+		return
+	}
+	logAt(logger, level, "%s %s %s in %s: %s", method, st.Code(), peer, d, st.Message())
+}
+
+// logPayload logs a message of a call at the Trace level. Messages are only
+// formatted when the logger is at the Trace level.
+func logPayload(logger *logs.Logger, method string, what string, m interface{}) {
+	if logger.Level() > logs.Trace {
+		return
+	}
+	var payload interface{} = m
+	if msg, ok := m.(proto.Message); ok {
+		if data, err := protojson.Marshal(msg); err == nil {
+			payload = string(data)
+		}
+	}
+	logger.Trace("%s %s: %v", method, what, payload)
+}
+
+// peerAddr returns the address of the peer of a call served
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && nil != p.Addr {
+		return p.Addr.String()
+	}
+	return "unknown peer"
+}
+
+// logAt logs a message at level
+func logAt(logger *logs.Logger, level logs.LogLevel, format string, args ...interface{}) {
+	switch {
+	case level <= logs.Trace:
+		logger.Trace(format, args...)
+	case level <= logs.Debug:
+		logger.Debug(format, args...)
+	case level <= logs.Info:
+		logger.Info(format, args...)
+	case level <= logs.Warn:
+		logger.Warn(format, args...)
+	default:
+		logger.Error(format, args...)
+	}
+}
@@ -0,0 +1,166 @@
+package logsgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+	logsgrpc "github.com/big-squid/go-logs-go/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// recorded collects the entries logged by a Logger
+type recorded struct {
+	mu       sync.Mutex
+	messages []logs.LogMessage
+}
+
+func (r *recorded) handler(msg logs.LogMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+}
+
+// find returns the entries of a logger that contain text
+func (r *recorded) find(logger string, text string) []logs.LogMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []logs.LogMessage
+	for _, msg := range r.messages {
+		if msg.Logger == logger && strings.Contains(msg.Message, text) {
+			found = append(found, msg)
+		}
+	}
+	return found
+}
+
+// await waits for an entry of a logger that contains text
+func (r *recorded) await(test *testing.T, logger string, text string) logs.LogMessage {
+	test.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if found := r.find(logger, text); len(found) > 0 {
+			return found[0]
+		}
+		if time.Now().After(deadline) {
+			test.Fatalf("Expected %s to log %q. Found: %v", logger, text, r.messages)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// healthClient serves a health service through the interceptors, over an
+// in-memory connection
+func healthClient(test *testing.T, logger *logs.Logger, config logsgrpc.InterceptorConfig) healthpb.HealthClient {
+	lis := bufconn.Listen(1 << 16)
+	server := logger.ChildLogger("server")
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logsgrpc.UnaryServerInterceptor(server, config)),
+		grpc.ChainStreamInterceptor(logsgrpc.StreamServerInterceptor(server, config)),
+	)
+	hs := health.NewServer()
+	hs.SetServingStatus("svc", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, hs)
+	go s.Serve(lis)
+	test.Cleanup(s.Stop)
+
+	client := logger.ChildLogger("client")
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(logsgrpc.UnaryClientInterceptor(client, config)),
+		grpc.WithChainStreamInterceptor(logsgrpc.StreamClientInterceptor(client, config)),
+	)
+	if err != nil {
+		test.Fatal(err)
+	}
+	test.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(test *testing.T) {
+	r := &recorded{}
+	logger := logs.New(&logs.RootLogConfig{Label: "app", Level: logs.Debug, LogHandler: r.handler})
+	defer logger.Close()
+	client := healthClient(test, logger, logsgrpc.InterceptorConfig{
+		CodeLevels: map[codes.Code]logs.LogLevel{codes.NotFound: logs.Info},
+	})
+	ctx := context.Background()
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "svc"}); err != nil {
+		test.Fatal(err)
+	}
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"}); err == nil {
+		test.Fatal("Expected an unknown service to fail")
+	}
+
+	for _, logger := range []string{"app.server", "app.client"} {
+		ok := r.find(logger, "/grpc.health.v1.Health/Check OK")
+		if len(ok) != 1 || ok[0].Level != logs.Debug {
+			test.Errorf("Expected %s to log the successful call at DEBUG. Found: %v", logger, ok)
+		}
+		notFound := r.find(logger, "/grpc.health.v1.Health/Check NotFound")
+		if len(notFound) != 1 || notFound[0].Level != logs.Info || !strings.Contains(notFound[0].Message, "unknown service") {
+			test.Errorf("Expected %s to log the failed call at INFO. Found: %v", logger, notFound)
+		}
+	}
+	if found := r.find("app.server", "from bufconn"); len(found) != 2 {
+		test.Errorf("Expected the peer of the server calls. Found: %v", found)
+	}
+	if found := r.find("app.client", "to passthrough:///bufnet"); len(found) != 2 {
+		test.Errorf("Expected the target of the client calls. Found: %v", found)
+	}
+	if found := r.find("app.server", "request"); len(found) != 0 {
+		test.Errorf("Expected no payloads above TRACE. Found: %v", found)
+	}
+
+	logger.SetLevel(logs.Trace)
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "svc"}); err != nil {
+		test.Fatal(err)
+	}
+	request := r.find("app.server", `request from bufconn: {"service":"svc"}`)
+	response := r.find("app.client", `response from passthrough:///bufnet: {"status":"SERVING"}`)
+	if len(request) != 1 || request[0].Level != logs.Trace || len(response) != 1 {
+		test.Errorf("Expected the payloads at TRACE. Found: %v", r.messages)
+	}
+}
+
+func TestStreamInterceptors(test *testing.T) {
+	r := &recorded{}
+	logger := logs.New(&logs.RootLogConfig{Label: "app", Level: logs.Trace, LogHandler: r.handler})
+	defer logger.Close()
+	client := healthClient(test, logger, logsgrpc.InterceptorConfig{OKLevel: logs.Info, ErrorLevel: logs.Warn})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "svc"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		test.Fatal(err)
+	}
+	cancel()
+	if _, err := stream.Recv(); err == nil {
+		test.Fatal("Expected the canceled stream to fail")
+	}
+
+	r.await(test, "app.server", `message from bufconn: {"service":"svc"}`)
+	r.await(test, "app.client", `message from passthrough:///bufnet: {"status":"SERVING"}`)
+	for _, name := range []string{"app.server", "app.client"} {
+		msg := r.await(test, name, "/grpc.health.v1.Health/Watch Canceled")
+		if msg.Level != logs.Warn {
+			test.Errorf("Expected %s to log the canceled stream at WARN. Found: %v", name, msg)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package logsgrpc serves the levels of a live go-logs-go logger tree over gRPC,
+// for infrastructures that standardize operational controls on gRPC. It lives in
+// its own module so that users who don't run gRPC don't inherit it as a
+// dependency.
+//
+// The service is defined in admin.proto. Regenerate admin.pb.go and
+// admin_grpc.pb.go after changing it with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//		--go-grpc_out=. --go-grpc_opt=paths=source_relative admin.proto
+package logsgrpc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewServer returns a LogLevelAdminServer for a logger and its descendants.
+// Logger names in requests are dotted paths below it, as with ChildLogger(). The
+// service can change what is logged, so serve it only to operators.
+func NewServer(logger *logs.Logger) LogLevelAdminServer {
+	return &server{logger: logger}
+}
+
+// Register registers a LogLevelAdminServer for a logger with a grpc.Server
+func Register(s *grpc.Server, logger *logs.Logger) {
+	RegisterLogLevelAdminServer(s, NewServer(logger))
+}
+
+type server struct {
+	UnimplementedLogLevelAdminServer
+	logger *logs.Logger
+}
+
+func (s *server) ListLoggers(ctx context.Context, req *ListLoggersRequest) (*ListLoggersResponse, error) {
+	target := s.child(req.GetName())
+	var loggers []*Logger
+	flatten(req.GetName(), target.Levels(), &loggers)
+	sort.Slice(loggers, func(i, j int) bool {
+		return loggers[i].Name < loggers[j].Name
+	})
+	return &ListLoggersResponse{Loggers: loggers}, nil
+}
+
+func (s *server) SetLevel(ctx context.Context, req *SetLevelRequest) (*SetLevelResponse, error) {
+	level, ok := logs.LogLevels.Level(req.GetLevel())
+	if !ok || level == logs.NotSet {
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown level %q", req.GetLevel())
+	}
+	var d time.Duration
+	if nil != req.GetDuration() {
+		if err := req.GetDuration().CheckValid(); err != nil || req.GetDuration().AsDuration() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid duration %v", req.GetDuration())
+		}
+		d = req.GetDuration().AsDuration()
+	}
+	target := s.child(req.GetName())
+	target.SetLevelFor(level, d)
+	return &SetLevelResponse{Logger: describe(req.GetName(), target.Levels())}, nil
+}
+
+func (s *server) ResetLevel(ctx context.Context, req *ResetLevelRequest) (*ResetLevelResponse, error) {
+	if len(strings.Trim(req.GetName(), ".")) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "The root logger requires a level")
+	}
+	target := s.child(req.GetName())
+	target.SetLevel(logs.NotSet)
+	return &ResetLevelResponse{Logger: describe(req.GetName(), target.Levels())}, nil
+}
+
+// child returns the named descendant of the served logger
+func (s *server) child(name string) *logs.Logger {
+	if name = strings.Trim(name, "."); len(name) == 0 {
+		return s.logger
+	}
+	return s.logger.ChildLogger(name)
+}
+
+// describe converts LoggerLevels to a Logger message
+func describe(name string, levels *logs.LoggerLevels) *Logger {
+	logger := &Logger{
+		Name:       strings.Trim(name, "."),
+		Label:      levels.Label,
+		Level:      levels.Level,
+		Configured: levels.Configured,
+	}
+	if nil != levels.Expires {
+		logger.Expires = timestamppb.New(*levels.Expires)
+	}
+	return logger
+}
+
+// flatten appends a logger and its descendants to loggers
+func flatten(name string, levels *logs.LoggerLevels, loggers *[]*Logger) {
+	*loggers = append(*loggers, describe(name, levels))
+	for child, childLevels := range levels.Loggers {
+		flatten(strings.Trim(name, ".")+"."+child, childLevels, loggers)
+	}
+}
@@ -0,0 +1,113 @@
+package logsgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+	logsgrpc "github.com/big-squid/go-logs-go/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// adminClient serves the levels of a logger over an in-memory connection
+func adminClient(test *testing.T, logger *logs.Logger) logsgrpc.LogLevelAdminClient {
+	lis := bufconn.Listen(1 << 16)
+	s := grpc.NewServer()
+	logsgrpc.Register(s, logger)
+	go s.Serve(lis)
+	test.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		test.Fatal(err)
+	}
+	test.Cleanup(func() { conn.Close() })
+	return logsgrpc.NewLogLevelAdminClient(conn)
+}
+
+func TestServer(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "app",
+		LogHandler: func(logs.LogMessage) {},
+		Loggers:    map[string]*logs.LogConfig{"db": {Level: logs.Warn}},
+	})
+	cache := logger.ChildLogger("db").ChildLogger("cache")
+	logger.ChildLogger("http")
+	client := adminClient(test, logger)
+	ctx := context.Background()
+
+	list, err := client.ListLoggers(ctx, &logsgrpc.ListLoggersRequest{})
+	if err != nil {
+		test.Fatal(err)
+	}
+	var names []string
+	for _, l := range list.GetLoggers() {
+		names = append(names, l.GetName())
+	}
+	if len(names) != 4 || names[0] != "" || names[1] != "db" || names[2] != "db.cache" || names[3] != "http" {
+		test.Fatalf("Expected the loggers sorted by name. Found: %q", names)
+	}
+	if db := list.GetLoggers()[1]; db.GetLabel() != "app.db" || db.GetLevel() != "WARN" || db.GetConfigured() != "WARN" {
+		test.Errorf("Unexpected db logger: %v", db)
+	}
+
+	set, err := client.SetLevel(ctx, &logsgrpc.SetLevelRequest{Name: "db.cache", Level: "trace"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if set.GetLogger().GetName() != "db.cache" || set.GetLogger().GetLevel() != "TRACE" || cache.Level() != logs.Trace {
+		test.Errorf("Expected db.cache to be at TRACE. Found: %v", set.GetLogger())
+	}
+
+	set, err = client.SetLevel(ctx, &logsgrpc.SetLevelRequest{Name: "http", Level: "DEBUG", Duration: durationpb.New(time.Hour)})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if set.GetLogger().GetLevel() != "DEBUG" || nil == set.GetLogger().GetExpires() || set.GetLogger().GetExpires().AsTime().Before(time.Now()) {
+		test.Errorf("Expected http to be at DEBUG for an hour. Found: %v", set.GetLogger())
+	}
+
+	list, err = client.ListLoggers(ctx, &logsgrpc.ListLoggersRequest{Name: "db"})
+	if err != nil || len(list.GetLoggers()) != 2 || list.GetLoggers()[1].GetName() != "db.cache" {
+		test.Errorf("Expected the db subtree. Found: %v, %v", list.GetLoggers(), err)
+	}
+
+	reset, err := client.ResetLevel(ctx, &logsgrpc.ResetLevelRequest{Name: "db.cache"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if reset.GetLogger().GetLevel() != "WARN" || reset.GetLogger().GetConfigured() != "" || cache.Level() != logs.Warn {
+		test.Errorf("Expected db.cache to inherit the WARN level. Found: %v", reset.GetLogger())
+	}
+}
+
+func TestServerErrors(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	client := adminClient(test, logger)
+	ctx := context.Background()
+
+	if _, err := client.SetLevel(ctx, &logsgrpc.SetLevelRequest{Name: "db", Level: "LOUD"}); status.Code(err) != codes.InvalidArgument {
+		test.Errorf("Expected InvalidArgument for an unknown level. Found: %v", err)
+	}
+	if _, err := client.SetLevel(ctx, &logsgrpc.SetLevelRequest{Name: "db"}); status.Code(err) != codes.InvalidArgument {
+		test.Errorf("Expected InvalidArgument for an empty level. Found: %v", err)
+	}
+	if _, err := client.SetLevel(ctx, &logsgrpc.SetLevelRequest{Name: "db", Level: "DEBUG", Duration: durationpb.New(-time.Minute)}); status.Code(err) != codes.InvalidArgument {
+		test.Errorf("Expected InvalidArgument for a negative duration. Found: %v", err)
+	}
+	if _, err := client.ResetLevel(ctx, &logsgrpc.ResetLevelRequest{}); status.Code(err) != codes.InvalidArgument {
+		test.Errorf("Expected InvalidArgument for resetting the root logger. Found: %v", err)
+	}
+}
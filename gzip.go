@@ -0,0 +1,98 @@
+package gologsgo
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// GzipWriter compresses everything written to it as a single gzip stream, for
+// file and network handlers with high-volume output. Compressed data is flushed to
+// the underlying Writer on an interval, so readers see recent entries without
+// waiting for Close().
+type GzipWriter struct {
+	w      io.Writer
+	mu     sync.Mutex
+	gz     *gzip.Writer
+	dirty  bool
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewGzipWriter returns a GzipWriter that writes to w, flushing every
+// flushInterval. A flushInterval of 0 only flushes on Flush() and Close().
+func NewGzipWriter(w io.Writer, flushInterval time.Duration) *GzipWriter {
+	g := &GzipWriter{
+		w:    w,
+		gz:   gzip.NewWriter(w),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		go g.run(flushInterval)
+	} else {
+		close(g.done)
+	}
+	return g
+}
+
+// Write compresses p
+func (g *GzipWriter) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return 0, io.ErrClosedPipe
+	}
+	g.dirty = true
+	return g.gz.Write(p)
+}
+
+// Flush writes everything compressed so far to the underlying Writer. It is part
+// of FlushCloser.
+func (g *GzipWriter) Flush() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed || !g.dirty {
+		return nil
+	}
+	g.dirty = false
+	return g.gz.Flush()
+}
+
+// Close ends the gzip stream and closes the underlying Writer if it is an
+// io.Closer. It is part of FlushCloser.
+func (g *GzipWriter) Close() error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	close(g.stop)
+	err := g.gz.Close()
+	g.mu.Unlock()
+
+	<-g.done
+	if closer, ok := g.w.(io.Closer); ok {
+		if closeErr := closer.Close(); nil == err {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (g *GzipWriter) run(interval time.Duration) {
+	defer close(g.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			g.Flush()
+		case <-g.stop:
+			return
+		}
+	}
+}
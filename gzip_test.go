@@ -0,0 +1,65 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestGzipWriter(test *testing.T) {
+	var out syncBuffer
+	writer := logs.NewGzipWriter(&out, 10*time.Millisecond)
+	handler := logs.WriterHandler(writer, logs.TextEncoder)
+
+	for i := 0; i < 100; i++ {
+		handler(logs.LogMessage{Level: logs.Trace, LevelLabel: "TRACE", Message: "Very verbose"})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(out.Bytes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(out.Bytes()) == 0 {
+		test.Fatalf("Expected compressed data to be flushed on the interval")
+	}
+
+	if err := writer.Close(); err != nil {
+		test.Fatal(err)
+	}
+	compressed := out.Bytes()
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		test.Fatal(err)
+	}
+	plain, err := ioutil.ReadAll(reader)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if strings.Count(string(plain), "Very verbose") != 100 || len(compressed) >= len(plain)/4 {
+		test.Errorf("Expected a well compressed stream of 100 entries. Found %d bytes from %d", len(compressed), len(plain))
+	}
+}
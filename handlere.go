@@ -0,0 +1,59 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// LogHandlerE is a handler that can report delivery failures, such as a full disk
+// or a broken pipe. Use it as RootLogConfig.LogHandlerE, or adapt it to a
+// LogHandler with its LogHandler() method.
+type LogHandlerE func(LogMessage) error
+
+// ErrorCallback is called with the error returned by a LogHandlerE and the
+// LogMessage that could not be delivered
+type ErrorCallback func(err error, msg LogMessage)
+
+// LogHandler adapts the LogHandlerE to a LogHandler that passes errors to
+// onError. If onError is nil, DefaultErrorCallback is used.
+func (h LogHandlerE) LogHandler(onError ErrorCallback) LogHandler {
+	if nil == onError {
+		onError = DefaultErrorCallback
+	}
+	return func(msg LogMessage) {
+		if err := h(msg); err != nil {
+			atomic.AddUint64(&handlerErrorsCount, 1)
+			onError(err, msg)
+		}
+	}
+}
+
+// DefaultErrorCallback writes delivery failures and the record that could not be
+// delivered to LastResortWriter
+func DefaultErrorCallback(err error, msg LogMessage) {
+	lastresortlock.Lock()
+	defer lastresortlock.Unlock()
+
+	fmt.Fprintf(LastResortWriter, "ERROR [gologsgo]: Failed to deliver a log record: %s\n", err)
+	if data, err := TextEncoder(msg); nil == err {
+		fmt.Fprintln(LastResortWriter, string(data))
+	}
+}
+
+// WriterHandler returns a LogHandlerE that writes each record to w, encoded with
+// encoder and delimited with NewlineFraming. If encoder is nil, TextEncoder is
+// used.
+func WriterHandler(w io.Writer, encoder Encoder) LogHandlerE {
+	if nil == encoder {
+		encoder = TextEncoder
+	}
+	return func(msg LogMessage) error {
+		data, err := encoder(msg)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(NewlineFraming(data))
+		return err
+	}
+}
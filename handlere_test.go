@@ -0,0 +1,54 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestLogHandlerE(test *testing.T) {
+	var failures []string
+	root := logs.New(&logs.RootLogConfig{
+		LogHandlerE: logs.WriterHandler(failingWriter{}, nil),
+		ErrorCallback: func(err error, msg logs.LogMessage) {
+			failures = append(failures, err.Error()+": "+msg.Message)
+		},
+	})
+	root.ChildLogger("child").Error("Disk on fire")
+
+	if len(failures) != 1 || failures[0] != "broken pipe: Disk on fire" {
+		test.Errorf("Expected the failure to reach ErrorCallback. Found: %v", failures)
+	}
+}
+
+func TestDefaultErrorCallback(test *testing.T) {
+	var lastResort bytes.Buffer
+	output := logs.LastResortWriter
+	logs.LastResortWriter = &lastResort
+	defer func() {
+		logs.LastResortWriter = output
+	}()
+
+	var buffer bytes.Buffer
+	root := logs.New(&logs.RootLogConfig{
+		LogHandlerE: logs.WriterHandler(&buffer, nil),
+	})
+	root.Info("Delivered")
+	if !strings.Contains(buffer.String(), "Delivered") {
+		test.Errorf("Expected WriterHandler to write the record. Found: %q", buffer.String())
+	}
+
+	logs.WriterHandler(failingWriter{}, nil).LogHandler(nil)(logs.LogMessage{Level: logs.Warn, Message: "Lost"})
+	if !strings.Contains(lastResort.String(), "broken pipe") || !strings.Contains(lastResort.String(), "Lost") {
+		test.Errorf("Expected DefaultErrorCallback to report to LastResortWriter. Found: %q", lastResort.String())
+	}
+}
@@ -0,0 +1,67 @@
+package gologsgo
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+)
+
+// HclConfig creates a RootLogConfig from HashiCorp HCL data. The keys are those
+// of the JSON accepted by JsonConfig(). Child loggers are written as labeled
+// logger blocks, which may be nested:
+//
+//	level = "info"
+//
+//	logger "main" {
+//	  level = "debug"
+//
+//	  logger "db" {
+//	    level = "trace"
+//	  }
+//	}
+//
+// Other objects, such as colors, are written as blocks, and handlers as a list:
+// handlers = [{ type = "stdout" }].
+func HclConfig(data []byte) (*RootLogConfig, error) {
+	var cfg map[string]interface{}
+	if err := hcl.Decode(&cfg, string(data)); err != nil {
+		return nil, fmt.Errorf("Invalid HCL config: %s", err)
+	}
+
+	return MapConfig(hclObject(cfg))
+}
+
+// hclObject converts an object decoded from HCL to the structure of the JSON
+// config. HCL decodes each block as a list of objects, which are merged, and
+// logger blocks are moved to "loggers".
+func hclObject(obj map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		blocks, ok := v.([]map[string]interface{})
+		if !ok {
+			converted[k] = v
+			continue
+		}
+		if k == "handlers" {
+			handlers := make([]interface{}, len(blocks))
+			for i, block := range blocks {
+				handlers[i] = hclObject(block)
+			}
+			converted[k] = handlers
+			continue
+		}
+		merged := make(map[string]interface{})
+		for _, block := range blocks {
+			mergeMaps(merged, hclObject(block))
+		}
+		if k == "logger" {
+			k = "loggers"
+		}
+		if existing, ok := converted[k].(map[string]interface{}); ok {
+			mergeMaps(existing, merged)
+			continue
+		}
+		converted[k] = merged
+	}
+	return converted
+}
@@ -0,0 +1,60 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestHclConfig(test *testing.T) {
+	config, err := logs.HclConfig([]byte(`
+level = "info"
+label = "app"
+
+colors {
+  mode = "never"
+}
+
+handlers = [{ type = "stdout", format = "json" }]
+
+logger "main" {
+  level = "debug"
+
+  logger "db" {
+    level = "trace"
+  }
+}
+
+logger "http" {
+  level = "warn"
+}
+`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Info || config.Label != "app" {
+		test.Errorf("Unexpected config: %+v", config)
+	}
+	if nil == config.Colors || config.Colors.Mode != logs.ColorNever {
+		test.Errorf("Expected the colors block to be read. Found: %+v", config.Colors)
+	}
+	if len(config.Handlers) != 1 || config.Handlers[0].Format != "json" {
+		test.Errorf("Expected one json handler. Found: %+v", config.Handlers)
+	}
+	main := config.Loggers["main"]
+	if nil == main || main.Level != logs.Debug || nil == main.Loggers["db"] || main.Loggers["db"].Level != logs.Trace {
+		test.Errorf("Expected the main loggers to be configured. Found: %+v", main)
+	}
+	if nil == config.Loggers["http"] || config.Loggers["http"].Level != logs.Warn {
+		test.Errorf("Expected the http logger to be configured. Found: %+v", config.Loggers["http"])
+	}
+}
+
+func TestHclConfigErrors(test *testing.T) {
+	if _, err := logs.HclConfig([]byte(`logger "main" {`)); err == nil {
+		test.Error("Expected an error for invalid HCL")
+	}
+	if _, err := logs.HclConfig([]byte(`logger "main" { level = "loud" }`)); err == nil {
+		test.Error("Expected an error for an unknown level")
+	}
+}
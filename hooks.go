@@ -0,0 +1,17 @@
+package gologsgo
+
+// Hook is run on each LogMessage before it reaches any handler. It may modify
+// the message - to enrich, rewrite or re-level it - and returns false to drop
+// it. Hooks are set with RootLogConfig.Hooks and run in order; the first to
+// return false stops the others from running.
+type Hook func(msg *LogMessage) bool
+
+// runHooks reports whether msg survived the Logger's hooks
+func (logger *Logger) runHooks(msg *LogMessage) bool {
+	for _, hook := range logger.hooks {
+		if !hook(msg) {
+			return false
+		}
+	}
+	return true
+}
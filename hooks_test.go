@@ -0,0 +1,53 @@
+package gologsgo_test
+
+import (
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestHooks(test *testing.T) {
+	var calls []string
+	var received []logs.LogMessage
+	root := logs.New(&logs.RootLogConfig{
+		Hooks: []logs.Hook{
+			func(msg *logs.LogMessage) bool {
+				calls = append(calls, "enrich")
+				msg.Message = "[" + msg.Logger + "] " + msg.Message
+				return true
+			},
+			func(msg *logs.LogMessage) bool {
+				calls = append(calls, "filter")
+				return !strings.Contains(msg.Message, "healthcheck")
+			},
+			func(msg *logs.LogMessage) bool {
+				calls = append(calls, "downgrade")
+				if strings.Contains(msg.Message, "noisy") {
+					msg.Level = logs.Debug
+					msg.LevelLabel = "DEBUG"
+				}
+				return true
+			},
+		},
+		LogHandler: func(msg logs.LogMessage) {
+			received = append(received, msg)
+		},
+	})
+	child := root.ChildLogger("http")
+
+	child.Info("GET /healthcheck")
+	if strings.Join(calls, ",") != "enrich,filter" || len(received) != 0 {
+		test.Errorf("Expected the filter to drop the entry and stop later hooks. Found: %v", calls)
+	}
+
+	child.Info("noisy retry")
+	if len(received) != 0 {
+		test.Errorf("Expected an entry downgraded below the level to be dropped")
+	}
+
+	child.Info("GET /orders")
+	if len(received) != 1 || received[0].Message != "[http] GET /orders" {
+		test.Errorf("Expected hooks to modify the entry before the handler. Found: %v", received)
+	}
+}
@@ -0,0 +1,364 @@
+package gologsgo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPHandlerConfig configures an HTTPHandler
+type HTTPHandlerConfig struct {
+	// URL entries are sent to
+	URL string
+	// Method defaults to POST
+	Method string
+	// Headers are added to every request, for example an Authorization header
+	Headers http.Header
+	// Client defaults to an http.Client with a 30 second timeout
+	Client *http.Client
+	// Encoder serializes each entry. The request body is a JSON array of the
+	// encoded entries, so it must produce JSON. Defaults to JSONEncoder.
+	Encoder Encoder
+	// Gzip compresses request bodies and sets Content-Encoding: gzip
+	Gzip bool
+	// MaxBatchSize is the most entries sent in one request. Defaults to 100.
+	MaxBatchSize int
+	// MaxBatchAge is the longest an entry waits for its batch to fill before
+	// being sent. Defaults to 1 second.
+	MaxBatchAge time.Duration
+	// MaxRetries is the number of times a failed request is retried. Requests
+	// are retried on network errors, 429 and 5xx responses. Defaults to 3. Set
+	// a negative value to disable retries.
+	MaxRetries int
+	// MinBackoff is the delay before the first retry. It doubles on each retry
+	// up to MaxBackoff. Defaults to 500ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// QueueSize is the number of entries held in memory waiting to be batched.
+	// When full, new entries are dropped. Defaults to 10000.
+	QueueSize int
+	// OnDeliveryFailure, when set, is called with each batch that could not be
+	// delivered after all retries
+	OnDeliveryFailure func(batch []LogMessage, err error)
+	// Spool, when set, receives the request bodies of batches that could not be
+	// delivered after all retries. They are replayed after the next successful
+	// request.
+	Spool *Spool
+}
+
+// HTTPHandler sends batches of entries to an HTTP endpoint, for use with any log
+// ingestion API that accepts JSON. Entries are queued and sent by a background
+// goroutine.
+type HTTPHandler struct {
+	// dropped is first to guarantee 64-bit alignment for atomic operations
+	dropped uint64
+	// spooled is 1 while the Spool may hold bodies to replay. It is read and
+	// written atomically.
+	spooled int32
+	config  HTTPHandlerConfig
+	batcher *Batcher
+	// health is failing after a batch could not be delivered
+	health health
+	// body, when set, replaces the default JSON array request body. Handlers
+	// for specific ingestion APIs set it.
+	body func(batch []LogMessage) ([]byte, error)
+	// prepare, when set, is called on each request (with its uncompressed body)
+	// before it is sent, for example to sign it
+	prepare func(req *http.Request, body []byte) error
+	// response, when set, checks the body of successful responses for errors,
+	// for ingestion APIs that report failures in the response
+	response func(body []byte) (retry bool, err error)
+}
+
+// NewHTTPHandler validates the config and starts an HTTPHandler
+func NewHTTPHandler(config HTTPHandlerConfig) (*HTTPHandler, error) {
+	if len(config.URL) < 1 {
+		return nil, fmt.Errorf("HTTPHandler requires a URL")
+	}
+	if len(config.Method) == 0 {
+		config.Method = http.MethodPost
+	}
+	if nil == config.Client {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if nil == config.Encoder {
+		config.Encoder = JSONEncoder
+	}
+	if config.MaxBatchSize < 1 {
+		config.MaxBatchSize = 100
+	}
+	if config.MaxBatchAge <= 0 {
+		config.MaxBatchAge = time.Second
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 500 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 10000
+	}
+
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("Invalid HTTPHandler URL: %s", err)
+	}
+
+	h := &HTTPHandler{
+		config: config,
+	}
+	if nil != config.Spool {
+		// Bodies spooled before a restart are replayed after the first delivery
+		h.spooled = 1
+	}
+	h.batcher = newBatcher(h.send, config.MaxBatchSize, config.MaxBatchAge, config.QueueSize)
+
+	return h, nil
+}
+
+// LogHandler queues a LogMessage to be sent. It is a LogHandler.
+func (h *HTTPHandler) LogHandler(msg LogMessage) {
+	h.batcher.LogHandler(msg)
+}
+
+// LogHandlerE queues a LogMessage for the next batch, like LogHandler, but
+// returns an error instead when the queue is full, and for MaxBackoff after a
+// batch could not be delivered. Entries are then accepted again, and the next
+// batch shows whether the endpoint has recovered. Use it with Failover(). It is
+// a LogHandlerE.
+func (h *HTTPHandler) LogHandlerE(msg LogMessage) error {
+	if err := h.health.check(h.config.MaxBackoff); err != nil {
+		return err
+	}
+	return h.batcher.enqueue(msg)
+}
+
+// Dropped returns the number of entries that were discarded because the queue was
+// full, they could not be delivered, or they were logged after Close()
+func (h *HTTPHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped) + h.batcher.Dropped()
+}
+
+// Flush sends everything queued so far and waits for it to be delivered. It is
+// part of FlushCloser.
+func (h *HTTPHandler) Flush() error {
+	return h.batcher.Flush()
+}
+
+// Close stops accepting entries, sends everything still queued and waits for it
+// to be delivered
+func (h *HTTPHandler) Close() error {
+	return h.batcher.Close()
+}
+
+// partialFailure is returned by a response check when only some entries of a
+// batch failed. retry and failed hold the indices of the entries in the batch
+// that are worth retrying and that were rejected for good.
+type partialFailure struct {
+	retry  []int
+	failed []int
+	err    error
+}
+
+func (p *partialFailure) Error() string {
+	return p.err.Error()
+}
+
+// send delivers a batch, retrying with backoff
+func (h *HTTPHandler) send(batch []LogMessage) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body, err := h.encode(batch)
+	if err != nil {
+		h.fail(batch, err)
+		return
+	}
+	var retry bool
+	backoff := h.config.MinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err = h.post(body)
+		if partial, ok := err.(*partialFailure); ok {
+			// The entries that were delivered are done, and those rejected for
+			// good are reported, so only the rest are sent again
+			if len(partial.failed) > 0 {
+				h.fail(entriesAt(batch, partial.failed), partial.err)
+			}
+			if len(partial.retry) == 0 {
+				err = nil
+				break
+			}
+			batch = entriesAt(batch, partial.retry)
+			if body, err = h.encode(batch); err != nil {
+				h.fail(batch, err)
+				return
+			}
+			retry, err = true, partial.err
+		}
+		if err == nil || !retry || attempt >= h.config.MaxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > h.config.MaxBackoff {
+			backoff = h.config.MaxBackoff
+		}
+	}
+
+	if nil == err {
+		h.health.set(nil)
+		h.replay()
+		return
+	}
+	if retry {
+		// A rejected request doesn't mean the endpoint is unavailable
+		h.health.set(err)
+	}
+	// Only transient failures are spooled; a rejected body would be rejected again
+	if retry && nil != h.config.Spool && nil == h.config.Spool.Write(body) {
+		atomic.StoreInt32(&h.spooled, 1)
+		return
+	}
+	h.fail(batch, err)
+}
+
+// fail counts entries that could not be delivered as dropped, and reports them
+// to OnDeliveryFailure
+func (h *HTTPHandler) fail(batch []LogMessage, err error) {
+	drop(&h.dropped, uint64(len(batch)))
+	if nil != h.config.OnDeliveryFailure {
+		h.config.OnDeliveryFailure(batch, err)
+	}
+}
+
+// entriesAt returns the entries of batch at indices, ignoring indices that are
+// out of range
+func entriesAt(batch []LogMessage, indices []int) []LogMessage {
+	entries := make([]LogMessage, 0, len(indices))
+	for _, i := range indices {
+		if i >= 0 && i < len(batch) {
+			entries = append(entries, batch[i])
+		}
+	}
+	return entries
+}
+
+// replay sends spooled request bodies now that the endpoint is accepting
+// requests. The spool is only read when bodies have been spooled since the last
+// replay that delivered everything.
+func (h *HTTPHandler) replay() {
+	if nil == h.config.Spool || !atomic.CompareAndSwapInt32(&h.spooled, 1, 0) {
+		return
+	}
+	_, err := h.config.Spool.Replay(func(body []byte) error {
+		retry, err := h.post(body)
+		if partial, ok := err.(*partialFailure); ok {
+			// The entries of a spooled body are unknown, so those that failed
+			// are counted rather than sent again with the ones delivered
+			drop(&h.dropped, uint64(len(partial.failed)+len(partial.retry)))
+			return nil
+		}
+		if nil != err && !retry {
+			// Discard a body the endpoint rejects rather than retrying it forever. The
+			// number of entries it held is unknown, so it is counted as one.
+			drop(&h.dropped, 1)
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		// The endpoint failed again, so what is left is replayed later
+		atomic.StoreInt32(&h.spooled, 1)
+	}
+}
+
+// encode builds the request body for a batch
+func (h *HTTPHandler) encode(batch []LogMessage) ([]byte, error) {
+	if nil != h.body {
+		return h.body(batch)
+	}
+
+	buf := bytes.NewBufferString("[")
+	for i, msg := range batch {
+		data, err := h.config.Encoder(msg)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// post sends a request body, reporting whether a failure is worth retrying
+func (h *HTTPHandler) post(body []byte) (bool, error) {
+	payload := body
+	if h.config.Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		if err := zw.Close(); err != nil {
+			return false, err
+		}
+		payload = buf.Bytes()
+	}
+
+	req, err := http.NewRequest(h.config.Method, h.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	for k, values := range h.config.Headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	if len(req.Header.Get("Content-Type")) == 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if h.config.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if nil != h.prepare {
+		if err := h.prepare(req, body); err != nil {
+			return false, err
+		}
+	}
+
+	resp, err := h.config.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if nil == h.response {
+			io.Copy(ioutil.Discard, resp.Body)
+			return false, nil
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return true, err
+		}
+		return h.response(respBody)
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("%s %s returned %s: %s", h.config.Method, h.config.URL, resp.Status, bytes.TrimSpace(respBody))
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, err
+}
@@ -0,0 +1,75 @@
+package gologsgo_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestHTTPHandler(test *testing.T) {
+	var mu sync.Mutex
+	var batches [][]map[string]string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			// Fail the first attempt to exercise retries
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("Content-Encoding") != "gzip" {
+			test.Errorf("Unexpected headers: %v", r.Header)
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if nil != err {
+			test.Errorf("Expected a gzipped body: %s", err)
+			return
+		}
+		var batch []map[string]string
+		if err := json.NewDecoder(zr).Decode(&batch); nil != err {
+			test.Errorf("Expected a JSON array body: %s", err)
+		}
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewHTTPHandler(logs.HTTPHandlerConfig{
+		URL:          server.URL,
+		Headers:      http.Header{"Authorization": {"Bearer token"}},
+		Gzip:         true,
+		MaxBatchSize: 2,
+		MinBackoff:   time.Millisecond,
+	})
+	if nil != err {
+		test.Fatalf("Error creating HTTPHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("First")
+	logger.Info("Second")
+	logger.Info("Third")
+	handler.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		test.Fatalf("Expected batches of 2 and 1. Found: %v", batches)
+	}
+	if batches[0][0]["message"] != "First" || batches[1][0]["message"] != "Third" {
+		test.Errorf("Unexpected batches: %v", batches)
+	}
+	if handler.Dropped() != 0 {
+		test.Errorf("Expected no dropped entries. Found: %d", handler.Dropped())
+	}
+}
@@ -0,0 +1,170 @@
+package gologsgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// httpConfigTimeout limits each request of HttpConfig()
+const httpConfigTimeout = 30 * time.Second
+
+// HttpConfig fetches a JSON config from url, and polls it every interval for
+// changes, so a central service can control the levels of many processes:
+//
+//	config, err := logs.HttpConfig("https://logging.internal/my-app.json", time.Minute)
+//	if err != nil {
+//		panic(err)
+//	}
+//	logger := logs.New(config)
+//
+// Polls send the ETag and Last-Modified of the last response as If-None-Match and
+// If-Modified-Since, so an unchanged config costs the server a 304 Not Modified,
+// and a config is only applied when its content changes. Changed configs are
+// delivered through Updates, so only their levels are applied to the live Logger,
+// with ApplyLevels(). Failed polls and configs that fail to parse are reported
+// to LastResortWriter, and the current levels are kept. Until the config is
+// passed to New(), only the latest change is held for it. Polling stops when the
+// Logger is closed. An interval that is not positive fetches the config once.
+func HttpConfig(url string, interval time.Duration) (*RootLogConfig, error) {
+	p := &httpPoller{
+		url:    url,
+		client: &http.Client{Timeout: httpConfigTimeout},
+		done:   make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	body, modified, err := p.fetch()
+	if err != nil {
+		return nil, err
+	}
+	if !modified {
+		return nil, fmt.Errorf("Config request to %s returned 304 Not Modified", url)
+	}
+	config, err := JsonConfig(body)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid config from %s: %s", url, err)
+	}
+	p.last = body
+	if interval <= 0 {
+		return config, nil
+	}
+
+	// Only the latest config is held for the Logger, so a config that is never
+	// passed to New() doesn't block polling
+	updates := make(chan *RootLogConfig, 1)
+	config.Updates = updates
+	config.Closers = append(config.Closers, p)
+	go p.poll(interval, updates)
+	return config, nil
+}
+
+// httpPoller polls the config of HttpConfig()
+type httpPoller struct {
+	url    string
+	client *http.Client
+	// etag and lastModified are the validators of the last response
+	etag         string
+	lastModified string
+	// last is the config last applied
+	last []byte
+	done chan struct{}
+	once sync.Once
+	// ctx is canceled by Close() to interrupt requests
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// poll sends the configs that change to updates, replacing one that hasn't been
+// received yet, until the poller is closed
+func (p *httpPoller) poll(interval time.Duration, updates chan *RootLogConfig) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			return
+		}
+
+		body, modified, err := p.fetch()
+		if err != nil {
+			select {
+			case <-p.done:
+				return
+			default:
+			}
+			lastResortWarning("%s. Keeping the current levels", err)
+			continue
+		}
+		if !modified || bytes.Equal(body, p.last) {
+			continue
+		}
+		p.last = body
+		config, err := JsonConfig(body)
+		if err != nil {
+			lastResortWarning("Invalid config from %s: %s. Keeping the current levels", p.url, err)
+			continue
+		}
+		// The Logger releases the Closers of the configs it receives. Only
+		// this goroutine sends, so once a stale config is taken back there
+		// is room for the new one.
+		select {
+		case stale := <-updates:
+			for _, closer := range stale.Closers {
+				closer.Close()
+			}
+		default:
+		}
+		updates <- config
+	}
+}
+
+// fetch requests the config, reporting whether it was modified since the last
+// response
+func (p *httpPoller) fetch() ([]byte, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(p.etag) > 0 {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if len(p.lastModified) > 0 {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+
+	resp, err := p.client.Do(req.WithContext(p.ctx))
+	if err != nil {
+		return nil, false, fmt.Errorf("Config request to %s failed: %s", p.url, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("Config request to %s failed: %s", p.url, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		p.etag = resp.Header.Get("ETag")
+		p.lastModified = resp.Header.Get("Last-Modified")
+		return body, true, nil
+	case http.StatusNotModified:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("Config request to %s returned %s: %s", p.url, resp.Status, bytes.TrimSpace(body))
+	}
+}
+
+// Close stops polling
+func (p *httpPoller) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+		p.cancel()
+	})
+	return nil
+}
@@ -0,0 +1,113 @@
+package gologsgo_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestHttpConfig(test *testing.T) {
+	var mu sync.Mutex
+	body, etag := `{"level": "INFO"}`, `"v1"`
+	notModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	config, err := logs.HttpConfig(server.URL, 10*time.Millisecond)
+	if err != nil {
+		test.Fatal(err)
+	}
+	config.LogHandler = func(logs.LogMessage) {}
+	logger := logs.New(config)
+	defer logger.Close()
+	db := logger.ChildLogger("db")
+	if logger.Level() != logs.Info {
+		test.Errorf("Expected the fetched level. Found: %s", logs.LogLevels.Label(logger.Level()))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for polled := 0; polled == 0 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		polled = notModified
+		mu.Unlock()
+	}
+	mu.Lock()
+	if notModified == 0 {
+		test.Error("Expected polls of an unchanged config to send If-None-Match")
+	}
+	body, etag = `{"level": "WARN", "loggers": {"db": {"level": "TRACE"}}}`, `"v2"`
+	mu.Unlock()
+
+	waitForLevel(db, logs.Trace)
+	if logger.Level() != logs.Warn || db.Level() != logs.Trace {
+		test.Errorf("Expected the levels to be polled. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(db.Level()))
+	}
+}
+
+func TestHttpConfigErrors(test *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/invalid" {
+			w.Write([]byte(`{"level": 7`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/missing", "/invalid"} {
+		if _, err := logs.HttpConfig(server.URL+path, 0); err == nil {
+			test.Errorf("Expected an error for %s", path)
+		}
+	}
+}
+
+func TestHttpConfigKeepsLatestUpdate(test *testing.T) {
+	var mu sync.Mutex
+	version := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		// Every poll returns a new config
+		version++
+		fmt.Fprintf(w, `{"level": "INFO", "loggers": {"v%d": {"level": "DEBUG"}}}`, version)
+	}))
+	defer server.Close()
+
+	config, err := logs.HttpConfig(server.URL, time.Millisecond)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// Polling carries on while the updates aren't consumed
+	deadline := time.Now().Add(5 * time.Second)
+	for polled := 0; polled < 5 && time.Now().Before(deadline); {
+		mu.Lock()
+		polled = version
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	if version < 5 {
+		test.Errorf("Expected polling not to block on unconsumed updates. Found %d polls", version)
+	}
+	mu.Unlock()
+
+	config.LogHandler = func(logs.LogMessage) {}
+	logs.New(config).Close()
+}
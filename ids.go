@@ -0,0 +1,80 @@
+package gologsgo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"sync"
+)
+
+// IDGenerator returns a new unique ID, such as a request ID
+type IDGenerator func() string
+
+// HashFunc returns a new hash.Hash. It is used wherever this package hashes
+// data - fingerprints, redaction and tamper evident chaining - so security teams
+// can mandate a specific algorithm.
+type HashFunc func() hash.Hash
+
+var idGenerator IDGenerator = randomID
+var hashFunc HashFunc = sha256.New
+var idlock sync.RWMutex
+
+// SetIDGenerator replaces the IDGenerator used by NewID(). The default generates
+// 128 bit random IDs, hex encoded. Passing nil restores the default.
+func SetIDGenerator(gen IDGenerator) {
+	idlock.Lock()
+	defer idlock.Unlock()
+	if nil == gen {
+		gen = randomID
+	}
+	idGenerator = gen
+}
+
+// SetHashFunc replaces the HashFunc used by Hash(). The default is SHA-256.
+// Passing nil restores the default.
+func SetHashFunc(fn HashFunc) {
+	idlock.Lock()
+	defer idlock.Unlock()
+	if nil == fn {
+		fn = sha256.New
+	}
+	hashFunc = fn
+}
+
+// NewID returns a new unique ID from the configured IDGenerator
+func NewID() string {
+	idlock.RLock()
+	gen := idGenerator
+	idlock.RUnlock()
+	return gen()
+}
+
+// Hash returns the hex encoded digest of data using the configured HashFunc
+func Hash(data []byte) string {
+	idlock.RLock()
+	fn := hashFunc
+	idlock.RUnlock()
+
+	h := fn()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fingerprint returns a stable identifier for the log statement that produced a
+// LogMessage: a Hash() of its logger label, level and format string. Unlike the
+// message itself, it doesn't change with the statement's arguments.
+func Fingerprint(msg LogMessage) string {
+	return Hash([]byte(msg.Logger + "\x00" + LogLevels.Label(msg.Level) + "\x00" + msg.Format))
+}
+
+// randomID is the default IDGenerator
+func randomID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		// crypto/rand only fails if the OS can't supply randomness, in which case
+		// nothing else would work either
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,46 @@
+package gologsgo_test
+
+import (
+	"crypto/sha512"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestIDGenerator(test *testing.T) {
+	if a, b := logs.NewID(), logs.NewID(); len(a) != 32 || a == b {
+		test.Errorf("Expected unique 32 character IDs. Found: %s and %s", a, b)
+	}
+
+	logs.SetIDGenerator(func() string { return "fixed" })
+	defer logs.SetIDGenerator(nil)
+	if id := logs.NewID(); id != "fixed" {
+		test.Errorf("Expected the configured IDGenerator to be used. Found: %s", id)
+	}
+}
+
+func TestHashFunc(test *testing.T) {
+	sha256Hash := logs.Hash([]byte("data"))
+	if len(sha256Hash) != 64 {
+		test.Errorf("Expected a SHA-256 hash by default. Found: %s", sha256Hash)
+	}
+
+	logs.SetHashFunc(sha512.New)
+	defer logs.SetHashFunc(nil)
+	if sha512Hash := logs.Hash([]byte("data")); len(sha512Hash) != 128 {
+		test.Errorf("Expected the configured HashFunc to be used. Found: %s", sha512Hash)
+	}
+}
+
+func TestFingerprint(test *testing.T) {
+	a := logs.LogMessage{Level: logs.Info, Logger: "main", Format: "User %s logged in", Message: "User a logged in"}
+	b := logs.LogMessage{Level: logs.Info, Logger: "main", Format: "User %s logged in", Message: "User b logged in"}
+	c := logs.LogMessage{Level: logs.Warn, Logger: "main", Format: "User %s logged in", Message: "User a logged in"}
+
+	if logs.Fingerprint(a) != logs.Fingerprint(b) {
+		test.Error("Expected the same fingerprint for the same log statement")
+	}
+	if logs.Fingerprint(a) == logs.Fingerprint(c) {
+		test.Error("Expected different fingerprints for different levels")
+	}
+}
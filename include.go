@@ -0,0 +1,107 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// includeKeys are the keys of a JSON config file that list the files it is
+// layered over
+var includeKeys = []string{"include", "extends"}
+
+// jsonFileConfig creates a RootLogConfig from the JSON data of a config file,
+// with the files it includes merged under it. See FileConfig().
+func jsonFileConfig(path string, data []byte) (*RootLogConfig, error) {
+	cfg, err := jsonConfigMap(path, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return JsonConfig(merged)
+}
+
+// jsonConfigMap parses the JSON data of a config file read from path as an
+// object, after expanding environment variables in it. The files listed by its
+// "include" or "extends" key are read in order, and each is merged over the last,
+// with the file itself merged last. including holds the files that include path,
+// to detect cycles.
+func jsonConfigMap(path string, data []byte, including []string) (map[string]interface{}, error) {
+	cfg := make(map[string]interface{})
+	if err := json.Unmarshal(ExpandEnv(data), &cfg); err != nil {
+		return nil, fmt.Errorf("Invalid config file %s: %s", path, err)
+	}
+	includes, err := includePaths(path, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(includes) == 0 {
+		return cfg, nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	including = append(including, abs)
+	merged := make(map[string]interface{})
+	for _, include := range includes {
+		if abs, err := filepath.Abs(include); err == nil {
+			for _, p := range including {
+				if p == abs {
+					return nil, fmt.Errorf("Config file %s includes itself through %s", include, path)
+				}
+			}
+		}
+		data, err := ioutil.ReadFile(include)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to include %s in %s: %s", include, path, err)
+		}
+		layer, err := jsonConfigMap(include, data, including)
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(merged, layer)
+	}
+	mergeMaps(merged, cfg)
+	return merged, nil
+}
+
+// includePaths removes the include keys from cfg, returning the paths they list.
+// Relative paths are relative to the directory of path.
+func includePaths(path string, cfg map[string]interface{}) ([]string, error) {
+	var paths []string
+	for _, key := range includeKeys {
+		value, ok := cfg[key]
+		if !ok {
+			continue
+		}
+		delete(cfg, key)
+
+		var list []interface{}
+		switch v := value.(type) {
+		case string:
+			list = []interface{}{v}
+		case []interface{}:
+			list = v
+		case nil:
+		default:
+			return nil, fmt.Errorf("Invalid %q in %s. Use a path or a list of paths", key, path)
+		}
+		for _, item := range list {
+			include, ok := item.(string)
+			if !ok || len(include) == 0 {
+				return nil, fmt.Errorf("Invalid %q in %s. Use a path or a list of paths", key, path)
+			}
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(path), include)
+			}
+			paths = append(paths, include)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,50 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestFileConfigInclude(test *testing.T) {
+	dir, err := ioutil.TempDir("", "include")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, "shared"), 0755)
+	files := map[string]string{
+		"shared/org.json":  `{"level": "WARN", "label": "org", "loggers": {"db": {"level": "ERROR"}, "http": {"level": "INFO"}}}`,
+		"shared/team.json": `{"extends": "org.json", "loggers": {"http": {"level": "DEBUG"}}}`,
+		"service.json":     `{"include": ["shared/team.json"], "label": "service", "loggers": {"db": {"level": "TRACE"}}}`,
+		"cycle-a.json":     `{"include": "cycle-b.json"}`,
+		"cycle-b.json":     `{"include": ["cycle-a.json"]}`,
+		"missing.json":     `{"include": ["nowhere.json"]}`,
+		"invalid.json":     `{"include": 7}`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	config, err := logs.FileConfig(filepath.Join(dir, "service.json"))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Warn || config.Label != "service" {
+		test.Errorf("Expected the settings of the included files to be overridden. Found: %s, %s", logs.LogLevels.Label(config.Level), config.Label)
+	}
+	if config.Loggers["db"].Level != logs.Trace || config.Loggers["http"].Level != logs.Debug {
+		test.Errorf("Expected the loggers to be merged. Found: %s, %s", logs.LogLevels.Label(config.Loggers["db"].Level), logs.LogLevels.Label(config.Loggers["http"].Level))
+	}
+
+	for _, name := range []string{"cycle-a.json", "missing.json", "invalid.json"} {
+		if _, err := logs.FileConfig(filepath.Join(dir, name)); err == nil {
+			test.Errorf("Expected an error for %s", name)
+		}
+	}
+}
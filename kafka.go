@@ -0,0 +1,166 @@
+package gologsgo
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// KafkaMessage is a record published to Kafka by a KafkaHandler
+type KafkaMessage struct {
+	Topic string
+	Key   []byte
+	Value []byte
+	Time  time.Time
+}
+
+// KafkaProducer publishes a batch of messages to Kafka, returning once they have
+// been delivered or have failed. This package does not include a Kafka client;
+// adapt the one your application already uses. For example, with
+// github.com/segmentio/kafka-go:
+//
+//	type producer struct{ w *kafka.Writer }
+//
+//	func (p producer) Produce(ctx context.Context, msgs []logs.KafkaMessage) error {
+//		batch := make([]kafka.Message, len(msgs))
+//		for i, m := range msgs {
+//			batch[i] = kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value, Time: m.Time}
+//		}
+//		return p.w.WriteMessages(ctx, batch...)
+//	}
+type KafkaProducer interface {
+	Produce(ctx context.Context, msgs []KafkaMessage) error
+}
+
+// KafkaHandlerConfig configures a KafkaHandler
+type KafkaHandlerConfig struct {
+	Producer KafkaProducer
+	Topic    string
+	// Encoder serializes each message value. Defaults to JSONEncoder.
+	Encoder Encoder
+	// BatchSize is the most messages sent in one call to Produce(). Defaults to 100.
+	BatchSize int
+	// BatchTimeout is the longest a message waits for its batch to fill before
+	// being sent. Defaults to 1 second.
+	BatchTimeout time.Duration
+	// QueueSize is the number of entries held in memory waiting to be batched.
+	// When full, new entries are dropped. Defaults to 10000.
+	QueueSize int
+	// ProduceTimeout limits each call to Produce(). Defaults to 10 seconds.
+	ProduceTimeout time.Duration
+	// OnDeliveryFailure, when set, is called with each batch that could not be
+	// delivered
+	OnDeliveryFailure func(msgs []KafkaMessage, err error)
+}
+
+// KafkaHandler publishes log entries to a Kafka topic in batches, keyed by the
+// logger label so each logger's entries stay ordered within a partition.
+type KafkaHandler struct {
+	// dropped is first to guarantee 64-bit alignment for atomic operations
+	dropped uint64
+	config  KafkaHandlerConfig
+	batcher *Batcher
+	// health is failing after a batch could not be produced
+	health health
+}
+
+// NewKafkaHandler validates the config and starts a KafkaHandler
+func NewKafkaHandler(config KafkaHandlerConfig) (*KafkaHandler, error) {
+	if nil == config.Producer {
+		return nil, fmt.Errorf("KafkaHandler requires a Producer")
+	}
+	if len(config.Topic) < 1 {
+		return nil, fmt.Errorf("KafkaHandler requires a Topic")
+	}
+	if nil == config.Encoder {
+		config.Encoder = JSONEncoder
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = 100
+	}
+	if config.BatchTimeout <= 0 {
+		config.BatchTimeout = time.Second
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 10000
+	}
+	if config.ProduceTimeout <= 0 {
+		config.ProduceTimeout = 10 * time.Second
+	}
+
+	h := &KafkaHandler{config: config}
+	h.batcher = newBatcher(h.produce, config.BatchSize, config.BatchTimeout, config.QueueSize)
+
+	return h, nil
+}
+
+// LogHandler queues a LogMessage to be published. It is a LogHandler.
+func (h *KafkaHandler) LogHandler(msg LogMessage) {
+	h.batcher.LogHandler(msg)
+}
+
+// LogHandlerE queues a LogMessage to be published, like LogHandler, but returns
+// an error instead when the queue is full, and for ProduceTimeout after a batch
+// could not be produced. Entries are then accepted again, and the next batch
+// shows whether the brokers have recovered. Use it with Failover(). It is a
+// LogHandlerE.
+func (h *KafkaHandler) LogHandlerE(msg LogMessage) error {
+	if err := h.health.check(h.config.ProduceTimeout); err != nil {
+		return err
+	}
+	return h.batcher.enqueue(msg)
+}
+
+// Dropped returns the number of messages that were discarded because the queue
+// was full, they could not be encoded or delivered, or they were logged after
+// Close()
+func (h *KafkaHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped) + h.batcher.Dropped()
+}
+
+// Flush publishes everything queued so far and waits for it to be delivered. It
+// is part of FlushCloser.
+func (h *KafkaHandler) Flush() error {
+	return h.batcher.Flush()
+}
+
+// Close stops accepting messages, publishes everything still queued and waits
+// for it to be delivered
+func (h *KafkaHandler) Close() error {
+	return h.batcher.Close()
+}
+
+// produce encodes and publishes a batch. It is the BatchFunc of the Batcher.
+func (h *KafkaHandler) produce(batch []LogMessage) {
+	msgs := make([]KafkaMessage, 0, len(batch))
+	for _, msg := range batch {
+		value, err := h.config.Encoder(msg)
+		if err != nil {
+			drop(&h.dropped, 1)
+			continue
+		}
+		msgs = append(msgs, KafkaMessage{
+			Topic: h.config.Topic,
+			Key:   []byte(msg.Logger),
+			Value: value,
+			Time:  msg.Time,
+		})
+	}
+	if len(msgs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.ProduceTimeout)
+	defer cancel()
+	err := h.config.Producer.Produce(ctx, msgs)
+	if nil == err {
+		h.health.set(nil)
+		return
+	}
+	h.health.set(fmt.Errorf("Unable to publish logs to %s: %s", h.config.Topic, err))
+	drop(&h.dropped, uint64(len(msgs)))
+	if nil != h.config.OnDeliveryFailure {
+		h.config.OnDeliveryFailure(msgs, err)
+	}
+}
@@ -0,0 +1,141 @@
+package gologsgo_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+type fakeKafkaProducer struct {
+	mu      sync.Mutex
+	batches [][]logs.KafkaMessage
+	err     error
+}
+
+func (p *fakeKafkaProducer) Produce(ctx context.Context, msgs []logs.KafkaMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batches = append(p.batches, msgs)
+	return p.err
+}
+
+func TestKafkaHandler(test *testing.T) {
+	producer := &fakeKafkaProducer{}
+	handler, err := logs.NewKafkaHandler(logs.KafkaHandlerConfig{
+		Producer:  producer,
+		Topic:     "logs",
+		BatchSize: 2,
+	})
+	if nil != err {
+		test.Fatalf("Error creating KafkaHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("First")
+	logger.ChildLogger("db").Info("Second")
+	logger.Info("Third")
+	handler.Close()
+
+	if len(producer.batches) != 2 || len(producer.batches[0]) != 2 || len(producer.batches[1]) != 1 {
+		test.Fatalf("Expected batches of 2 and 1. Found: %v", producer.batches)
+	}
+	msg := producer.batches[0][1]
+	if msg.Topic != "logs" || string(msg.Key) != "main.db" {
+		test.Errorf("Unexpected message: %v", msg)
+	}
+}
+
+func TestKafkaHandlerDeliveryFailure(test *testing.T) {
+	producer := &fakeKafkaProducer{err: fmt.Errorf("broker unavailable")}
+	var failed int
+	handler, err := logs.NewKafkaHandler(logs.KafkaHandlerConfig{
+		Producer: producer,
+		Topic:    "logs",
+		OnDeliveryFailure: func(msgs []logs.KafkaMessage, err error) {
+			failed += len(msgs)
+		},
+	})
+	if nil != err {
+		test.Fatalf("Error creating KafkaHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{LogHandler: handler.LogHandler})
+	logger.Error("Lost")
+	handler.Close()
+
+	if failed != 1 || handler.Dropped() != 1 {
+		test.Errorf("Expected 1 failed delivery. Found: %d (dropped: %d)", failed, handler.Dropped())
+	}
+}
+
+func TestKafkaHandlerFlush(test *testing.T) {
+	producer := &fakeKafkaProducer{}
+	handler, err := logs.NewKafkaHandler(logs.KafkaHandlerConfig{
+		Producer:     producer,
+		Topic:        "logs",
+		BatchTimeout: time.Hour,
+	})
+	if nil != err {
+		test.Fatalf("Error creating KafkaHandler: %s", err)
+	}
+	defer handler.Close()
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("Flushed")
+	handler.Flush()
+
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	if len(producer.batches) != 1 || len(producer.batches[0]) != 1 {
+		test.Errorf("Expected the entry to be published by Flush. Found: %v", producer.batches)
+	}
+}
+
+func TestKafkaHandlerLogHandlerE(test *testing.T) {
+	producer := &fakeKafkaProducer{err: fmt.Errorf("broker unavailable")}
+	handler, err := logs.NewKafkaHandler(logs.KafkaHandlerConfig{
+		Producer:       producer,
+		Topic:          "logs",
+		ProduceTimeout: 20 * time.Millisecond,
+	})
+	if nil != err {
+		test.Fatalf("Error creating KafkaHandler: %s", err)
+	}
+	defer handler.Close()
+
+	if err := handler.LogHandlerE(logs.LogMessage{Message: "Failing"}); err != nil {
+		test.Fatalf("Expected the entry to be queued. Found: %v", err)
+	}
+	handler.Flush()
+	if err := handler.LogHandlerE(logs.LogMessage{Message: "Rejected"}); nil == err || !strings.Contains(err.Error(), "broker unavailable") {
+		test.Errorf("Expected an error while the brokers are unavailable. Found: %v", err)
+	}
+
+	producer.mu.Lock()
+	producer.err = nil
+	producer.mu.Unlock()
+	// Entries are rejected until ProduceTimeout has passed
+	err = handler.LogHandlerE(logs.LogMessage{Message: "Probe"})
+	for deadline := time.Now().Add(5 * time.Second); nil != err && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+		err = handler.LogHandlerE(logs.LogMessage{Message: "Probe"})
+	}
+	if err != nil {
+		test.Errorf("Expected entries to be accepted again after ProduceTimeout. Found: %v", err)
+	}
+	handler.Flush()
+	if err := handler.LogHandlerE(logs.LogMessage{Message: "Recovered"}); err != nil {
+		test.Errorf("Expected the handler to be healthy once a batch was produced. Found: %v", err)
+	}
+}
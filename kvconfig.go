@@ -0,0 +1,383 @@
+package gologsgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConsulWatchConfig configures Logger.WatchConsulConfig()
+type ConsulWatchConfig struct {
+	// Address is the URL of the Consul HTTP API. Defaults to
+	// "http://127.0.0.1:8500".
+	Address string
+	// Key is the KV path holding the JSON config, such as "my-app/log"
+	Key string
+	// Token, when set, is sent as an ACL token
+	Token string
+	// Datacenter, when set, is queried instead of the agent's datacenter
+	Datacenter string
+	// WaitTime is the longest a blocking query waits for a change. Defaults to 5
+	// minutes.
+	WaitTime time.Duration
+	// Client defaults to an http.Client with a timeout of WaitTime plus a minute
+	Client *http.Client
+	// MinBackoff is the delay before the first retry of a failed query. It
+	// doubles on each failure up to MaxBackoff. Defaults to 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// WatchConsulConfig watches a Consul KV path holding a JSON config with blocking
+// queries, and applies its levels with ApplyLevels() when it changes, for shops
+// using Consul as their source of truth for configuration. The current config is
+// applied when watching starts. Configs that fail to parse, and query errors, are
+// reported to LastResortWriter. Watching stops when the returned io.Closer, or
+// the Logger, is closed.
+func (logger *Logger) WatchConsulConfig(config ConsulWatchConfig) (io.Closer, error) {
+	if len(config.Key) == 0 {
+		return nil, fmt.Errorf("WatchConsulConfig requires a Key")
+	}
+	if len(config.Address) == 0 {
+		config.Address = "http://127.0.0.1:8500"
+	}
+	if _, err := url.Parse(config.Address); err != nil {
+		return nil, fmt.Errorf("Invalid Consul address: %s", err)
+	}
+	if config.WaitTime <= 0 {
+		config.WaitTime = 5 * time.Minute
+	}
+	if nil == config.Client {
+		config.Client = &http.Client{Timeout: config.WaitTime + time.Minute}
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+
+	w := &consulWatcher{
+		kvWatcher: newKVWatcher(logger, "Consul"),
+		config:    config,
+		index:     "0",
+	}
+	go retryWatch(w.done, config.MinBackoff, config.MaxBackoff, w.watch)
+	logger.RegisterCloser(w)
+	return w, nil
+}
+
+// consulWatcher applies the config at a Consul KV path when it changes
+type consulWatcher struct {
+	*kvWatcher
+	config ConsulWatchConfig
+	// index is the X-Consul-Index of the last query
+	index string
+	// last is the config last applied
+	last []byte
+}
+
+// watch runs blocking queries until one fails
+func (w *consulWatcher) watch() error {
+	for {
+		query := url.Values{"raw": {""}, "index": {w.index}, "wait": {fmt.Sprintf("%ds", int(w.config.WaitTime.Seconds()))}}
+		if len(w.config.Datacenter) > 0 {
+			query.Set("dc", w.config.Datacenter)
+		}
+		req, err := http.NewRequest(http.MethodGet, strings.TrimRight(w.config.Address, "/")+"/v1/kv/"+strings.TrimLeft(w.config.Key, "/")+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		if len(w.config.Token) > 0 {
+			req.Header.Set("X-Consul-Token", w.config.Token)
+		}
+
+		body, resp, err := w.do(w.config.Client, req)
+		if err != nil {
+			return fmt.Errorf("Consul config query for %s failed: %s", w.config.Key, err)
+		}
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("Consul config query for %s returned %s: %s", w.config.Key, resp.Status, bytes.TrimSpace(body))
+		}
+
+		// The index only moves forward; if it goes back, Consul's state was
+		// reset, and the query starts again from the beginning
+		next := resp.Header.Get("X-Consul-Index")
+		if n, err := strconv.ParseUint(next, 10, 64); err != nil || n == 0 {
+			w.index = "0"
+		} else if current, _ := strconv.ParseUint(w.index, 10, 64); n < current {
+			w.index = "0"
+		} else {
+			w.index = next
+		}
+		// Blocking queries also return when they time out, unchanged
+		if resp.StatusCode == http.StatusOK && !bytes.Equal(body, w.last) {
+			w.last = body
+			w.apply(body)
+		}
+	}
+}
+
+// EtcdWatchConfig configures Logger.WatchEtcdConfig()
+type EtcdWatchConfig struct {
+	// Endpoint is the URL of the etcd v3 HTTP gateway. Defaults to
+	// "http://127.0.0.1:2379".
+	Endpoint string
+	// Key holds the JSON config, such as "/config/my-app/log"
+	Key string
+	// Username and Password, when set, authenticate with etcd
+	Username string
+	Password string
+	// Client defaults to an http.Client without a timeout, as watches are long
+	// lived requests. Set its Transport to configure TLS.
+	Client *http.Client
+	// MinBackoff is the delay before the first retry of a failed watch. It
+	// doubles on each failure up to MaxBackoff. Defaults to 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// WatchEtcdConfig watches an etcd key holding a JSON config through the etcd v3
+// HTTP gateway, and applies its levels with ApplyLevels() when it changes, for
+// shops using etcd as their source of truth for configuration. The current config
+// is applied when watching starts. Configs that fail to parse, and watch errors,
+// are reported to LastResortWriter. Watching stops when the returned io.Closer,
+// or the Logger, is closed.
+func (logger *Logger) WatchEtcdConfig(config EtcdWatchConfig) (io.Closer, error) {
+	if len(config.Key) == 0 {
+		return nil, fmt.Errorf("WatchEtcdConfig requires a Key")
+	}
+	if len(config.Endpoint) == 0 {
+		config.Endpoint = "http://127.0.0.1:2379"
+	}
+	if _, err := url.Parse(config.Endpoint); err != nil {
+		return nil, fmt.Errorf("Invalid etcd endpoint: %s", err)
+	}
+	if nil == config.Client {
+		config.Client = &http.Client{}
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+
+	w := &etcdWatcher{
+		kvWatcher: newKVWatcher(logger, "etcd"),
+		config:    config,
+		endpoint:  strings.TrimRight(config.Endpoint, "/"),
+		key:       base64.StdEncoding.EncodeToString([]byte(config.Key)),
+	}
+	go retryWatch(w.done, config.MinBackoff, config.MaxBackoff, func() error {
+		err := w.watch()
+		if nil != err {
+			err = fmt.Errorf("etcd config watch of %s failed: %s", config.Key, err)
+		}
+		return err
+	})
+	logger.RegisterCloser(w)
+	return w, nil
+}
+
+// etcdWatcher applies the config at an etcd key when it changes
+type etcdWatcher struct {
+	*kvWatcher
+	config   EtcdWatchConfig
+	endpoint string
+	// key is base64 encoded, as the gateway expects
+	key string
+	// revision is the revision of the config last applied
+	revision int64
+	// token authenticates requests when Username is set
+	token string
+}
+
+// watch applies the current config, then each change to it until the watch
+// fails
+func (w *etcdWatcher) watch() error {
+	if len(w.config.Username) > 0 {
+		var auth struct {
+			Token string `json:"token"`
+		}
+		if err := w.call("/v3/auth/authenticate", map[string]string{"name": w.config.Username, "password": w.config.Password}, &auth); err != nil {
+			return err
+		}
+		w.token = auth.Token
+	}
+
+	// The current config is read first, so a change made while the watch was
+	// down isn't missed
+	var current struct {
+		Header struct {
+			Revision int64 `json:"revision,string"`
+		} `json:"header"`
+		Kvs []etcdKV `json:"kvs"`
+	}
+	if err := w.call("/v3/kv/range", map[string]string{"key": w.key}, &current); err != nil {
+		return err
+	}
+	if len(current.Kvs) > 0 && current.Kvs[0].ModRevision != w.revision {
+		w.revision = current.Kvs[0].ModRevision
+		w.apply(current.Kvs[0].Value)
+	}
+
+	req, err := w.request("/v3/watch", map[string]interface{}{
+		"create_request": map[string]interface{}{
+			"key":            w.key,
+			"start_revision": strconv.FormatInt(current.Header.Revision+1, 10),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := w.config.Client.Do(req.WithContext(w.ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Watch returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	// The response is a stream of JSON objects, one per batch of changes
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var update struct {
+			Result struct {
+				Canceled     bool   `json:"canceled"`
+				CancelReason string `json:"cancel_reason"`
+				Events       []struct {
+					Type string `json:"type"`
+					Kv   etcdKV `json:"kv"`
+				} `json:"events"`
+			} `json:"result"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := decoder.Decode(&update); err != nil {
+			return err
+		}
+		if nil != update.Error {
+			return fmt.Errorf("Watch failed: %s", update.Error.Message)
+		}
+		if update.Result.Canceled {
+			return fmt.Errorf("Watch canceled: %s", update.Result.CancelReason)
+		}
+		for _, event := range update.Result.Events {
+			// Deleting the key keeps the current levels
+			if event.Type != "DELETE" {
+				w.revision = event.Kv.ModRevision
+				w.apply(event.Kv.Value)
+			}
+		}
+	}
+}
+
+// request builds a gateway request with a JSON body
+func (w *etcdWatcher) request(path string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.token) > 0 {
+		req.Header.Set("Authorization", w.token)
+	}
+	return req, nil
+}
+
+// call sends a gateway request, decoding its JSON response into result
+func (w *etcdWatcher) call(path string, body interface{}, result interface{}) error {
+	req, err := w.request(path, body)
+	if err != nil {
+		return err
+	}
+	data, resp, err := w.do(w.config.Client, req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s: %s", path, resp.Status, bytes.TrimSpace(data))
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("Invalid response from %s: %s", path, err)
+	}
+	return nil
+}
+
+// etcdKV is a key-value pair returned by the etcd v3 HTTP gateway
+type etcdKV struct {
+	// Value is base64 encoded in JSON, which []byte decodes
+	Value       []byte `json:"value"`
+	ModRevision int64  `json:"mod_revision,string"`
+}
+
+// kvWatcher applies the configs read from a key-value store
+type kvWatcher struct {
+	logger *Logger
+	store  string
+	done   chan struct{}
+	once   sync.Once
+	// ctx is canceled by Close() to interrupt requests
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newKVWatcher(logger *Logger, store string) *kvWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &kvWatcher{
+		logger: logger,
+		store:  store,
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// do sends a request that can be interrupted by Close(), returning the response
+// body
+func (w *kvWatcher) do(client *http.Client, req *http.Request) ([]byte, *http.Response, error) {
+	resp, err := client.Do(req.WithContext(w.ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp, err
+}
+
+// apply applies the levels of a JSON config
+func (w *kvWatcher) apply(data []byte) {
+	config, err := JsonConfig(data)
+	if err != nil {
+		lastResortWarning("Invalid config from %s: %s. Keeping the current levels", w.store, err)
+		return
+	}
+	w.logger.applyReloaded(config)
+}
+
+// Close stops watching the store
+func (w *kvWatcher) Close() error {
+	w.once.Do(func() {
+		close(w.done)
+		w.cancel()
+	})
+	return nil
+}
@@ -0,0 +1,137 @@
+package gologsgo_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// waitForLevel waits for a logger to reach a level
+func waitForLevel(logger *logs.Logger, level logs.LogLevel) {
+	deadline := time.Now().Add(5 * time.Second)
+	for logger.Level() != level && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchConsulConfig(test *testing.T) {
+	changed := make(chan string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/my-app/log" || r.Header.Get("X-Consul-Token") != "secret" {
+			http.Error(w, "Unexpected request", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Query().Get("index") {
+		case "0":
+			w.Header().Set("X-Consul-Index", "5")
+			fmt.Fprint(w, `{"level": "WARN"}`)
+		case "5":
+			select {
+			case config := <-changed:
+				w.Header().Set("X-Consul-Index", "6")
+				fmt.Fprint(w, config)
+			case <-r.Context().Done():
+			}
+		default:
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	db := logger.ChildLogger("db")
+	watcher, err := logger.WatchConsulConfig(logs.ConsulWatchConfig{
+		Address: server.URL,
+		Key:     "my-app/log",
+		Token:   "secret",
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer watcher.Close()
+
+	waitForLevel(logger, logs.Warn)
+	if logger.Level() != logs.Warn {
+		test.Errorf("Expected the current config to be applied. Found: %s", logs.LogLevels.Label(logger.Level()))
+	}
+	changed <- `{"level": "WARN", "loggers": {"db": {"level": "TRACE"}}}`
+	waitForLevel(db, logs.Trace)
+	if db.Level() != logs.Trace {
+		test.Errorf("Expected the changed config to be applied. Found: %s", logs.LogLevels.Label(db.Level()))
+	}
+}
+
+func TestWatchEtcdConfig(test *testing.T) {
+	changed := make(chan string)
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			if body["key"] != encode("/config/my-app/log") {
+				http.Error(w, "Unexpected key", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"header": {"revision": "10"}, "kvs": [{"value": %q, "mod_revision": "10"}]}`, encode(`{"level": "WARN"}`))
+		case "/v3/watch":
+			create, _ := body["create_request"].(map[string]interface{})
+			if create["start_revision"] != "11" {
+				http.Error(w, "Unexpected revision", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"result": {"created": true}}`)
+			w.(http.Flusher).Flush()
+			select {
+			case config := <-changed:
+				fmt.Fprintf(w, `{"result": {"events": [{"kv": {"value": %q, "mod_revision": "12"}}]}}`, encode(config))
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	db := logger.ChildLogger("db")
+	watcher, err := logger.WatchEtcdConfig(logs.EtcdWatchConfig{
+		Endpoint: server.URL,
+		Key:      "/config/my-app/log",
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer watcher.Close()
+
+	waitForLevel(logger, logs.Warn)
+	if logger.Level() != logs.Warn {
+		test.Errorf("Expected the current config to be applied. Found: %s", logs.LogLevels.Label(logger.Level()))
+	}
+	changed <- `{"level": "WARN", "loggers": {"db": {"level": "TRACE"}}}`
+	waitForLevel(db, logs.Trace)
+	if db.Level() != logs.Trace {
+		test.Errorf("Expected the changed config to be applied. Found: %s", logs.LogLevels.Label(db.Level()))
+	}
+}
+
+func TestWatchKVConfigErrors(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	if _, err := logger.WatchConsulConfig(logs.ConsulWatchConfig{}); err == nil {
+		test.Error("Expected an error without a Consul Key")
+	}
+	if _, err := logger.WatchEtcdConfig(logs.EtcdWatchConfig{}); err == nil {
+		test.Error("Expected an error without an etcd Key")
+	}
+}
@@ -0,0 +1,60 @@
+package gologsgo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LabelFormat controls how logger labels are rendered in LogMessage.Logger, for
+// teams with deep logger trees. Labels are still configured (in "loggers") and
+// looked up by their dotted names.
+type LabelFormat struct {
+	// Separator is written between the names of a label, such as "/" or "::".
+	// Defaults to ".".
+	Separator string `json:"separator,omitempty"`
+	// Depth, when positive, renders only the last Depth names of a label. 1
+	// renders only the leaf name.
+	Depth int `json:"depth,omitempty"`
+	// Abbreviate shortens the names of a label, except the last, to their first
+	// letter, so "billing.invoices.pdf" becomes "b.i.pdf"
+	Abbreviate bool `json:"abbreviate,omitempty"`
+}
+
+// Validate returns an error if the Depth is negative
+func (f LabelFormat) Validate() error {
+	if f.Depth < 0 {
+		return fmt.Errorf("Label depth must not be negative. Found: %d", f.Depth)
+	}
+	return nil
+}
+
+// Render renders a dotted logger label
+func (f LabelFormat) Render(label string) string {
+	if len(label) == 0 {
+		return label
+	}
+	names := strings.Split(label, ".")
+	if f.Depth > 0 && len(names) > f.Depth {
+		names = names[len(names)-f.Depth:]
+	}
+	if f.Abbreviate {
+		for i := 0; i < len(names)-1; i++ {
+			if r := []rune(names[i]); len(r) > 0 {
+				names[i] = string(r[0])
+			}
+		}
+	}
+	separator := f.Separator
+	if len(separator) == 0 {
+		separator = "."
+	}
+	return strings.Join(names, separator)
+}
+
+// render renders a label with f, or returns it unchanged when f is nil
+func (f *LabelFormat) render(label string) string {
+	if nil == f {
+		return label
+	}
+	return f.Render(label)
+}
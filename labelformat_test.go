@@ -0,0 +1,54 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLabelFormat(test *testing.T) {
+	for _, c := range []struct {
+		format   logs.LabelFormat
+		expected string
+	}{
+		{logs.LabelFormat{}, "app.billing.invoices.pdf"},
+		{logs.LabelFormat{Separator: "::"}, "app::billing::invoices::pdf"},
+		{logs.LabelFormat{Depth: 1}, "pdf"},
+		{logs.LabelFormat{Depth: 2, Separator: "/"}, "invoices/pdf"},
+		{logs.LabelFormat{Abbreviate: true}, "a.b.i.pdf"},
+	} {
+		if actual := c.format.Render("app.billing.invoices.pdf"); actual != c.expected {
+			test.Errorf("Expected %q for %+v. Found: %q", c.expected, c.format, actual)
+		}
+	}
+}
+
+func TestLabelFormatConfig(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(`{
+		"label": "app",
+		"labelFormat": {"separator": "/", "depth": 2},
+		"loggers": {"billing": {"level": "DEBUG"}}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	var messages []logs.LogMessage
+	config.LogHandler = func(msg logs.LogMessage) {
+		messages = append(messages, msg)
+	}
+	root := logs.New(config)
+	// Children can be named with the separator too
+	root.ChildLogger("billing/invoices").Debug("Rendered")
+	root.ChildLogger("billing.invoices.pdf").Info("Written")
+
+	if len(messages) != 2 || messages[0].Logger != "billing/invoices" || messages[1].Logger != "invoices/pdf" {
+		test.Errorf("Unexpected messages: %+v", messages)
+	}
+	if label := root.ChildLogger("billing/invoices").Label(); label != "app.billing.invoices" {
+		test.Errorf("Expected the dotted label. Found: %q", label)
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"labelFormat": {"depth": -1}}`)); err == nil {
+		test.Error("Expected an error for a negative depth")
+	}
+}
@@ -0,0 +1,144 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SetLabel replaces the label printed for a level, for example "WARNING" instead
+// of "WARN", or a lowercase label. The previous label is still accepted when
+// parsing levels from config. Labels are global, so SetLabel is usually called
+// once, before any loggers are created.
+func (ll *orderedLogLevels) SetLabel(level LogLevel, label string) error {
+	if len(label) == 0 {
+		return fmt.Errorf("Level labels can't be empty")
+	}
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	previous, ok := ll.labels[level]
+	if !ok || level == All || level == Off {
+		return fmt.Errorf("Only the labels of the TRACE, DEBUG, INFO, WARN and ERROR levels can be set")
+	}
+	for other, l := range ll.labels {
+		if other != level && strings.EqualFold(l, label) {
+			return fmt.Errorf("The label %q is already used by another level", label)
+		}
+	}
+
+	if nil == ll.aliases {
+		ll.aliases = make(map[string]LogLevel)
+	}
+	ll.aliases[previous] = level
+	delete(ll.aliases, label)
+	ll.labels[level] = label
+	// A label may have been cached for another level when it was an alias
+	ll.ordinalsCache = nil
+	return nil
+}
+
+// setLabels applies the "levelLabels" setting of a config, which is keyed by the
+// levels' current labels
+func setLabels(labels map[string]string) error {
+	return LogLevels.setLabels(labels)
+}
+
+func (ll *orderedLogLevels) setLabels(labels map[string]string) error {
+	for current, label := range labels {
+		level, ok := ll.Level(current)
+		if !ok {
+			return fmt.Errorf("Unknown level %q in levelLabels", current)
+		}
+		if err := ll.SetLabel(level, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clone returns a copy of the levels, which labels can be set on without
+// changing these
+func (ll *orderedLogLevels) clone() *orderedLogLevels {
+	ll.mu.RLock()
+	defer ll.mu.RUnlock()
+
+	c := &orderedLogLevels{
+		order:   ll.order,
+		labels:  make(map[LogLevel]string, len(ll.labels)),
+		aliases: make(map[string]LogLevel, len(ll.aliases)),
+	}
+	for level, label := range ll.labels {
+		c.labels[level] = label
+	}
+	for alias, level := range ll.aliases {
+		c.aliases[alias] = level
+	}
+	return c
+}
+
+var (
+	// configLevels, while a config is parsed, are the levels with the labels
+	// set by its "levelLabels", so its levels may be written with them before
+	// New() applies them to LogLevels. It is guarded by configLevelsMu.
+	configLevels   *orderedLogLevels
+	configLevelsMu sync.RWMutex
+	// parsingConfig is held while a config with labels is parsed, so configs
+	// with different labels are parsed one at a time
+	parsingConfig sync.Mutex
+)
+
+// withConfigLabels calls parse with the "levelLabels" of a JSON config accepted
+// by parseLevel(), without applying them to LogLevels
+func withConfigLabels(data []byte, parse func() error) error {
+	var labels struct {
+		LevelLabels map[string]string `json:"levelLabels"`
+	}
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return err
+	}
+	if len(labels.LevelLabels) == 0 {
+		return parse()
+	}
+
+	levels := LogLevels.clone()
+	if err := levels.setLabels(labels.LevelLabels); err != nil {
+		return err
+	}
+
+	parsingConfig.Lock()
+	defer parsingConfig.Unlock()
+	configLevelsMu.Lock()
+	configLevels = levels
+	configLevelsMu.Unlock()
+	defer func() {
+		configLevelsMu.Lock()
+		configLevels = nil
+		configLevelsMu.Unlock()
+	}()
+	return parse()
+}
+
+// parseLevel returns the level with a label, like LogLevels.Level(), also
+// accepting the labels of the config being parsed
+func parseLevel(label string) (LogLevel, bool) {
+	configLevelsMu.RLock()
+	levels := configLevels
+	configLevelsMu.RUnlock()
+	if nil != levels {
+		return levels.Level(label)
+	}
+	return LogLevels.Level(label)
+}
+
+// levelLabel is the label handlers print for an entry. Entries are created with
+// the label of their level; those are printed as configured with SetLabel().
+// Other labels are printed in upper case.
+func levelLabel(msg LogMessage) string {
+	label := LogLevels.Label(msg.Level)
+	if len(label) > 0 && strings.EqualFold(label, msg.LevelLabel) {
+		return label
+	}
+	return strings.ToUpper(msg.LevelLabel)
+}
@@ -0,0 +1,82 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLevelLabels(test *testing.T) {
+	defer logs.LogLevels.SetLabel(logs.Warn, "WARN")
+	defer logs.LogLevels.SetLabel(logs.Info, "INFO")
+
+	var buf bytes.Buffer
+	output := log.Writer()
+	flags := log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer log.SetOutput(output)
+	defer log.SetFlags(flags)
+
+	config, err := logs.JsonConfig([]byte(`{
+		"level": "warning",
+		"levelLabels": {"WARN": "WARNING", "INFO": "info"}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Warn {
+		test.Errorf("Expected WARNING to parse as the WARN level. Found: %d", config.Level)
+	}
+
+	logger := logs.New(config)
+	logger.Warn("Disk nearly full")
+	if buf.String() != "WARNING: Disk nearly full\n" {
+		test.Errorf("Unexpected output: %q", buf.String())
+	}
+
+	// The previous labels are still accepted
+	if level, ok := logs.LogLevels.Level("WARN"); !ok || level != logs.Warn {
+		test.Error("Expected WARN to still be accepted")
+	}
+	data, _ := logs.JSONEncoder(logs.LogMessage{Level: logs.Info, LevelLabel: "INFO", Message: "hi"})
+	if !bytes.Contains(data, []byte(`"level":"info"`)) {
+		test.Errorf("Expected the lowercase label. Found: %s", data)
+	}
+
+	if err := logs.LogLevels.SetLabel(logs.Error, "warning"); err == nil {
+		test.Error("Expected an error for a label used by another level")
+	}
+	if err := logs.LogLevels.SetLabel(logs.Off, "NONE"); err == nil {
+		test.Error("Expected an error for the OFF level")
+	}
+}
+
+func TestLevelLabelsAppliedByNew(test *testing.T) {
+	defer logs.LogLevels.SetLabel(logs.Warn, "WARN")
+
+	data := []byte(`{"level": "CAUTION", "levelLabels": {"WARN": "CAUTION"}}`)
+	config, err := logs.JsonConfig(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if _, err := logs.StrictJsonConfig(data); err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Warn {
+		test.Errorf("Expected CAUTION to parse as the WARN level. Found: %d", config.Level)
+	}
+	if label := logs.LogLevels.Label(logs.Warn); label != "WARN" {
+		test.Errorf("Expected parsing to leave the labels unchanged. Found: %s", label)
+	}
+	if _, ok := logs.LogLevels.Level("CAUTION"); ok {
+		test.Error("Expected CAUTION to be unknown until the config is applied")
+	}
+
+	logs.New(config)
+	if label := logs.LogLevels.Label(logs.Warn); label != "CAUTION" {
+		test.Errorf("Expected New to apply the labels. Found: %s", label)
+	}
+}
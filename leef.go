@@ -0,0 +1,83 @@
+package gologsgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LEEFConfig sets the header fields of LEEFEncoder
+type LEEFConfig struct {
+	// Vendor, Product and Version identify the sending device. They default to
+	// "big-squid", "go-logs-go" and "1".
+	Vendor  string
+	Product string
+	Version string
+	// Delimiter separates the attributes. Defaults to a tab.
+	Delimiter rune
+}
+
+// leefSeverities maps log levels to LEEF severities (1 to 10)
+var leefSeverities = map[LogLevel]int{
+	Trace: 1,
+	Debug: 2,
+	Info:  3,
+	Warn:  6,
+	Error: 8,
+}
+
+var leefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+
+// LEEFEncoder returns an Encoder that produces IBM QRadar Log Event Extended
+// Format 2.0 records. The event ID is the entry's "event_id" field, or its
+// Fingerprint() when it has none. The attributes hold the time (devTime), the
+// severity (sev), the logger (cat), the message (msg) and the fields.
+func LEEFEncoder(config LEEFConfig) Encoder {
+	vendor, product, version := siemHeader(config.Vendor, config.Product, config.Version)
+	delimiter := config.Delimiter
+	if delimiter == 0 {
+		delimiter = '\t'
+	}
+	delim := string(delimiter)
+
+	// Unprintable delimiters are declared by their hex value
+	declared := delim
+	if delimiter <= ' ' || delimiter == '|' || delimiter == '\\' {
+		declared = fmt.Sprintf("x%02X", delimiter)
+	}
+	prefix := "LEEF:2.0|" + leefHeaderEscaper.Replace(vendor) + "|" + leefHeaderEscaper.Replace(product) + "|" + leefHeaderEscaper.Replace(version) + "|"
+	valueEscaper := strings.NewReplacer(`\`, `\\`, delim, `\`+delim, "\n", `\n`, "\r", `\r`)
+
+	return func(msg LogMessage) ([]byte, error) {
+		severity, ok := leefSeverities[msg.Level]
+		if !ok {
+			severity = 1
+		}
+
+		var b strings.Builder
+		b.WriteString(prefix)
+		b.WriteString(leefHeaderEscaper.Replace(siemEventID(msg)))
+		b.WriteString("|")
+		b.WriteString(declared)
+		b.WriteString("|devTime=")
+		b.WriteString(strconv.FormatInt(msg.Time.UnixNano()/1e6, 10))
+		b.WriteString(delim)
+		b.WriteString("sev=")
+		b.WriteString(strconv.Itoa(severity))
+		if len(msg.Logger) > 0 {
+			b.WriteString(delim)
+			b.WriteString("cat=")
+			b.WriteString(valueEscaper.Replace(msg.Logger))
+		}
+		b.WriteString(delim)
+		b.WriteString("msg=")
+		b.WriteString(valueEscaper.Replace(msg.Message))
+		for _, k := range siemFieldKeys(msg.Fields) {
+			b.WriteString(delim)
+			b.WriteString(siemKey(k))
+			b.WriteString("=")
+			b.WriteString(valueEscaper.Replace(fmt.Sprint(msg.Fields[k])))
+		}
+		return []byte(b.String()), nil
+	}
+}
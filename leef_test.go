@@ -0,0 +1,40 @@
+package gologsgo_test
+
+import (
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLEEFEncoder(test *testing.T) {
+	msg := logs.LogMessage{
+		Level:      logs.Error,
+		LevelLabel: "ERROR",
+		Logger:     "auth",
+		Message:    "Account locked\tafter retries",
+		Time:       time.Unix(1500000000, 0),
+		Fields: map[string]interface{}{
+			"event_id": "account-locked",
+			"usrName":  "jane",
+		},
+	}
+
+	data, err := logs.LEEFEncoder(logs.LEEFConfig{Vendor: "Acme", Product: "Billing", Version: "2.0"})(msg)
+	if err != nil {
+		test.Fatal(err)
+	}
+	expected := "LEEF:2.0|Acme|Billing|2.0|account-locked|x09|devTime=1500000000000\tsev=8\tcat=auth\tmsg=Account locked\\\tafter retries\tusrName=jane"
+	if string(data) != expected {
+		test.Errorf("Expected:\n%q\nFound:\n%q", expected, data)
+	}
+
+	data, err = logs.LEEFEncoder(logs.LEEFConfig{Delimiter: '^'})(msg)
+	if err != nil {
+		test.Fatal(err)
+	}
+	expected = "LEEF:2.0|big-squid|go-logs-go|1|account-locked|^|devTime=1500000000000^sev=8^cat=auth^msg=Account locked\tafter retries^usrName=jane"
+	if string(data) != expected {
+		test.Errorf("Expected:\n%q\nFound:\n%q", expected, data)
+	}
+}
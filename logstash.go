@@ -0,0 +1,52 @@
+package gologsgo
+
+import (
+	"encoding/json"
+)
+
+// logstashTimestamp is the layout Logstash parses @timestamp with
+const logstashTimestamp = "2006-01-02T15:04:05.000Z07:00"
+
+// LogstashEncoder returns an Encoder that produces Logstash JSON events, using the
+// same field names as logstash-logback-encoder: `@timestamp`, `@version`,
+// `message`, `level`, `logger_name` and `tags`. Fields are added at the top level
+// and can't replace the standard keys.
+func LogstashEncoder(tags ...string) Encoder {
+	return func(msg LogMessage) ([]byte, error) {
+		event := make(map[string]interface{}, len(msg.Fields)+6)
+		for k, v := range msg.Fields {
+			event[k] = v
+		}
+		event["@timestamp"] = msg.Time.UTC().Format(logstashTimestamp)
+		event["@version"] = "1"
+		event["message"] = msg.Message
+		event["level"] = levelLabel(msg)
+		event["logger_name"] = msg.Logger
+		if len(tags) > 0 {
+			event["tags"] = tags
+		}
+		return json.Marshal(event)
+	}
+}
+
+// LogstashHandlerConfig configures a Logstash handler
+type LogstashHandlerConfig struct {
+	// NetworkHandlerConfig configures the connection to the Logstash tcp input
+	// (which should use the json_lines codec). Network defaults to "tcp".
+	// Encoder and Framing are ignored.
+	NetworkHandlerConfig
+	// Tags are added to every event
+	Tags []string
+}
+
+// NewLogstashHandler returns a NetworkHandler that sends newline delimited
+// Logstash JSON events, for drop-in ingestion by a Logstash tcp input or the
+// ELK stack.
+func NewLogstashHandler(config LogstashHandlerConfig) (*NetworkHandler, error) {
+	if len(config.Network) == 0 {
+		config.Network = "tcp"
+	}
+	config.Encoder = LogstashEncoder(config.Tags...)
+	config.Framing = NewlineFraming
+	return NewNetworkHandler(config.NetworkHandlerConfig)
+}
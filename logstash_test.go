@@ -0,0 +1,46 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLogstashEncoder(test *testing.T) {
+	encode := logs.LogstashEncoder("billing", "prod")
+	data, err := encode(logs.LogMessage{
+		Level:      logs.Warn,
+		LevelLabel: "WARN",
+		Logger:     "main.db",
+		Message:    "A warn log message",
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC),
+		Fields:     map[string]interface{}{"attempt": 2, "message": "ignored"},
+	})
+	if nil != err {
+		test.Fatalf("Error encoding LogMessage: %s", err)
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(data, &event); nil != err {
+		test.Fatalf("Unable to parse %q as JSON: %s", data, err)
+	}
+
+	expected := map[string]interface{}{
+		"@timestamp":  "2020-01-02T03:04:05.006Z",
+		"@version":    "1",
+		"message":     "A warn log message",
+		"level":       "WARN",
+		"logger_name": "main.db",
+		"attempt":     float64(2),
+	}
+	for k, v := range expected {
+		if event[k] != v {
+			test.Errorf("Expected %s to be %v. Found: %v", k, v, event[k])
+		}
+	}
+	if tags, ok := event["tags"].([]interface{}); !ok || len(tags) != 2 {
+		test.Errorf("Expected 2 tags. Found: %v", event["tags"])
+	}
+}
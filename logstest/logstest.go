@@ -0,0 +1,166 @@
+// Package logstest helps tests assert on the records logged by code under test,
+// by level, logger, fingerprint or field, instead of comparing formatted output.
+package logstest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// Matcher reports whether a record is one a test is looking for
+type Matcher func(logs.LogMessage) bool
+
+// Level matches records logged at the given level
+func Level(level logs.LogLevel) Matcher {
+	return func(msg logs.LogMessage) bool {
+		return msg.Level == level
+	}
+}
+
+// Logger matches records logged by the logger with the given label
+func Logger(label string) Matcher {
+	return func(msg logs.LogMessage) bool {
+		return msg.Logger == label
+	}
+}
+
+// Message matches records with exactly the given (formatted) message
+func Message(message string) Matcher {
+	return func(msg logs.LogMessage) bool {
+		return msg.Message == message
+	}
+}
+
+// MessageContains matches records whose (formatted) message contains substr
+func MessageContains(substr string) Matcher {
+	return func(msg logs.LogMessage) bool {
+		return strings.Contains(msg.Message, substr)
+	}
+}
+
+// Fingerprint matches records logged with the given format string, regardless of
+// the arguments it was formatted with
+func Fingerprint(format string) Matcher {
+	return func(msg logs.LogMessage) bool {
+		return msg.Format == format
+	}
+}
+
+// Field matches records with a field set to the given value. Values are compared
+// with reflect.DeepEqual, so maps and slices can be matched too.
+func Field(key string, value interface{}) Matcher {
+	return func(msg logs.LogMessage) bool {
+		v, ok := msg.Fields[key]
+		return ok && reflect.DeepEqual(v, value)
+	}
+}
+
+// Code matches records with a "code" field set to the given value
+func Code(code interface{}) Matcher {
+	return Field("code", code)
+}
+
+// Hook records everything logged by a Logger and its children - at every level,
+// regardless of their configured levels - so tests can assert on it.
+type Hook struct {
+	capture *logs.Capture
+	mu      sync.Mutex
+	offset  int
+}
+
+// Attach starts recording everything logged by the logger and its children. The
+// Hook stops recording when the test ends.
+func Attach(t testing.TB, logger *logs.Logger) *Hook {
+	ctx, cancel := context.WithCancel(context.Background())
+	hook := &Hook{
+		capture: logger.Capture(ctx, logs.CaptureOptions{}),
+	}
+	t.Cleanup(func() {
+		cancel()
+		<-hook.capture.Done()
+	})
+	return hook
+}
+
+// NewLogger returns a root Logger that discards its output, with a Hook attached
+func NewLogger(t testing.TB, label string) (*logs.Logger, *Hook) {
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      label,
+		Level:      logs.All,
+		LogHandler: func(logs.LogMessage) {},
+	})
+	return logger, Attach(t, logger)
+}
+
+// Entries returns the records logged since the Hook was attached or last Reset()
+func (h *Hook) Entries() []logs.LogMessage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.capture.Records()[h.offset:]
+}
+
+// Reset forgets the records logged so far
+func (h *Hook) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.offset = h.capture.Count()
+}
+
+// Find returns the records that match every matcher
+func (h *Hook) Find(matchers ...Matcher) []logs.LogMessage {
+	var found []logs.LogMessage
+	for _, msg := range h.Entries() {
+		if matchAll(msg, matchers) {
+			found = append(found, msg)
+		}
+	}
+	return found
+}
+
+// AssertLogged fails the test unless a record matching every matcher was logged.
+// It returns the first matching record.
+func (h *Hook) AssertLogged(t testing.TB, matchers ...Matcher) logs.LogMessage {
+	t.Helper()
+	found := h.Find(matchers...)
+	if len(found) == 0 {
+		t.Errorf("Expected a matching log record. Logged:\n%s", h.describe())
+		return logs.LogMessage{}
+	}
+	return found[0]
+}
+
+// AssertNotLogged fails the test if a record matching every matcher was logged
+func (h *Hook) AssertNotLogged(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	if found := h.Find(matchers...); len(found) > 0 {
+		t.Errorf("Expected no matching log records. Found %d, the first being: %s %s: %s",
+			len(found), found[0].LevelLabel, found[0].Logger, found[0].Message)
+	}
+}
+
+func (h *Hook) describe() string {
+	entries := h.Entries()
+	if len(entries) == 0 {
+		return "  (nothing)"
+	}
+	lines := make([]string, len(entries))
+	for i, msg := range entries {
+		lines[i] = fmt.Sprintf("  %s [%s]: %s", msg.LevelLabel, msg.Logger, msg.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func matchAll(msg logs.LogMessage, matchers []Matcher) bool {
+	for _, match := range matchers {
+		if !match(msg) {
+			return false
+		}
+	}
+	return true
+}
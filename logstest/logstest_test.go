@@ -0,0 +1,56 @@
+package logstest_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+	"github.com/big-squid/go-logs-go/logstest"
+)
+
+// chargeCard stands in for code under test that logs deep inside
+func chargeCard(logger *logs.Logger, amount int) {
+	log := logger.ChildLogger("payments")
+	log.SetField("code", "PAY-001")
+	log.Debug("Charging %d cents", amount)
+	log.Warn("Card declined")
+}
+
+func TestHook(test *testing.T) {
+	root := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		Level:      logs.Error,
+		LogHandler: func(logs.LogMessage) {},
+	})
+	hook := logstest.Attach(test, root)
+
+	chargeCard(root, 500)
+
+	msg := hook.AssertLogged(test, logstest.Fingerprint("Charging %d cents"), logstest.Level(logs.Debug))
+	if msg.Message != "Charging 500 cents" {
+		test.Errorf("Unexpected message: %s", msg.Message)
+	}
+	hook.AssertLogged(test, logstest.Code("PAY-001"), logstest.Logger("main.payments"), logstest.Message("Card declined"))
+	hook.AssertNotLogged(test, logstest.Level(logs.Error))
+
+	hook.Reset()
+	if len(hook.Entries()) != 0 {
+		test.Errorf("Expected no entries after Reset(). Found: %d", len(hook.Entries()))
+	}
+}
+
+func TestFieldMatchesStructuredValues(test *testing.T) {
+	root := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: func(logs.LogMessage) {},
+	})
+	hook := logstest.Attach(test, root)
+
+	root.WithFields(map[string]interface{}{
+		"tags":    []string{"a", "b"},
+		"request": map[string]interface{}{"method": "GET"},
+	}).Info("Tagged")
+
+	hook.AssertLogged(test, logstest.Field("tags", []string{"a", "b"}))
+	hook.AssertLogged(test, logstest.Field("request", map[string]interface{}{"method": "GET"}))
+	hook.AssertNotLogged(test, logstest.Field("tags", []string{"a"}))
+}
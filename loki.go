@@ -0,0 +1,137 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lokiPushPath is the path of Loki's push API
+const lokiPushPath = "/loki/api/v1/push"
+
+// LokiHandlerConfig configures a Grafana Loki handler
+type LokiHandlerConfig struct {
+	// HTTPHandlerConfig configures batching and delivery. URL is the base URL of
+	// Loki (for example http://loki:3100); the push API path is added if the URL
+	// has no path. Encoder produces each log line and defaults to JSONEncoder.
+	HTTPHandlerConfig
+	// TenantID, when set, is sent as the X-Scope-OrgID header for multi-tenant
+	// Loki deployments
+	TenantID string
+	// Labels are static labels added to every stream, such as app or env
+	Labels map[string]string
+	// LabelFields lists fields that are promoted to stream labels when present.
+	// Keep this to low cardinality fields.
+	LabelFields []string
+}
+
+// lokiStream is a stream in a Loki push request
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// NewLokiHandler returns an HTTPHandler that pushes entries to Loki's push API.
+// Each entry is labeled with its `logger` and `level`, the static Labels and any
+// LabelFields, and entries with the same labels are grouped in to one stream.
+func NewLokiHandler(config LokiHandlerConfig) (*HTTPHandler, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Loki URL: %s", err)
+	}
+	if len(strings.Trim(u.Path, "/")) == 0 {
+		u.Path = lokiPushPath
+		config.URL = u.String()
+	}
+	if len(config.TenantID) > 0 {
+		config.Headers = cloneHeader(config.Headers)
+		config.Headers.Set("X-Scope-OrgID", config.TenantID)
+	}
+	if nil == config.Encoder {
+		config.Encoder = JSONEncoder
+	}
+
+	h, err := NewHTTPHandler(config.HTTPHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+	h.body = func(batch []LogMessage) ([]byte, error) {
+		return lokiPushBody(batch, config)
+	}
+	return h, nil
+}
+
+// lokiPushBody groups a batch in to streams by label set
+func lokiPushBody(batch []LogMessage, config LokiHandlerConfig) ([]byte, error) {
+	streams := make(map[string]*lokiStream)
+	var order []string
+	for _, msg := range batch {
+		labels := make(map[string]string, len(config.Labels)+len(config.LabelFields)+2)
+		for k, v := range config.Labels {
+			labels[k] = v
+		}
+		for _, k := range config.LabelFields {
+			if v, ok := msg.Fields[k]; ok {
+				labels[k] = fmt.Sprint(v)
+			}
+		}
+		// Loki labels levels in lower case
+		labels["level"] = strings.ToLower(levelLabel(msg))
+		if len(msg.Logger) > 0 {
+			labels["logger"] = msg.Logger
+		}
+
+		line, err := config.Encoder(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		key := lokiLabelKey(labels)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			order = append(order, key)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(msg.Time.UnixNano(), 10),
+			string(line),
+		})
+	}
+
+	request := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range order {
+		request.Streams = append(request.Streams, streams[key])
+	}
+	return json.Marshal(request)
+}
+
+// lokiLabelKey renders a label set in Loki's selector syntax, which also serves
+// as a key for grouping entries in to streams
+func lokiLabelKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// cloneHeader copies an http.Header so config passed by the caller isn't modified
+func cloneHeader(h http.Header) http.Header {
+	clone := make(http.Header, len(h))
+	for k, v := range h {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
@@ -0,0 +1,102 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLokiHandler(test *testing.T) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	var streams []stream
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/push" || r.Header.Get("X-Scope-OrgID") != "team-a" {
+			test.Errorf("Unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		var body struct {
+			Streams []stream `json:"streams"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); nil != err {
+			test.Errorf("Unable to parse push request: %s", err)
+		}
+		streams = append(streams, body.Streams...)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewLokiHandler(logs.LokiHandlerConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{URL: server.URL},
+		TenantID:          "team-a",
+		Labels:            map[string]string{"app": "billing"},
+		LabelFields:       []string{"region"},
+	})
+	if nil != err {
+		test.Fatalf("Error creating LokiHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.SetField("region", "us-east-1")
+	logger.Info("First")
+	logger.Info("Second")
+	logger.Warn("Third")
+	handler.Close()
+
+	if len(streams) != 2 {
+		test.Fatalf("Expected entries grouped in to 2 streams. Found: %v", streams)
+	}
+	info := streams[0]
+	expectedLabels := map[string]string{"app": "billing", "region": "us-east-1", "level": "info", "logger": "main"}
+	for k, v := range expectedLabels {
+		if info.Stream[k] != v {
+			test.Errorf("Expected label %s=%s. Found: %v", k, v, info.Stream)
+		}
+	}
+	if len(info.Values) != 2 {
+		test.Errorf("Expected 2 entries in the info stream. Found: %v", info.Values)
+	}
+}
+
+func TestLokiHandlerLevelLabel(test *testing.T) {
+	defer logs.LogLevels.SetLabel(logs.Warn, "WARN")
+	if err := logs.LogLevels.SetLabel(logs.Warn, "CAUTION"); err != nil {
+		test.Fatal(err)
+	}
+
+	var level string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+			} `json:"streams"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Streams) > 0 {
+			level = body.Streams[0].Stream["level"]
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewLokiHandler(logs.LokiHandlerConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{URL: server.URL},
+	})
+	if nil != err {
+		test.Fatalf("Error creating LokiHandler: %s", err)
+	}
+	logger := logs.New(&logs.RootLogConfig{LogHandler: handler.LogHandler})
+	logger.Warn("Disk nearly full")
+	handler.Close()
+
+	if level != "caution" {
+		test.Errorf("Expected the configured label in lower case. Found: %q", level)
+	}
+}
@@ -0,0 +1,147 @@
+package gologsgo
+
+import "io"
+
+// MergeConfigs layers configs from several sources, such as a file, environment
+// variables and command line flags, returning a new RootLogConfig. Each override
+// is applied in turn, replacing only the settings it sets. The loggers trees are
+// merged logger by logger, so an override can change the level of one logger
+// while keeping the rest. The handler settings (Handler, Handlers, LogHandler and
+// LogHandlerE) are taken together from the last config to set one of them.
+// LevelLabels are merged by level, Profiles by name, and Closers and Hooks are
+// combined in order.
+// nil configs are skipped.
+func MergeConfigs(base *RootLogConfig, overrides ...*RootLogConfig) *RootLogConfig {
+	merged := &RootLogConfig{}
+	for _, config := range append([]*RootLogConfig{base}, overrides...) {
+		if nil != config {
+			merged = mergeConfig(merged, config)
+		}
+	}
+	return merged
+}
+
+// mergeConfig returns a copy of base with the settings of override applied
+func mergeConfig(base *RootLogConfig, override *RootLogConfig) *RootLogConfig {
+	merged := *base
+	merged.Loggers = mergeLoggers(base.Loggers, override.Loggers)
+	if override.Level != NotSet {
+		merged.Level = override.Level
+	}
+	if len(override.Label) > 0 {
+		merged.Label = override.Label
+	}
+	if nil != override.Handler || len(override.Handlers) > 0 || nil != override.LogHandler || nil != override.LogHandlerE {
+		merged.Handler = override.Handler
+		merged.Handlers = override.Handlers
+		merged.LogHandler = override.LogHandler
+		merged.LogHandlerE = override.LogHandlerE
+	}
+	if nil != override.ErrorCallback {
+		merged.ErrorCallback = override.ErrorCallback
+	}
+	merged.Closers = append(append([]io.Closer{}, base.Closers...), override.Closers...)
+	if len(override.TimeFormat) > 0 {
+		merged.TimeFormat = override.TimeFormat
+	}
+	if len(override.Timezone) > 0 {
+		merged.Timezone = override.Timezone
+	}
+	if nil != override.Colors {
+		merged.Colors = override.Colors
+	}
+	if len(base.LevelLabels) > 0 || len(override.LevelLabels) > 0 {
+		merged.LevelLabels = make(map[string]string)
+		for current, label := range base.LevelLabels {
+			merged.LevelLabels[current] = label
+		}
+		for current, label := range override.LevelLabels {
+			merged.LevelLabels[current] = label
+		}
+	}
+	if nil != override.LabelFormat {
+		merged.LabelFormat = override.LabelFormat
+	}
+	if override.CaptureStacks != NotSet {
+		merged.CaptureStacks = override.CaptureStacks
+	}
+	if nil != override.Symbols {
+		merged.Symbols = override.Symbols
+	}
+	if len(override.Preset) > 0 {
+		merged.Preset = override.Preset
+	}
+	merged.Hooks = append(append([]Hook{}, base.Hooks...), override.Hooks...)
+	if nil != override.Redaction {
+		merged.Redaction = override.Redaction
+	}
+	if nil != override.Sample {
+		merged.Sample = override.Sample
+	}
+	if override.RateLimitPerSec > 0 {
+		merged.RateLimitPerSec = override.RateLimitPerSec
+	}
+	if len(base.Profiles) > 0 || len(override.Profiles) > 0 {
+		merged.Profiles = make(map[string]*RootLogConfig)
+		for name, profile := range base.Profiles {
+			merged.Profiles[name] = profile
+		}
+		for name, profile := range override.Profiles {
+			merged.Profiles[name] = profile
+		}
+	}
+	if len(override.Profile) > 0 {
+		merged.Profile = override.Profile
+	}
+	if len(override.Rules) > 0 {
+		// Rules of overrides are evaluated first, so they take precedence
+		merged.Rules = append(append([]LevelRule{}, override.Rules...), base.Rules...)
+	}
+	if nil != override.Updates {
+		merged.Updates = override.Updates
+	}
+	return &merged
+}
+
+// mergeLoggers merges the child logger configs of override into copies of those
+// of base
+func mergeLoggers(base map[string]*LogConfig, override map[string]*LogConfig) map[string]*LogConfig {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]*LogConfig, len(base)+len(override))
+	for name, cfg := range base {
+		if nil != cfg {
+			copied := *cfg
+			copied.Loggers = mergeLoggers(cfg.Loggers, nil)
+			merged[name] = &copied
+		}
+	}
+	for name, cfg := range override {
+		if nil == cfg {
+			continue
+		}
+		existing, ok := merged[name]
+		if !ok {
+			copied := *cfg
+			copied.Loggers = mergeLoggers(nil, cfg.Loggers)
+			merged[name] = &copied
+			continue
+		}
+		existing.Loggers = mergeLoggers(existing.Loggers, cfg.Loggers)
+		if cfg.Level != NotSet {
+			existing.Level = cfg.Level
+		}
+		if nil != cfg.Handler || nil != cfg.LogHandler {
+			existing.Handler = cfg.Handler
+			existing.LogHandler = cfg.LogHandler
+		}
+		if nil != cfg.Sample {
+			existing.Sample = cfg.Sample
+		}
+		if cfg.RateLimitPerSec > 0 {
+			existing.RateLimitPerSec = cfg.RateLimitPerSec
+		}
+	}
+	return merged
+}
@@ -0,0 +1,60 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestMergeConfigs(test *testing.T) {
+	file, err := logs.JsonConfig([]byte(`{
+		"level": "INFO",
+		"label": "app",
+		"handler": "json-stderr",
+		"loggers": {
+			"db": {"level": "WARN", "loggers": {"cache": {"level": "ERROR"}}},
+			"http": {"level": "INFO"}
+		}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	env, err := logs.JsonConfig([]byte(`{
+		"loggers": {"db": {"loggers": {"cache": {"level": "DEBUG"}}}},
+		"rules": [{"match": "sql$", "level": "DEBUG"}]
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	var entries []logs.LogMessage
+	flags := &logs.RootLogConfig{
+		Level:      logs.Trace,
+		LogHandler: func(msg logs.LogMessage) { entries = append(entries, msg) },
+	}
+
+	config := logs.MergeConfigs(file, env, nil, flags)
+	if config.Level != logs.Trace || config.Label != "app" {
+		test.Errorf("Unexpected root settings: %+v", config)
+	}
+	if len(config.Rules) != 1 || config.Rules[0].Match != "sql$" {
+		test.Errorf("Expected the rules to be merged. Found: %+v", config.Rules)
+	}
+	db := config.Loggers["db"]
+	if nil == db || db.Level != logs.Warn || db.Loggers["cache"].Level != logs.Debug {
+		test.Errorf("Expected the db loggers to be merged. Found: %+v", db)
+	}
+	if nil == config.Loggers["http"] || config.Loggers["http"].Level != logs.Info {
+		test.Errorf("Expected the http logger to be kept. Found: %+v", config.Loggers["http"])
+	}
+	if nil != config.Handler {
+		test.Errorf("Expected the handler to be replaced. Found: %+v", config.Handler)
+	}
+	if file.Level != logs.Info || file.Loggers["db"].Loggers["cache"].Level != logs.Error {
+		test.Error("Expected the merged configs to be unchanged")
+	}
+
+	logs.New(config).ChildLogger("db").ChildLogger("cache").Debug("Hit")
+	if len(entries) != 1 || entries[0].Logger != "app.db.cache" {
+		test.Errorf("Expected the flags' handler to be used. Found: %+v", entries)
+	}
+}
@@ -0,0 +1,156 @@
+package gologsgo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header HTTPMiddleware() reads the ID of a request from,
+// and returns it in
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// contextKey is the type of the keys of the values this package stores in a
+// context.Context
+type contextKey int
+
+// loggerKey is the key of the Logger stored by NewContext()
+const loggerKey contextKey = 0
+
+// NewContext returns a copy of ctx that carries logger. See FromContext().
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the Logger carried by ctx, such as the request logger of
+// HTTPMiddleware(), or nil if there is none
+func FromContext(ctx context.Context) *Logger {
+	logger, _ := ctx.Value(loggerKey).(*Logger)
+	return logger
+}
+
+// HTTPMiddleware returns net/http middleware that logs an access entry for each
+// request with logger, once it has been handled:
+//
+//	http.ListenAndServe(":8080", logs.HTTPMiddleware(logger.ChildLogger("http"))(mux))
+//
+// Each request gets a request logger, from WithFields(), with the "request_id",
+// "method", "path" and "remote_addr" fields, which handlers get from the request's
+// context with FromContext(), so their entries can be correlated:
+//
+//	logs.FromContext(r.Context()).Warn("Cache miss for %s", key)
+//
+// The access entry is logged by the request logger, with the "status", "bytes"
+// and "latency_ms" fields added. It is logged at the INFO level, or ERROR for 5xx
+// statuses. The request ID is taken from the X-Request-ID header of the request,
+// when it is set, or generated with NewID(), and is returned in the X-Request-ID
+// header of the response.
+func HTTPMiddleware(logger *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = NewID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			reqLogger := logger.WithFields(map[string]interface{}{
+				"request_id":  id,
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"remote_addr": r.RemoteAddr,
+			})
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r.WithContext(NewContext(r.Context(), reqLogger)))
+
+			status := sw.status
+			if status == 0 {
+				// Nothing was written, which net/http sends as 200 OK
+				status = http.StatusOK
+			}
+			latency := time.Since(start)
+			access := reqLogger.WithFields(map[string]interface{}{
+				"status":     status,
+				"bytes":      sw.bytes,
+				"latency_ms": float64(latency) / float64(time.Millisecond),
+			})
+			if status >= 500 {
+				access.Error("%s %s %d in %s", r.Method, r.URL.Path, status, latency)
+			} else {
+				access.Info("%s %s %d in %s", r.Method, r.URL.Path, status, latency)
+			}
+		})
+	}
+}
+
+// validRequestID reports whether a request ID from a client is short and
+// printable, so it can't forge entries
+func validRequestID(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// statusWriter records the status and size of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush flushes the response, if the underlying ResponseWriter supports it
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection, if the underlying ResponseWriter supports
+// it, such as for WebSockets
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("The ResponseWriter does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,97 @@
+package gologsgo_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestHTTPMiddleware(test *testing.T) {
+	var mu sync.Mutex
+	var messages []logs.LogMessage
+	logger := logs.New(&logs.RootLogConfig{
+		Label: "app",
+		LogHandler: func(msg logs.LogMessage) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, msg)
+		},
+	})
+	handler := logs.HTTPMiddleware(logger.ChildLogger("http"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logs.FromContext(r.Context()).Info("Handling")
+		if r.URL.Path == "/fail" {
+			http.Error(w, "broken", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, "hello")
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/hello", nil)
+	req.Header.Set(logs.RequestIDHeader, "abc-123")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		test.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get(logs.RequestIDHeader) != "abc-123" {
+		test.Errorf("Expected the request ID to be returned. Found: %q", resp.Header.Get(logs.RequestIDHeader))
+	}
+
+	resp, err = http.Post(server.URL+"/fail", "text/plain", nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+	resp.Body.Close()
+	generated := resp.Header.Get(logs.RequestIDHeader)
+	if len(generated) == 0 {
+		test.Error("Expected a request ID to be generated")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) != 4 {
+		test.Fatalf("Expected 4 messages. Found: %v", messages)
+	}
+	handling, access := messages[0], messages[1]
+	if handling.Logger != "app.http" || handling.Fields["request_id"] != "abc-123" || handling.Fields["path"] != "/hello" {
+		test.Errorf("Expected the handler to log with the request logger. Found: %v", handling)
+	}
+	f := access.Fields
+	if access.Level != logs.Info || !strings.HasPrefix(access.Message, "GET /hello 200 in ") ||
+		f["request_id"] != "abc-123" || f["method"] != "GET" || f["status"] != 200 || f["bytes"] != int64(5) ||
+		!strings.HasPrefix(f["remote_addr"].(string), "127.0.0.1:") {
+		test.Errorf("Unexpected access entry: %v", access)
+	}
+	if _, ok := f["latency_ms"].(float64); !ok {
+		test.Errorf("Expected the latency. Found: %v", f)
+	}
+	failed := messages[3]
+	if failed.Level != logs.Error || failed.Fields["status"] != 500 || failed.Fields["request_id"] != generated {
+		test.Errorf("Expected the failed request at ERROR. Found: %v", failed)
+	}
+}
+
+func TestHTTPMiddlewareRejectsRequestIDs(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	handler := logs.HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, id := range []string{"two words", "line\nbreak", strings.Repeat("a", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logs.RequestIDHeader, id)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if returned := w.Header().Get(logs.RequestIDHeader); returned == id || len(returned) == 0 {
+			test.Errorf("Expected %q to be replaced. Found: %q", id, returned)
+		}
+	}
+	if logs.FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()) != nil {
+		test.Error("Expected no logger in a plain context")
+	}
+}
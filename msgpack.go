@@ -0,0 +1,260 @@
+package gologsgo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// This file contains the small subset of MessagePack
+// (https://github.com/msgpack/msgpack/blob/master/spec.md) needed by the handlers
+// in this package. It is not a general purpose implementation.
+
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n < 1<<8:
+		b = append(b, 0xd9, byte(n))
+	case n < 1<<16:
+		b = append(b, 0xda, byte(n>>8), byte(n))
+	default:
+		b = append(b, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n < 1<<16:
+		return append(b, 0xdc, byte(n>>8), byte(n))
+	default:
+		return append(b, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func appendMsgpackMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n < 1<<16:
+		return append(b, 0xde, byte(n>>8), byte(n))
+	default:
+		return append(b, 0xdf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+// appendMsgpackValue appends a field value. Strings, booleans, numbers and nil
+// are encoded natively. Anything else is encoded as its fmt.Sprint string.
+func appendMsgpackValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case string:
+		return appendMsgpackString(b, v)
+	case int:
+		return appendMsgpackInt(b, int64(v))
+	case int8:
+		return appendMsgpackInt(b, int64(v))
+	case int16:
+		return appendMsgpackInt(b, int64(v))
+	case int32:
+		return appendMsgpackInt(b, int64(v))
+	case int64:
+		return appendMsgpackInt(b, v)
+	case uint8:
+		return appendMsgpackInt(b, int64(v))
+	case uint16:
+		return appendMsgpackInt(b, int64(v))
+	case uint32:
+		return appendMsgpackInt(b, int64(v))
+	case uint:
+		return appendMsgpackUint(b, uint64(v))
+	case uint64:
+		return appendMsgpackUint(b, v)
+	case uintptr:
+		return appendMsgpackUint(b, uint64(v))
+	case float32:
+		return appendMsgpackFloat(b, float64(v))
+	case float64:
+		return appendMsgpackFloat(b, v)
+	default:
+		return appendMsgpackString(b, fmt.Sprint(v))
+	}
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	if i >= 0 && i < 128 {
+		return append(b, byte(i))
+	}
+	if i < 0 && i >= -32 {
+		return append(b, byte(i))
+	}
+	b = append(b, 0xd3)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[len(b)-8:], uint64(i))
+	return b
+}
+
+// appendMsgpackUint appends values beyond the range of an int64 as a uint 64,
+// and others like appendMsgpackInt()
+func appendMsgpackUint(b []byte, u uint64) []byte {
+	if u <= math.MaxInt64 {
+		return appendMsgpackInt(b, int64(u))
+	}
+	b = append(b, 0xcf)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[len(b)-8:], u)
+	return b
+}
+
+func appendMsgpackFloat(b []byte, f float64) []byte {
+	b = append(b, 0xcb)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[len(b)-8:], math.Float64bits(f))
+	return b
+}
+
+// appendMsgpackEventTime appends t as the Fluentd EventTime extension type
+// (fixext 8, type 0: seconds and nanoseconds as big endian uint32s)
+func appendMsgpackEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint32(b[len(b)-8:], uint32(t.Unix()))
+	binary.BigEndian.PutUint32(b[len(b)-4:], uint32(t.Nanosecond()))
+	return b
+}
+
+// maxMsgpackReadString is the longest string read from a peer. The strings read,
+// such as Fluentd chunk ids, are short, so a longer one is a broken or hostile
+// peer, and isn't allocated.
+const maxMsgpackReadString = 64 << 10
+
+// readMsgpackStringMap reads a map of string keys to string values, such as a
+// Fluentd ack response
+func readMsgpackStringMap(r io.Reader) (map[string]string, error) {
+	header := make([]byte, 1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	var n int
+	switch {
+	case header[0]&0xf0 == 0x80:
+		n = int(header[0] & 0x0f)
+	case header[0] == 0xde:
+		size := make([]byte, 2)
+		if _, err := io.ReadFull(r, size); err != nil {
+			return nil, err
+		}
+		n = int(binary.BigEndian.Uint16(size))
+	default:
+		return nil, fmt.Errorf("Expected a MessagePack map. Found type 0x%x", header[0])
+	}
+
+	m := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		k, err := readMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := readMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func readMsgpackString(r io.Reader) (string, error) {
+	header := make([]byte, 1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", err
+	}
+
+	var n int
+	switch {
+	case header[0]&0xe0 == 0xa0:
+		n = int(header[0] & 0x1f)
+	case header[0] == 0xd9 || header[0] == 0xda || header[0] == 0xdb:
+		size := make([]byte, 1<<(header[0]-0xd9))
+		if _, err := io.ReadFull(r, size); err != nil {
+			return "", err
+		}
+		for _, c := range size {
+			n = n<<8 | int(c)
+		}
+	default:
+		return "", fmt.Errorf("Expected a MessagePack string. Found type 0x%x", header[0])
+	}
+	if n > maxMsgpackReadString {
+		return "", fmt.Errorf("MessagePack string of %d bytes exceeds the limit of %d", n, maxMsgpackReadString)
+	}
+
+	s := make([]byte, n)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// appendMsgpackTimestamp appends t as the MessagePack timestamp extension type
+// (timestamp 96: nanoseconds as a big endian uint32 and seconds as an int64)
+func appendMsgpackTimestamp(b []byte, t time.Time) []byte {
+	b = append(b, 0xc7, 12, 0xff)
+	b = append(b, make([]byte, 12)...)
+	binary.BigEndian.PutUint32(b[len(b)-12:], uint32(t.Nanosecond()))
+	binary.BigEndian.PutUint64(b[len(b)-8:], uint64(t.Unix()))
+	return b
+}
+
+func init() {
+	RegisterFormat("msgpack", FormatTraits{Binary: true})
+}
+
+// MsgpackEncoder encodes a LogMessage as a MessagePack map with the same keys as
+// JSONEncoder. The time is a MessagePack timestamp. It is more compact and
+// cheaper to produce than JSON, so suits high throughput network handlers. Use
+// it with LengthPrefixFraming, since the encoding may contain newlines.
+func MsgpackEncoder(msg LogMessage) ([]byte, error) {
+	n := 3
+	if len(msg.Logger) > 0 {
+		n++
+	}
+	if len(msg.Fields) > 0 {
+		n++
+	}
+
+	b := make([]byte, 0, 64+len(msg.Message))
+	b = appendMsgpackMapHeader(b, n)
+	b = appendMsgpackString(b, "time")
+	b = appendMsgpackTimestamp(b, msg.Time)
+	b = appendMsgpackString(b, "level")
+	b = appendMsgpackString(b, levelLabel(msg))
+	if len(msg.Logger) > 0 {
+		b = appendMsgpackString(b, "logger")
+		b = appendMsgpackString(b, msg.Logger)
+	}
+	b = appendMsgpackString(b, "message")
+	b = appendMsgpackString(b, msg.Message)
+	if len(msg.Fields) > 0 {
+		b = appendMsgpackString(b, "fields")
+		b = appendMsgpackMapHeader(b, len(msg.Fields))
+		for _, k := range fieldKeys(msg.Fields) {
+			b = appendMsgpackString(b, k)
+			b = appendMsgpackValue(b, msg.Fields[k])
+		}
+	}
+	return b, nil
+}
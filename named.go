@@ -0,0 +1,147 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NamedConfig builds the RootLogConfig of the application called name from, in
+// increasing order of precedence:
+//
+//   - defaults, which may be nil
+//   - /etc/<name>.json
+//   - ~/.<name>.json
+//   - ./<name>.json
+//   - environment variables starting with <NAME>_ (see EnvPrefixConfig())
+//   - --log-level command line flags
+//
+// Missing files are skipped. Objects, such as "loggers", are merged key by key;
+// other settings replace those of lower precedence. The --log-level flag takes a
+// level, which sets the root level, or <logger>=<level>, which sets the level of
+// a logger, such as --log-level=db.cache=DEBUG. It may be repeated. Other
+// command line arguments are ignored.
+func NamedConfig(name string, defaults *RootLogConfig) (*RootLogConfig, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("NamedConfig requires a name")
+	}
+
+	paths := []string{filepath.Join("/etc", name+".json")}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, "."+name+".json"))
+	}
+	paths = append(paths, name+".json")
+
+	cfg := make(map[string]interface{})
+	for _, path := range paths {
+		layer, err := configFileMap(path)
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(cfg, layer)
+	}
+	env, err := envPrefixMap(envName(name))
+	if err != nil {
+		return nil, err
+	}
+	mergeMaps(cfg, env)
+	flags, err := flagMap(os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+	mergeMaps(cfg, flags)
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	config, err := JsonConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	return MergeConfigs(defaults, config), nil
+}
+
+// envName converts an application name to an environment variable prefix, for
+// example "my-app" to "MY_APP"
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+}
+
+// configFileMap reads a JSON config file as an object, with the files it
+// includes merged under it. A missing file is empty.
+func configFileMap(path string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jsonConfigMap(path, data, nil)
+}
+
+// flagMap builds a config object from the --log-level flags in args
+func flagMap(args []string) (map[string]interface{}, error) {
+	cfg := make(map[string]interface{})
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		var value string
+		switch {
+		case arg == "--log-level" || arg == "-log-level":
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("The %s flag requires a level", arg)
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(arg, "--log-level="), strings.HasPrefix(arg, "-log-level="):
+			value = arg[strings.Index(arg, "=")+1:]
+		default:
+			continue
+		}
+
+		eq := strings.LastIndex(value, "=")
+		if eq < 0 {
+			cfg["level"] = value
+			continue
+		}
+		lvlCfg := cfg
+		for _, k := range strings.Split(value[:eq], ".") {
+			if len(k) == 0 {
+				return nil, fmt.Errorf("Invalid --log-level %q. Use <level> or <logger>=<level>", value)
+			}
+			lvlCfg = childMap(childMap(lvlCfg, "loggers"), k)
+		}
+		lvlCfg["level"] = value[eq+1:]
+	}
+	return cfg, nil
+}
+
+// mergeMaps merges the JSON object src into dst. Objects are merged key by key;
+// other values in src replace those in dst.
+func mergeMaps(dst map[string]interface{}, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, ok := v.(map[string]interface{})
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dstMap, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dstMap = make(map[string]interface{})
+			dst[k] = dstMap
+		}
+		mergeMaps(dstMap, srcMap)
+	}
+}
@@ -0,0 +1,99 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// inTempDirs runs f with the working and home directories set to new temporary
+// directories
+func inTempDirs(test *testing.T, f func(cwd string, home string)) {
+	cwd, err := ioutil.TempDir("", "named-cwd")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(cwd)
+	home, err := ioutil.TempDir("", "named-home")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		test.Fatal(err)
+	}
+	if err := os.Chdir(cwd); err != nil {
+		test.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+
+	f(cwd, home)
+}
+
+func TestNamedConfig(test *testing.T) {
+	inTempDirs(test, func(cwd string, home string) {
+		ioutil.WriteFile(filepath.Join(home, ".logtest.json"), []byte(`{
+			"level": "WARN",
+			"timeFormat": "15:04",
+			"loggers": {"db": {"level": "ERROR", "loggers": {"cache": {"level": "WARN"}}}}
+		}`), 0644)
+		ioutil.WriteFile(filepath.Join(cwd, "logtest.json"), []byte(`{
+			"loggers": {"db": {"level": "INFO"}}
+		}`), 0644)
+		os.Setenv("LOGTEST_LABEL", "env")
+		defer os.Unsetenv("LOGTEST_LABEL")
+		args := os.Args
+		os.Args = []string{"app", "serve", "--log-level", "debug", "--log-level=http=TRACE"}
+		defer func() { os.Args = args }()
+
+		config, err := logs.NamedConfig("logtest", &logs.RootLogConfig{
+			Label:    "defaults",
+			Timezone: "UTC",
+			Level:    logs.Error,
+		})
+		if err != nil {
+			test.Fatal(err)
+		}
+		if config.Level != logs.Debug {
+			test.Errorf("Expected the flag to set the DEBUG level. Found: %s", logs.LogLevels.Label(config.Level))
+		}
+		if config.Label != "env" {
+			test.Errorf("Expected the environment to set the label. Found: %q", config.Label)
+		}
+		if config.Timezone != "UTC" || config.TimeFormat != "15:04" {
+			test.Errorf("Expected the defaults and home file to be kept. Found: %q, %q", config.Timezone, config.TimeFormat)
+		}
+		db := config.Loggers["db"]
+		if nil == db || db.Level != logs.Info || nil == db.Loggers["cache"] || db.Loggers["cache"].Level != logs.Warn {
+			test.Errorf("Expected the db loggers to be merged. Found: %+v", db)
+		}
+		if nil == config.Loggers["http"] || config.Loggers["http"].Level != logs.Trace {
+			test.Errorf("Expected the flag to set the http level. Found: %+v", config.Loggers["http"])
+		}
+	})
+}
+
+func TestNamedConfigErrors(test *testing.T) {
+	inTempDirs(test, func(cwd string, home string) {
+		if _, err := logs.NamedConfig("", nil); err == nil {
+			test.Error("Expected an error for an empty name")
+		}
+
+		config, err := logs.NamedConfig("logtest", nil)
+		if err != nil || config.Level != logs.NotSet {
+			test.Errorf("Expected an empty config without any files. Found: %+v, %v", config, err)
+		}
+
+		ioutil.WriteFile(filepath.Join(cwd, "logtest.json"), []byte(`{"level": `), 0644)
+		if _, err := logs.NamedConfig("logtest", nil); err == nil {
+			test.Error("Expected an error for an invalid file")
+		}
+	})
+}
@@ -0,0 +1,361 @@
+package gologsgo
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NetworkHandlerConfig configures a NetworkHandler
+type NetworkHandlerConfig struct {
+	// Network is one of "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix"
+	// or "unixgram"
+	Network string
+	// Address is the host:port of the remote collector, or the socket path for
+	// unix networks
+	Address string
+	// Encoder serializes each LogMessage. Defaults to JSONEncoder.
+	Encoder Encoder
+	// Framing delimits entries on the wire. Defaults to NewlineFraming.
+	Framing Framing
+	// TLSConfig, when set, wraps TCP connections in TLS. Set Certificates on it
+	// for client authentication.
+	TLSConfig *tls.Config
+	// QueueSize is the number of entries held in memory while the collector
+	// is unreachable. When full, the oldest entries are spooled, or dropped
+	// without a Spool. Defaults to 1000.
+	QueueSize int
+	// DialTimeout defaults to 5 seconds
+	DialTimeout time.Duration
+	// WriteTimeout defaults to 5 seconds
+	WriteTimeout time.Duration
+	// MinBackoff is the delay before the first reconnection attempt. It doubles
+	// on each failure up to MaxBackoff. Defaults to 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// DrainTimeout is how long Close() waits for queued entries to be delivered.
+	// Defaults to 5 seconds.
+	DrainTimeout time.Duration
+	// Spool, when set, receives entries that would otherwise be dropped because
+	// the queue is full or they could not be delivered before DrainTimeout. They
+	// are replayed once entries are delivered again.
+	Spool *Spool
+}
+
+// NetworkHandler streams log entries to a remote collector over TCP, UDP or a unix
+// domain socket. Entries are queued and written by a background goroutine, so a
+// slow or unreachable collector never blocks the caller. Lost connections are
+// re-established with exponential backoff.
+type NetworkHandler struct {
+	// dropped is first to guarantee 64-bit alignment for atomic operations
+	dropped uint64
+	// spooled is 1 while the Spool may hold entries to replay. It is read and
+	// written atomically.
+	spooled int32
+	config  NetworkHandlerConfig
+	queue   chan []byte
+	stop    chan struct{}
+	done    chan struct{}
+	mu      sync.Mutex
+	closed  bool
+	// health is failing while the background goroutine can't deliver entries
+	health health
+	// confirm, when set, is called after each entry is written to wait for the
+	// collector to acknowledge it
+	confirm func(conn net.Conn, data []byte) error
+	// handshake, when set, is called on each new connection before any entries
+	// are written to it
+	handshake func(conn net.Conn) error
+}
+
+// NewNetworkHandler validates the config and starts a NetworkHandler. The connection
+// is made lazily, so an unreachable collector does not prevent startup.
+func NewNetworkHandler(config NetworkHandlerConfig) (*NetworkHandler, error) {
+	switch config.Network {
+	case "tcp", "tcp4", "tcp6":
+	case "udp", "udp4", "udp6", "unix", "unixgram":
+		if nil != config.TLSConfig {
+			return nil, fmt.Errorf("TLS is not supported for network %s", config.Network)
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported network for NetworkHandler: %q", config.Network)
+	}
+
+	if len(config.Address) < 1 {
+		return nil, fmt.Errorf("NetworkHandler requires an Address")
+	}
+
+	if nil == config.Encoder {
+		config.Encoder = JSONEncoder
+	}
+	if nil == config.Framing {
+		config.Framing = NewlineFraming
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 1000
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.DrainTimeout <= 0 {
+		config.DrainTimeout = 5 * time.Second
+	}
+
+	h := &NetworkHandler{
+		config: config,
+		queue:  make(chan []byte, config.QueueSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if nil != config.Spool {
+		// Entries spooled before a restart are replayed once connected
+		h.spooled = 1
+	}
+	go h.run()
+
+	return h, nil
+}
+
+// LogHandler queues a LogMessage for delivery. It is a LogHandler.
+func (h *NetworkHandler) LogHandler(msg LogMessage) {
+	data, err := h.config.Encoder(msg)
+	if err != nil || nil != h.enqueue(h.config.Framing(data)) {
+		drop(&h.dropped, 1)
+	}
+}
+
+// LogHandlerE queues a LogMessage for delivery, like LogHandler, but returns an
+// error instead while the collector can't be reached, from the first failed
+// attempt until an entry is delivered again. Use it with Failover(). It is a
+// LogHandlerE.
+func (h *NetworkHandler) LogHandlerE(msg LogMessage) error {
+	if err := h.health.check(0); err != nil {
+		return err
+	}
+	data, err := h.config.Encoder(msg)
+	if err != nil {
+		return fmt.Errorf("Unable to encode entry for %s: %s", h.config.Address, err)
+	}
+	return h.enqueue(h.config.Framing(data))
+}
+
+// enqueue adds an already framed entry to the queue, spooling the oldest entry
+// if the queue is full. An error is returned after Close().
+func (h *NetworkHandler) enqueue(data []byte) error {
+	var evicted [][]byte
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return fmt.Errorf("NetworkHandler for %s is closed", h.config.Address)
+	}
+	for queued := false; !queued; {
+		select {
+		case h.queue <- data:
+			queued = true
+		default:
+			select {
+			case oldest := <-h.queue:
+				evicted = append(evicted, oldest)
+			default:
+			}
+		}
+	}
+	h.mu.Unlock()
+
+	// The spool is written outside the lock, so other callers aren't held up by
+	// the disk
+	if len(evicted) > 0 {
+		h.spool(evicted...)
+	}
+	return nil
+}
+
+// Dropped returns the number of entries that were discarded because the queue
+// was full (and they could not be spooled), they could not be encoded, or they
+// were logged after Close()
+func (h *NetworkHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Close stops accepting entries and waits up to DrainTimeout for queued entries
+// to be delivered. An error is returned if any entries had to be abandoned.
+func (h *NetworkHandler) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.queue)
+	h.mu.Unlock()
+
+	before := h.Dropped()
+	select {
+	case <-h.done:
+	case <-time.After(h.config.DrainTimeout):
+		close(h.stop)
+		<-h.done
+	}
+
+	if abandoned := h.Dropped() - before; abandoned > 0 {
+		return fmt.Errorf("%d log entries could not be delivered to %s", abandoned, h.config.Address)
+	}
+	return nil
+}
+
+// run is the background goroutine that delivers queued entries
+func (h *NetworkHandler) run() {
+	defer close(h.done)
+
+	var conn net.Conn
+	defer func() {
+		if nil != conn {
+			conn.Close()
+		}
+	}()
+
+	backoff := h.config.MinBackoff
+	for data := range h.queue {
+		for {
+			if nil == conn {
+				c, err := h.dial()
+				if err != nil {
+					h.health.set(fmt.Errorf("Unable to connect to %s: %s", h.config.Address, err))
+					if !h.wait(backoff) {
+						h.abandon(data)
+						return
+					}
+					backoff *= 2
+					if backoff > h.config.MaxBackoff {
+						backoff = h.config.MaxBackoff
+					}
+					continue
+				}
+				if err := h.replay(c); err != nil {
+					h.health.set(fmt.Errorf("Unable to write to %s: %s", h.config.Address, err))
+					c.Close()
+					if !h.wait(backoff) {
+						h.abandon(data)
+						return
+					}
+					continue
+				}
+				conn = c
+				backoff = h.config.MinBackoff
+			}
+
+			if err := h.write(conn, data); err != nil {
+				// Reconnect and try this entry again
+				h.health.set(fmt.Errorf("Unable to write to %s: %s", h.config.Address, err))
+				conn.Close()
+				conn = nil
+				if !h.wait(backoff) {
+					h.abandon(data)
+					return
+				}
+				continue
+			}
+			h.health.set(nil)
+			// Entries spooled while connected, because the queue overflowed,
+			// are replayed as soon as the collector keeps up. What isn't
+			// delivered stays spooled for the next connection.
+			if err := h.replay(conn); err != nil {
+				conn.Close()
+				conn = nil
+			}
+			break
+		}
+	}
+}
+
+// write sends one entry, waiting for it to be acknowledged if required
+func (h *NetworkHandler) write(conn net.Conn, data []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(h.config.WriteTimeout))
+	_, err := conn.Write(data)
+	if nil == err && nil != h.confirm {
+		err = h.confirm(conn, data)
+	}
+	return err
+}
+
+// replay sends spooled entries over a connection, when entries have been
+// spooled since the last replay that delivered everything
+func (h *NetworkHandler) replay(conn net.Conn) error {
+	if nil == h.config.Spool || !atomic.CompareAndSwapInt32(&h.spooled, 1, 0) {
+		return nil
+	}
+	_, err := h.config.Spool.Replay(func(data []byte) error {
+		return h.write(conn, data)
+	})
+	if err != nil {
+		atomic.StoreInt32(&h.spooled, 1)
+	}
+	return err
+}
+
+// spool writes an entry that can't be delivered to the Spool, counting it as
+// dropped if there is no Spool or it can't be written
+func (h *NetworkHandler) spool(entries ...[]byte) {
+	if nil != h.config.Spool && nil == h.config.Spool.Write(entries...) {
+		atomic.StoreInt32(&h.spooled, 1)
+		return
+	}
+	drop(&h.dropped, uint64(len(entries)))
+}
+
+func (h *NetworkHandler) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: h.config.DialTimeout}
+	var conn net.Conn
+	var err error
+	if nil != h.config.TLSConfig {
+		conn, err = tls.DialWithDialer(dialer, h.config.Network, h.config.Address, h.config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial(h.config.Network, h.config.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if nil != h.handshake {
+		conn.SetDeadline(time.Now().Add(h.config.WriteTimeout))
+		if err := h.handshake(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn.SetDeadline(time.Time{})
+	}
+	return conn, nil
+}
+
+// wait sleeps for the given duration, returning false if the handler was told
+// to stop in the meantime
+func (h *NetworkHandler) wait(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-h.stop:
+		return false
+	}
+}
+
+// abandon spools or counts as dropped the entry being delivered and everything
+// left in the (closed) queue
+func (h *NetworkHandler) abandon(data []byte) {
+	entries := [][]byte{data}
+	for data := range h.queue {
+		entries = append(entries, data)
+	}
+	h.spool(entries...)
+}
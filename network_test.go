@@ -0,0 +1,80 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestNetworkHandlerTCP(test *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		test.Fatalf("Unable to listen: %s", err)
+	}
+	defer listener.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if nil != err {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	handler, err := logs.NewNetworkHandler(logs.NetworkHandlerConfig{
+		Network: "tcp",
+		Address: listener.Addr().String(),
+	})
+	if nil != err {
+		test.Fatalf("Error creating NetworkHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "net",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Info("An info log message")
+	logger.ChildLogger("child").Warn("A warn log message")
+
+	if err := handler.Close(); nil != err {
+		test.Errorf("Error closing NetworkHandler: %s", err)
+	}
+
+	expected := []struct{ level, logger, message string }{
+		{"INFO", "net", "An info log message"},
+		{"WARN", "net.child", "A warn log message"},
+	}
+	for _, e := range expected {
+		line, ok := <-lines
+		if !ok {
+			test.Fatalf("Expected a line for %q", e.message)
+		}
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(line), &entry); nil != err {
+			test.Fatalf("Unable to parse %q as JSON: %s", line, err)
+		}
+		if entry["level"] != e.level || entry["logger"] != e.logger || entry["message"] != e.message {
+			test.Errorf("Unexpected entry: %s", line)
+		}
+	}
+
+	if handler.Dropped() != 0 {
+		test.Errorf("Expected no dropped entries. Found: %d", handler.Dropped())
+	}
+}
+
+func TestNetworkHandlerConfigValidation(test *testing.T) {
+	_, err := logs.NewNetworkHandler(logs.NetworkHandlerConfig{Network: "carrier-pigeon", Address: "localhost:1"})
+	if nil == err {
+		test.Error("Expected an error for an unsupported network")
+	}
+}
@@ -0,0 +1,31 @@
+// Package logsotel connects go-logs-go to OpenTelemetry tracing. It lives in its
+// own module so that users who don't trace don't inherit OpenTelemetry as a
+// dependency.
+package logsotel
+
+import (
+	"context"
+
+	logs "github.com/big-squid/go-logs-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans started by this package
+const instrumentationName = "github.com/big-squid/go-logs-go"
+
+// SpanFunc returns a logs.SpanFunc that starts spans with the given Tracer
+func SpanFunc(tracer trace.Tracer) logs.SpanFunc {
+	return func(ctx context.Context, name string) (context.Context, func()) {
+		ctx, span := tracer.Start(ctx, name)
+		return ctx, func() {
+			span.End()
+		}
+	}
+}
+
+// Install registers a SpanFunc using a Tracer from the global TracerProvider, so
+// Logger.StartSpan() starts OpenTelemetry spans.
+func Install() {
+	logs.SetSpanFunc(SpanFunc(otel.Tracer(instrumentationName)))
+}
@@ -0,0 +1,43 @@
+package logsotel_test
+
+import (
+	"context"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+	logsotel "github.com/big-squid/go-logs-go/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracer wraps the noop tracer to record the names of started spans
+type recordingTracer struct {
+	embedded.Tracer
+	started []string
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.started = append(t.started, name)
+	return noop.NewTracerProvider().Tracer("test").Start(ctx, name, opts...)
+}
+
+func TestSpanFunc(test *testing.T) {
+	tracer := &recordingTracer{}
+	logs.SetSpanFunc(logsotel.SpanFunc(tracer))
+	defer logs.SetSpanFunc(nil)
+
+	root := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: func(logs.LogMessage) {},
+	})
+	_, spanLogger, end := root.StartSpan(context.Background(), "query")
+	end()
+
+	if len(tracer.started) != 1 || tracer.started[0] != "query" {
+		test.Errorf("Expected a span named query to be started. Found: %v", tracer.started)
+	}
+	if spanLogger.Label() != "main.query" {
+		test.Errorf("Expected span logger label to be main.query. Found: %s", spanLogger.Label())
+	}
+}
@@ -0,0 +1,164 @@
+package gologsgo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// OTLPHandlerConfig configures an OpenTelemetry (OTLP) logs handler
+type OTLPHandlerConfig struct {
+	// HTTPHandlerConfig configures batching and delivery. URL defaults to a local
+	// collector, "http://localhost:4318/v1/logs". Encoder is ignored.
+	HTTPHandlerConfig
+	// ServiceName is sent as the service.name resource attribute
+	ServiceName string
+	// Resource holds static attributes describing the source of the entries,
+	// such as "deployment.environment" or "host.name"
+	Resource map[string]interface{}
+	// ScopeName is the instrumentation scope of the entries. Defaults to the
+	// import path of this package.
+	ScopeName string
+}
+
+// otlpSeverities maps log levels to OpenTelemetry severity numbers
+var otlpSeverities = map[LogLevel]int{
+	Trace: 1,
+	Debug: 5,
+	Info:  9,
+	Warn:  13,
+	Error: 17,
+}
+
+// NewOTLPHandler returns an HTTPHandler that exports entries as OpenTelemetry
+// LogRecords to a collector with OTLP/HTTP, using the JSON encoding. Levels are
+// mapped to severity numbers, fields become attributes, and entries with
+// "trace_id" and "span_id" fields (hex encoded) are correlated with traces.
+func NewOTLPHandler(config OTLPHandlerConfig) (*HTTPHandler, error) {
+	if len(config.URL) == 0 {
+		config.URL = "http://localhost:4318/v1/logs"
+	}
+	if len(config.ScopeName) == 0 {
+		config.ScopeName = "github.com/big-squid/go-logs-go"
+	}
+
+	resource := make(map[string]interface{}, len(config.Resource)+1)
+	for k, v := range config.Resource {
+		resource[k] = v
+	}
+	if len(config.ServiceName) > 0 {
+		resource["service.name"] = config.ServiceName
+	}
+	resourceAttributes := otlpAttributes(resource, nil)
+
+	h, err := NewHTTPHandler(config.HTTPHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+	h.body = func(batch []LogMessage) ([]byte, error) {
+		return otlpExportBody(batch, resourceAttributes, config.ScopeName)
+	}
+	return h, nil
+}
+
+// otlpExportBody builds an ExportLogsServiceRequest for a batch
+func otlpExportBody(batch []LogMessage, resource []map[string]interface{}, scope string) ([]byte, error) {
+	records := make([]map[string]interface{}, len(batch))
+	for i, msg := range batch {
+		record := map[string]interface{}{
+			"timeUnixNano":         strconv.FormatInt(msg.Time.UnixNano(), 10),
+			"observedTimeUnixNano": strconv.FormatInt(msg.Time.UnixNano(), 10),
+			"severityText":         msg.LevelLabel,
+			"body":                 map[string]interface{}{"stringValue": msg.Message},
+		}
+		if severity, ok := otlpSeverities[msg.Level]; ok {
+			record["severityNumber"] = severity
+		}
+
+		correlation := map[string]bool{}
+		for field, key := range map[string]string{"trace_id": "traceId", "span_id": "spanId"} {
+			if v, ok := msg.Fields[field].(string); ok {
+				if _, err := hex.DecodeString(v); nil == err {
+					record[key] = v
+					correlation[field] = true
+				}
+			}
+		}
+
+		attributes := map[string]interface{}{}
+		if len(msg.Logger) > 0 {
+			attributes["logger.name"] = msg.Logger
+		}
+		for k, v := range msg.Fields {
+			attributes[k] = v
+		}
+		if len(attributes) > 0 {
+			record["attributes"] = otlpAttributes(attributes, correlation)
+		}
+		records[i] = record
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"resourceLogs": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{"attributes": resource},
+				"scopeLogs": []interface{}{
+					map[string]interface{}{
+						"scope":      map[string]interface{}{"name": scope},
+						"logRecords": records,
+					},
+				},
+			},
+		},
+	})
+}
+
+// otlpAttributes converts a map to OTLP KeyValues, sorted by key, leaving out
+// the keys in skip
+func otlpAttributes(values map[string]interface{}, skip map[string]bool) []map[string]interface{} {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if !skip[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	attributes := make([]map[string]interface{}, len(keys))
+	for i, k := range keys {
+		attributes[i] = map[string]interface{}{
+			"key":   k,
+			"value": otlpValue(values[k]),
+		}
+	}
+	return attributes
+}
+
+// otlpValue converts a value to an OTLP AnyValue
+func otlpValue(v interface{}) map[string]interface{} {
+	switch value := v.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": value}
+	case bool:
+		return map[string]interface{}{"boolValue": value}
+	case int:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int32:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(value, 10)}
+	case uint:
+		return map[string]interface{}{"intValue": strconv.FormatUint(uint64(value), 10)}
+	case uint32:
+		return map[string]interface{}{"intValue": strconv.FormatUint(uint64(value), 10)}
+	case uint64:
+		return map[string]interface{}{"intValue": strconv.FormatUint(value, 10)}
+	case float32:
+		return map[string]interface{}{"doubleValue": float64(value)}
+	case float64:
+		return map[string]interface{}{"doubleValue": value}
+	}
+	return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+}
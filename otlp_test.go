@@ -0,0 +1,91 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestOTLPHandler(test *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" {
+			test.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewOTLPHandler(logs.OTLPHandlerConfig{
+		HTTPHandlerConfig: logs.HTTPHandlerConfig{URL: server.URL + "/v1/logs"},
+		ServiceName:       "billing",
+		Resource:          map[string]interface{}{"deployment.environment": "prod"},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	handler.LogHandler(logs.LogMessage{
+		Level:      logs.Warn,
+		LevelLabel: "WARN",
+		Logger:     "main.db",
+		Message:    "Slow query",
+		Time:       time.Unix(1, 500),
+		Fields: map[string]interface{}{
+			"trace_id": "0af7651916cd43dd8448eb211c80319c",
+			"span_id":  "b7ad6b7169203331",
+			"rows":     3,
+		},
+	})
+	handler.Close()
+
+	var request struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string                 `json:"key"`
+					Value map[string]interface{} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []struct {
+					TimeUnixNano   string `json:"timeUnixNano"`
+					SeverityNumber int    `json:"severityNumber"`
+					SeverityText   string `json:"severityText"`
+					Body           struct {
+						StringValue string `json:"stringValue"`
+					} `json:"body"`
+					TraceID    string `json:"traceId"`
+					SpanID     string `json:"spanId"`
+					Attributes []struct {
+						Key   string                 `json:"key"`
+						Value map[string]interface{} `json:"value"`
+					} `json:"attributes"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	body := <-bodies
+	if err := json.Unmarshal(body, &request); err != nil {
+		test.Fatalf("Unable to parse %s: %s", body, err)
+	}
+
+	resource := request.ResourceLogs[0].Resource.Attributes
+	if len(resource) != 2 || resource[1].Key != "service.name" || resource[1].Value["stringValue"] != "billing" {
+		test.Errorf("Unexpected resource attributes: %+v", resource)
+	}
+	record := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if record.TimeUnixNano != "1000000500" || record.SeverityNumber != 13 || record.SeverityText != "WARN" ||
+		record.Body.StringValue != "Slow query" || record.TraceID != "0af7651916cd43dd8448eb211c80319c" ||
+		record.SpanID != "b7ad6b7169203331" {
+		test.Errorf("Unexpected log record: %s", body)
+	}
+	if len(record.Attributes) != 2 || record.Attributes[0].Key != "logger.name" || record.Attributes[1].Value["intValue"] != "3" {
+		test.Errorf("Unexpected attributes: %+v", record.Attributes)
+	}
+}
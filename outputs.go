@@ -0,0 +1,344 @@
+package gologsgo
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// OutputConfig declares one output in the "handlers" section of a config
+type OutputConfig struct {
+	// Type is "console" (stderr), "stdout", "file", "http" (an HTTPHandler) or
+	// "network" (a NetworkHandler)
+	Type string `json:"type"`
+	// Format is "text", "json", "logstash", "ecs", "template" or, for console
+	// and stdout, "color".
+	// Defaults to "color" for console and stdout and "text" for files. The
+	// "http" type sends a JSON array of entries, so it only takes "json",
+	// "logstash" and "ecs", and defaults to "json", as does "network".
+	Format string `json:"format,omitempty"`
+	// Path is the file written by the "file" type
+	Path string `json:"path,omitempty"`
+	// URL is where the "http" and "network" types send entries. For "network"
+	// it is tcp://host:port, tls://host:port, udp://host:port or
+	// unix:///path/to/socket.
+	URL string `json:"url,omitempty"`
+	// Rotate, when set, rotates the file of the "file" type. See RotatingFile.
+	Rotate *RotateConfig `json:"rotate,omitempty"`
+	// MinLevel and MaxLevel, when set, limit the output to entries in a band of
+	// levels, for example to send WARN and above to a separate file
+	MinLevel LogLevel `json:"minLevel,omitempty"`
+	MaxLevel LogLevel `json:"maxLevel,omitempty"`
+	// Colors configures the colors of the "color" format
+	Colors *ColorConfig `json:"colors,omitempty"`
+	// Symbols prefixes entries of the "color" format with a symbol for their level
+	Symbols *SymbolConfig `json:"symbols,omitempty"`
+	// Keys renames the keys of the "json" format. See JSONEncoderWithKeys().
+	Keys map[string]string `json:"keys,omitempty"`
+	// SingleLine escapes newlines and other control characters in the "text",
+	// "color" and "template" formats, so each entry is exactly one line
+	SingleLine bool `json:"singleLine,omitempty"`
+	// Template is the layout of the "template" format. See TemplateEncoder().
+	Template string `json:"template,omitempty"`
+	// Stack renders the captured stacks of entries in the "text" and "color"
+	// formats. See RootLogConfig.CaptureStacks.
+	Stack *StackFormat `json:"stack,omitempty"`
+}
+
+// NewOutput builds the LogHandler for an output. The returned io.Closer, when not
+// nil, releases the output's resources, such as an open file.
+func NewOutput(output OutputConfig) (LogHandler, io.Closer, error) {
+	handler, closer, err := newOutput(output)
+	if err != nil || (output.MinLevel == NotSet && output.MaxLevel == NotSet) {
+		return handler, closer, err
+	}
+	return levelBand(handler, output.MinLevel, output.MaxLevel), closer, nil
+}
+
+// levelBand returns a LogHandler that passes entries with levels from min to max
+// to handler. NotSet leaves a bound open.
+func levelBand(handler LogHandler, min LogLevel, max LogLevel) LogHandler {
+	return func(msg LogMessage) {
+		if msg.Level < min || (max != NotSet && msg.Level > max) {
+			return
+		}
+		handler(msg)
+	}
+}
+
+// newOutput builds the LogHandler for an output, without its level band
+func newOutput(output OutputConfig) (LogHandler, io.Closer, error) {
+	plan, err := checkOutput(output)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plan.open()
+}
+
+// outputPlan is an output whose settings have been checked, ready to be opened
+type outputPlan struct {
+	output  OutputConfig
+	w       io.Writer
+	encoder Encoder
+	// handler is set for outputs that need nothing opened
+	handler LogHandler
+}
+
+// checkOutput checks the settings of an output and prepares its encoder,
+// without opening files or starting handlers, so a config can be checked
+// without side effects
+func checkOutput(output OutputConfig) (*outputPlan, error) {
+	if output.MaxLevel != NotSet && output.MinLevel > output.MaxLevel {
+		return nil, fmt.Errorf("The minLevel of an output may not be above its maxLevel")
+	}
+	var w io.Writer
+	format := output.Format
+	if len(output.URL) > 0 && output.Type != "http" && output.Type != "network" {
+		return nil, fmt.Errorf("Output type %q does not take a url", output.Type)
+	}
+	if nil != output.Rotate && output.Type != "file" {
+		return nil, fmt.Errorf("Only files can be rotated, not output type %q", output.Type)
+	}
+	switch output.Type {
+	case "http", "network":
+		if len(output.Path) > 0 {
+			return nil, fmt.Errorf("Output type %q does not take a path", output.Type)
+		}
+		if len(output.URL) < 1 {
+			return nil, fmt.Errorf("Output type %q requires a url", output.Type)
+		}
+		if len(format) == 0 {
+			format = "json"
+		}
+		if format == "color" {
+			return nil, fmt.Errorf("The \"color\" format can't be sent to output type %q", output.Type)
+		}
+		if output.Type == "http" && format != "json" && format != "logstash" && format != "ecs" {
+			return nil, fmt.Errorf("Output type \"http\" sends JSON, so it takes the \"json\", \"logstash\" and \"ecs\" formats, not %q", format)
+		}
+	case "console", "stdout":
+		if len(output.Path) > 0 {
+			return nil, fmt.Errorf("Output type %q does not take a path", output.Type)
+		}
+		w = os.Stderr
+		if output.Type == "stdout" {
+			w = os.Stdout
+		}
+		if len(format) == 0 {
+			format = "color"
+		}
+	case "file":
+		if len(output.Path) < 1 {
+			return nil, fmt.Errorf("Output type \"file\" requires a path")
+		}
+		if format == "color" {
+			return nil, fmt.Errorf("The \"color\" format can't be written to a file")
+		}
+	default:
+		return nil, fmt.Errorf("Unknown output type %q. Known types are: \"console\", \"stdout\", \"file\", \"http\", \"network\"", output.Type)
+	}
+
+	if nil != output.Colors && format != "color" {
+		return nil, fmt.Errorf("Colors only apply to the \"color\" format, not %q", format)
+	}
+	if nil != output.Symbols && format != "color" {
+		return nil, fmt.Errorf("Symbols only apply to the \"color\" format, not %q", format)
+	}
+	if nil != output.Stack {
+		if format != "text" && format != "color" && len(format) > 0 {
+			return nil, fmt.Errorf("Stack only applies to the \"text\" and \"color\" formats, not %q", format)
+		}
+		if err := output.Stack.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if len(output.Template) > 0 && format != "template" {
+		return nil, fmt.Errorf("A template only applies to the \"template\" format, not %q", format)
+	}
+	if len(output.Keys) > 0 && format != "json" {
+		return nil, fmt.Errorf("Keys only apply to the \"json\" format, not %q", format)
+	}
+
+	var encoder Encoder
+	switch format {
+	case "color":
+		h := defaultLeveledLogHandler
+		h.Logger = log.New(w, "", log.LstdFlags)
+		if nil != output.Colors {
+			theme, err := NewTheme(*output.Colors)
+			if err != nil {
+				return nil, err
+			}
+			h.Levels = theme
+			h.ColorMode = output.Colors.Mode
+		}
+		if nil != output.Symbols {
+			symbols, err := NewSymbols(*output.Symbols)
+			if err != nil {
+				return nil, err
+			}
+			h.Symbols = symbols
+			h.SymbolMode = output.Symbols.Mode
+		}
+		h.SingleLine = output.SingleLine
+		h.Stack = output.Stack
+		return &outputPlan{output: output, handler: h.LogHandler}, nil
+	case "", "text":
+		encoder = TextEncoder
+		if nil != output.Stack {
+			encoder = textWithStack(*output.Stack, output.SingleLine)
+		}
+		if output.SingleLine {
+			encoder = SingleLine(encoder)
+		}
+	case "json":
+		encoder = JSONEncoder
+		if len(output.Keys) > 0 {
+			var err error
+			if encoder, err = JSONEncoderWithKeys(output.Keys); err != nil {
+				return nil, err
+			}
+		}
+	case "logstash":
+		encoder = LogstashEncoder()
+	case "ecs":
+		encoder = ECSEncoder
+	case "template":
+		if len(output.Template) == 0 {
+			return nil, fmt.Errorf("The \"template\" format requires a template")
+		}
+		var err error
+		if encoder, err = TemplateEncoder(output.Template); err != nil {
+			return nil, err
+		}
+		if output.SingleLine {
+			encoder = SingleLine(encoder)
+		}
+	default:
+		return nil, fmt.Errorf("Unknown output format %q. Known formats are: \"text\", \"color\", \"json\", \"logstash\", \"ecs\", \"template\"", format)
+	}
+
+	// The http type restricts its formats above, as it sends JSON arrays
+	sink := "console"
+	switch output.Type {
+	case "file":
+		if nil != output.Rotate {
+			if err := output.Rotate.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		sink = "file"
+	case "http":
+		if _, err := url.Parse(output.URL); err != nil {
+			return nil, fmt.Errorf("Invalid HTTPHandler URL: %s", err)
+		}
+		sink = ""
+	case "network":
+		config, err := networkURL(output.URL)
+		if err != nil {
+			return nil, err
+		}
+		sink = strings.TrimRight(config.Network, "46")
+	}
+	if len(format) == 0 {
+		format = "text"
+	}
+	if len(sink) > 0 {
+		if err := checkCompatibility(sink, format, output.SingleLine); err != nil {
+			return nil, err
+		}
+	}
+	return &outputPlan{output: output, w: w, encoder: encoder}, nil
+}
+
+// open opens the file of the output or starts its handler
+func (p *outputPlan) open() (LogHandler, io.Closer, error) {
+	if nil != p.handler {
+		return p.handler, nil, nil
+	}
+	output, w, encoder := p.output, p.w, p.encoder
+	var closer io.Closer
+	switch output.Type {
+	case "file":
+		if nil != output.Rotate {
+			rf, err := NewRotatingFile(output.Path, *output.Rotate)
+			if err != nil {
+				return nil, nil, err
+			}
+			w, closer = rf, rf
+			break
+		}
+		f, err := os.OpenFile(output.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+		w, closer = f, f
+	case "http":
+		h, err := NewHTTPHandler(HTTPHandlerConfig{URL: output.URL, Encoder: encoder})
+		if err != nil {
+			return nil, nil, err
+		}
+		return h.LogHandler, h, nil
+	case "network":
+		config, err := networkURL(output.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.Encoder = encoder
+		h, err := NewNetworkHandler(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return h.LogHandler, h, nil
+	}
+	return WriterHandler(w, encoder).LogHandler(nil), closer, nil
+}
+
+// networkURL converts the url of a "network" output to a NetworkHandlerConfig
+func networkURL(rawurl string) (NetworkHandlerConfig, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return NetworkHandlerConfig{}, fmt.Errorf("Invalid network url: %s", err)
+	}
+	switch u.Scheme {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		return NetworkHandlerConfig{Network: u.Scheme, Address: u.Host}, nil
+	case "tls":
+		return NetworkHandlerConfig{
+			Network:   "tcp",
+			Address:   u.Host,
+			TLSConfig: &tls.Config{ServerName: u.Hostname()},
+		}, nil
+	case "unix", "unixgram":
+		return NetworkHandlerConfig{Network: u.Scheme, Address: u.Path}, nil
+	default:
+		return NetworkHandlerConfig{}, fmt.Errorf("Unknown network url scheme %q. Known schemes are: \"tcp\", \"tls\", \"udp\", \"unix\", \"unixgram\"", u.Scheme)
+	}
+}
+
+// textWithStack returns a TextEncoder that renders the captured stack of entries
+func textWithStack(format StackFormat, singleLine bool) Encoder {
+	return func(msg LogMessage) ([]byte, error) {
+		data, err := TextEncoder(msg)
+		if err != nil || len(msg.Stack) == 0 {
+			return data, err
+		}
+		return append(data, appendStack(format, msg.Stack, singleLine)...), nil
+	}
+}
+
+// fanOut returns a LogHandler that passes each LogMessage to all of handlers
+func fanOut(handlers []LogHandler) LogHandler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return func(msg LogMessage) {
+		for _, handler := range handlers {
+			handler(msg)
+		}
+	}
+}
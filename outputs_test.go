@@ -0,0 +1,124 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestHandlersFromConfig(test *testing.T) {
+	dir, err := ioutil.TempDir("", "outputs")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	jsonPath := filepath.Join(dir, "app.json")
+	textPath := filepath.Join(dir, "app.log")
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"label": "app",
+		"handlers": []map[string]string{
+			{"type": "file", "path": jsonPath, "format": "json"},
+			{"type": "file", "path": textPath},
+		},
+	})
+	config, err := logs.JsonConfig(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	logger := logs.New(config)
+	logger.Info("Written twice")
+	if err := logger.Close(); err != nil {
+		test.Fatalf("Unexpected error closing the outputs: %v", err)
+	}
+
+	jsonOut, _ := ioutil.ReadFile(jsonPath)
+	textOut, _ := ioutil.ReadFile(textPath)
+	if !strings.Contains(string(jsonOut), `"message":"Written twice"`) {
+		test.Errorf("Expected JSON output. Found: %q", jsonOut)
+	}
+	if !strings.Contains(string(textOut), "INFO [app]: Written twice") {
+		test.Errorf("Expected text output. Found: %q", textOut)
+	}
+}
+
+func TestHandlersLevelBands(test *testing.T) {
+	dir, err := ioutil.TempDir("", "outputs")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	infoPath := filepath.Join(dir, "info.log")
+	errorPath := filepath.Join(dir, "error.log")
+
+	received := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- string(body)
+	}))
+	defer server.Close()
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"level": "DEBUG",
+		"handlers": []map[string]interface{}{
+			{"type": "file", "path": infoPath, "maxLevel": "INFO", "rotate": map[string]interface{}{"maxSizeMB": 10}},
+			{"type": "file", "path": errorPath, "minLevel": "WARN"},
+			{"type": "http", "url": server.URL, "minLevel": "ERROR"},
+		},
+	})
+	config, err := logs.JsonConfig(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	logger := logs.New(config)
+	logger.Debug("Debugging")
+	logger.Warn("Warning")
+	logger.Error("Failing")
+	if err := logger.Close(); err != nil {
+		test.Fatalf("Unexpected error closing the outputs: %v", err)
+	}
+
+	infoOut, _ := ioutil.ReadFile(infoPath)
+	errorOut, _ := ioutil.ReadFile(errorPath)
+	if !strings.Contains(string(infoOut), "Debugging") || strings.Contains(string(infoOut), "Warning") {
+		test.Errorf("Expected only DEBUG and INFO entries. Found: %q", infoOut)
+	}
+	if strings.Contains(string(errorOut), "Debugging") || !strings.Contains(string(errorOut), "Warning") || !strings.Contains(string(errorOut), "Failing") {
+		test.Errorf("Expected only WARN and ERROR entries. Found: %q", errorOut)
+	}
+	select {
+	case body := <-received:
+		if !strings.Contains(body, `"message":"Failing"`) || strings.Contains(body, "Warning") {
+			test.Errorf("Expected only the ERROR entry to be sent. Found: %s", body)
+		}
+	default:
+		test.Error("Expected the ERROR entry to be sent on Close")
+	}
+}
+
+func TestHandlersConfigValidation(test *testing.T) {
+	for _, config := range []string{
+		`{"handlers": [{"type": "file", "path": "app.log", "format": "color"}]}`,
+		`{"handlers": [{"type": "file"}]}`,
+		`{"handlers": [{"type": "carrier-pigeon"}]}`,
+		`{"handlers": [{"type": "console", "format": "yaml"}]}`,
+		`{"handler": "json-stdout", "handlers": [{"type": "console"}]}`,
+		`{"handlers": [{"type": "http"}]}`,
+		`{"handlers": [{"type": "http", "url": "http://collector", "format": "text"}]}`,
+		`{"handlers": [{"type": "network", "url": "carrier-pigeon://coop"}]}`,
+		`{"handlers": [{"type": "console", "url": "http://collector"}]}`,
+		`{"handlers": [{"type": "console", "rotate": {"daily": true}}]}`,
+		`{"handlers": [{"type": "console", "minLevel": "ERROR", "maxLevel": "WARN"}]}`,
+	} {
+		if _, err := logs.JsonConfig([]byte(config)); err == nil {
+			test.Errorf("Expected an error for %s", config)
+		}
+	}
+}
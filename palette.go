@@ -0,0 +1,92 @@
+package gologsgo
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// paletteCode returns the SGR parameters for a 256 color ("color(208)") or
+// truecolor ("#ff8700") token, either of which may be prefixed with "bg-" to set
+// the background. ok is false when the token is neither.
+func paletteCode(token string) (code string, ok bool, err error) {
+	base := "38"
+	color := token
+	if strings.HasPrefix(color, "bg-") {
+		base = "48"
+		color = strings.TrimPrefix(color, "bg-")
+	}
+
+	switch {
+	case strings.HasPrefix(color, "color(") && strings.HasSuffix(color, ")"):
+		n, err := strconv.Atoi(color[len("color(") : len(color)-1])
+		if err != nil || n < 0 || n > 255 {
+			return "", true, fmt.Errorf("Invalid 256 color %q. Use color(0) to color(255)", token)
+		}
+		return fmt.Sprintf("%s;5;%d", base, n), true, nil
+	case strings.HasPrefix(color, "#"):
+		rgb, err := strconv.ParseUint(color[1:], 16, 32)
+		if err != nil || len(color) != 7 {
+			return "", true, fmt.Errorf("Invalid truecolor %q. Use #rrggbb", token)
+		}
+		r, g, b := int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)
+		if truecolor() {
+			return fmt.Sprintf("%s;2;%d;%d;%d", base, r, g, b), true, nil
+		}
+		return fmt.Sprintf("%s;5;%d", base, nearest256(r, g, b)), true, nil
+	}
+	return "", false, nil
+}
+
+// truecolor reports whether the terminal advertises 24-bit color support
+func truecolor() bool {
+	colorterm := os.Getenv("COLORTERM")
+	return colorterm == "truecolor" || colorterm == "24bit"
+}
+
+// cubeLevels are the intensities of each channel in the xterm 6x6x6 color cube
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// nearest256 returns the xterm 256 color palette index closest to an RGB color,
+// for terminals without truecolor support
+func nearest256(r, g, b int) int {
+	nearestLevel := func(v int) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(v-level) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDistance := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// The grayscale ramp (232 to 255) runs from 8 to 238 in steps of 10
+	gray := (r+g+b)/3 - 8
+	if gray < 0 {
+		gray = 0
+	}
+	step := (gray + 5) / 10
+	if step > 23 {
+		step = 23
+	}
+	level := 8 + 10*step
+	if distance(r, g, b, level, level, level) < cubeDistance {
+		return 232 + step
+	}
+	return cube
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,71 @@
+package gologsgo
+
+import (
+	"path"
+	"strings"
+)
+
+// isPattern reports whether a key of Loggers is a glob pattern rather than the
+// name of a child logger
+func isPattern(key string) bool {
+	return strings.ContainsAny(key, "*?[")
+}
+
+// childConfig returns the configuration of the named child of a Logger. An entry
+// for the name in the Logger's Loggers is used if there is one. Otherwise, glob
+// patterns in the Loggers of the Logger and its ancestors are matched, nearest
+// first, against the dotted path of the child below the logger declaring them,
+// so that "http.*" in the root configuration matches "http.client" and
+// "http.client.retry", and "*_test" matches "db_test" and "db.cache_test". A
+// matching pattern is copied so that changes to one logger's level don't affect
+// the others it matches. childlock must be held.
+func (logger *Logger) childConfig(name string) *LogConfig {
+	config, pattern := logger.lookupChildConfig(name)
+	if nil == config {
+		return &LogConfig{}
+	}
+	if len(pattern) > 0 {
+		return copyLogConfig(config)
+	}
+	return config
+}
+
+// lookupChildConfig finds the configuration of the named child of a Logger as
+// described by childConfig(), returning the pattern that matched it, if any. The
+// configuration is nil when there is none. childlock must be held.
+func (logger *Logger) lookupChildConfig(name string) (*LogConfig, string) {
+	if config, ok := logger.logConfig.Loggers[name]; ok && nil != config {
+		return config, ""
+	}
+
+	rel := name
+	for ancestor := logger; nil != ancestor; ancestor = ancestor.parent {
+		if pattern, config := matchPatterns(ancestor.logConfig.Loggers, rel); nil != config {
+			return config, pattern
+		}
+		rel = ancestor.name + "." + rel
+	}
+	return nil, ""
+}
+
+// matchPatterns returns the most specific pattern in loggers that matches name,
+// and its configuration. Longer patterns are considered more specific, and ties
+// are broken by comparing the patterns, so the result doesn't depend on map
+// order. Malformed patterns never match.
+func matchPatterns(loggers map[string]*LogConfig, name string) (string, *LogConfig) {
+	var best string
+	var config *LogConfig
+	for key, c := range loggers {
+		if nil == c || !isPattern(key) {
+			continue
+		}
+		if ok, err := path.Match(key, name); err != nil || !ok {
+			continue
+		}
+		if nil == config || len(key) > len(best) || (len(key) == len(best) && key < best) {
+			best = key
+			config = c
+		}
+	}
+	return best, config
+}
@@ -0,0 +1,58 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLoggerPatterns(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(`{
+		"level": "INFO",
+		"loggers": {
+			"main.http.*": {"level": "DEBUG"},
+			"main.http.client.*": {"level": "TRACE"},
+			"*_test": {"level": "ERROR"},
+			"main": {
+				"level": "WARN",
+				"loggers": {"db": {"level": "INFO"}}
+			}
+		}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	config.LogHandler = func(logs.LogMessage) {}
+	logger := logs.New(config)
+
+	cases := map[string]logs.LogLevel{
+		"main":                   logs.Warn,
+		"main.http":              logs.Warn,
+		"main.http.server":       logs.Debug,
+		"main.http.server.tls":   logs.Debug,
+		"main.http.client.retry": logs.Trace,
+		"main.db":                logs.Info,
+		"main.db_test":           logs.Error,
+		"api_test":               logs.Error,
+		"api":                    logs.Info,
+	}
+	for name, level := range cases {
+		if found := logger.ChildLogger(name).Level(); found != level {
+			test.Errorf("Expected %s to be at %s. Found: %s", name, logs.LogLevels.Label(level), logs.LogLevels.Label(found))
+		}
+	}
+
+	// Loggers matched by the same pattern have their own levels
+	logger.ChildLogger("main.http.server").SetLevel(logs.Off)
+	if found := logger.ChildLogger("main.http.server").Level(); found != logs.Off {
+		test.Errorf("Expected main.http.server to be OFF. Found: %s", logs.LogLevels.Label(found))
+	}
+	if found := logger.ChildLogger("main.http.admin").Level(); found != logs.Debug {
+		test.Errorf("Expected main.http.admin to keep the pattern's level. Found: %s", logs.LogLevels.Label(found))
+	}
+
+	logger.ApplyLevels(&logs.RootLogConfig{Loggers: map[string]*logs.LogConfig{"main.*": {Level: logs.Trace}}})
+	if found := logger.ChildLogger("main.http.server").Level(); found != logs.Trace {
+		test.Errorf("Expected applied patterns to match existing loggers. Found: %s", logs.LogLevels.Label(found))
+	}
+}
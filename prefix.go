@@ -0,0 +1,86 @@
+package gologsgo
+
+import (
+	"strings"
+	"sync"
+)
+
+// defaultPrefixColors is the palette LoggerPrefixes assigns from when no Colors
+// are configured. Red and yellow are left out so a prefix is never mistaken for
+// an ERROR or WARN message.
+var defaultPrefixColors = []Formatter{
+	mustColor("cyan"),
+	mustColor("green"),
+	mustColor("magenta"),
+	mustColor("blue"),
+	mustColor("hi-cyan"),
+	mustColor("hi-green"),
+	mustColor("hi-magenta"),
+	mustColor("hi-blue"),
+}
+
+// LoggerPrefixes tags each line written by a LeveledLogHandler with a short,
+// colored, per-logger prefix - like the service prefixes docker-compose adds -
+// so CLIs that run several subcommands concurrently can tell their interleaved
+// output apart on a single terminal.
+type LoggerPrefixes struct {
+	// Tags maps a logger label to the tag printed for it. Loggers without an
+	// entry use the last segment of their label, or "root" for the root logger.
+	Tags map[string]string
+	// Colors is the palette tags are colored from, assigned in the order loggers
+	// are first seen. Defaults to a set of colors that don't clash with the
+	// level colors.
+	Colors []Formatter
+	// Separator is written between the tag and the rest of the line. Defaults
+	// to " | ".
+	Separator string
+
+	mu       sync.Mutex
+	assigned map[string]Formatter
+	width    int
+}
+
+// Tag returns the uncolored tag for the logger with the given label
+func (p *LoggerPrefixes) Tag(label string) string {
+	if tag, ok := p.Tags[label]; ok {
+		return tag
+	}
+	if len(label) == 0 {
+		return "root"
+	}
+	return label[strings.LastIndex(label, ".")+1:]
+}
+
+// Prefix returns the colored prefix for the logger with the given label. Tags
+// are padded to the widest tag seen so far so that messages line up.
+func (p *LoggerPrefixes) Prefix(label string) string {
+	tag := p.Tag(label)
+
+	p.mu.Lock()
+	if nil == p.assigned {
+		p.assigned = make(map[string]Formatter)
+	}
+	colorFn, ok := p.assigned[label]
+	if !ok {
+		palette := p.Colors
+		if len(palette) == 0 {
+			palette = defaultPrefixColors
+		}
+		colorFn = palette[len(p.assigned)%len(palette)]
+		p.assigned[label] = colorFn
+	}
+	if len(tag) > p.width {
+		p.width = len(tag)
+	}
+	width := p.width
+	p.mu.Unlock()
+
+	separator := p.Separator
+	if len(separator) == 0 {
+		separator = " | "
+	}
+
+	padded := tag + strings.Repeat(" ", width-len(tag)) + separator
+	// Escape any % in the tag - the Formatter treats its first argument as a format
+	return colorFn("%s", padded)
+}
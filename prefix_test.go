@@ -0,0 +1,66 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// captureStdLog redirects the stdlib logger (used by the default handlers) in to
+// a buffer while fn runs and returns what was written, without date or time flags.
+// The stdlib logger's output and flags are restored when the test ends.
+func captureStdLog(test *testing.T, fn func()) string {
+	var buffer bytes.Buffer
+	writer := bufio.NewWriter(&buffer)
+	output, flags := log.Writer(), log.Flags()
+	test.Cleanup(func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	})
+	log.SetOutput(writer)
+	log.SetFlags(0)
+
+	fn()
+
+	writer.Flush()
+	return buffer.String()
+}
+
+func TestLoggerPrefixes(test *testing.T) {
+	handler := logs.LeveledLogHandler{
+		Format:     "%s [%s]: %s",
+		RootFormat: "%s: %s",
+		Prefixes: &logs.LoggerPrefixes{
+			Tags: map[string]string{
+				"cli.build": "bld",
+			},
+			Colors: []logs.Formatter{fmt.Sprintf},
+		},
+	}
+	root := logs.New(&logs.RootLogConfig{
+		Label:      "cli",
+		LogHandler: handler.LogHandler,
+	})
+
+	expectedOut := `bld | INFO [cli.build]: compiling
+deploy | INFO [cli.deploy]: uploading
+bld    | INFO [cli.build]: done
+cli    | INFO [cli]: finished
+`
+
+	actualOut := captureStdLog(test, func() {
+		root.ChildLogger("build").Info("compiling")
+		root.ChildLogger("deploy").Info("uploading")
+		root.ChildLogger("build").Info("done")
+		root.Info("finished")
+	})
+
+	// Tags are padded to the widest seen so far, so the first line is narrower
+	if actualOut != expectedOut {
+		test.Errorf("Did not receive expected prefixed log messages:\n%s\nShould be:\n%s", actualOut, expectedOut)
+	}
+}
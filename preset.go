@@ -0,0 +1,60 @@
+package gologsgo
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Presets, for RootLogConfig.Preset
+const (
+	// PresetDevelopment logs at the DEBUG level as colored, human readable
+	// lines, with millisecond timestamps and each field on its own line
+	PresetDevelopment = "development"
+	// PresetProduction logs at the INFO level as single line JSON to stderr, with
+	// UTC timestamps. Identical entries logged within a second of each other are
+	// sampled: the first is written, and the rest are summarized by a single
+	// entry with a repeat count.
+	PresetProduction = "production"
+)
+
+// developmentTimeFormat is the timestamp layout of PresetDevelopment
+const developmentTimeFormat = "15:04:05.000"
+
+// productionSampleWindow is the window PresetProduction samples repeated entries
+// over
+const productionSampleWindow = time.Second
+
+// NewDevelopment returns a root Logger configured with PresetDevelopment
+func NewDevelopment() *Logger {
+	return New(&RootLogConfig{Preset: PresetDevelopment})
+}
+
+// NewProduction returns a root Logger configured with PresetProduction. Close it
+// on shutdown so sampled entries are summarized.
+func NewProduction() *Logger {
+	return New(&RootLogConfig{Preset: PresetProduction})
+}
+
+func validatePreset(preset string) error {
+	switch preset {
+	case "", PresetDevelopment, PresetProduction:
+		return nil
+	}
+	return fmt.Errorf("Unknown preset %q. Use %q or %q", preset, PresetDevelopment, PresetProduction)
+}
+
+// productionHandler builds the handler of PresetProduction, registering its
+// sampler as a closer
+func productionHandler(config *RootLogConfig) LogHandler {
+	handler := WriterHandler(os.Stderr, JSONEncoder).LogHandler(config.ErrorCallback)
+	loc := time.UTC
+	if len(config.Timezone) > 0 {
+		if l, err := time.LoadLocation(config.Timezone); err == nil {
+			loc = l
+		}
+	}
+	sampler := Dedup(InLocation(handler, loc), productionSampleWindow)
+	config.Closers = append(config.Closers, sampler)
+	return sampler.LogHandler
+}
@@ -0,0 +1,73 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestDevelopmentPreset(test *testing.T) {
+	var buf bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(output)
+
+	logger := logs.NewDevelopment()
+	if logger.Level() != logs.Debug {
+		test.Errorf("Expected the DEBUG level. Found: %s", logs.LogLevels.Label(logger.Level()))
+	}
+	logger.SetField("user", "jane")
+	logger.SetField("id", 7)
+	logger.Debug("Signed in")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], " DEBUG: Signed in") || lines[1] != "    id: 7" || lines[2] != "    user: jane" {
+		test.Errorf("Unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestProductionPreset(test *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		test.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	config, err := logs.JsonConfig([]byte(`{"preset": "production"}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	logger := logs.New(config)
+	os.Stderr = stderr
+	logger.Debug("Hidden")
+	for i := 0; i < 3; i++ {
+		logger.Info("Cache miss")
+	}
+	logger.Close()
+	w.Close()
+
+	data, _ := ioutil.ReadAll(r)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		test.Fatalf("Expected an entry and a summary. Found:\n%s", data)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil || entry["message"] != "Cache miss" || !strings.HasSuffix(entry["time"].(string), "Z") {
+		test.Errorf("Unexpected entry: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "repeated 2 times") {
+		test.Errorf("Unexpected summary: %s", lines[1])
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"preset": "staging"}`)); err == nil {
+		test.Error("Expected an error for an unknown preset")
+	}
+}
@@ -0,0 +1,69 @@
+package gologsgo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ProfileEnv is the environment variable that selects a profile of a config when
+// its Profile is not set
+var ProfileEnv = "LOG_PROFILE"
+
+// WithProfile returns the config with the named profile applied: the profile's
+// settings are merged over the rest of the config with MergeConfigs(), so a
+// profile only needs the levels and handlers that differ. An empty name selects
+// the profile named by Profile, or else by the ProfileEnv environment variable.
+// Without a selection, or without any Profiles, the config is returned as it is.
+// New() applies the selected profile.
+func (config *RootLogConfig) WithProfile(name string) (*RootLogConfig, error) {
+	if len(name) == 0 {
+		name = config.Profile
+	}
+	if len(name) == 0 {
+		name = os.Getenv(ProfileEnv)
+	}
+	if len(name) == 0 || len(config.Profiles) == 0 {
+		return config, nil
+	}
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(config.Profiles))
+		for n := range config.Profiles {
+			names = append(names, fmt.Sprintf("%q", n))
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("Unknown profile %q. Known profiles are: %s", name, strings.Join(names, ", "))
+	}
+
+	base := *config
+	base.Profiles = nil
+	merged := MergeConfigs(&base, profile)
+	merged.Profile = name
+	merged.Profiles = nil
+	return merged, nil
+}
+
+// validateProfiles checks the profiles of a config
+func validateProfiles(profiles map[string]*RootLogConfig) error {
+	for name, profile := range profiles {
+		if nil == profile {
+			continue
+		}
+		if len(profile.Profiles) > 0 || len(profile.Profile) > 0 {
+			return fmt.Errorf("Profile %q: Profiles may not be nested", name)
+		}
+		if _, err := compileRules(profile.Rules); err != nil {
+			return fmt.Errorf("Profile %q: %s", name, err)
+		}
+		if err := validateVolumeControls(profile.Sample, profile.RateLimitPerSec); err != nil {
+			return fmt.Errorf("Profile %q: %s", name, err)
+		}
+		if err := validateLoggersVolumeControls(profile.Loggers); err != nil {
+			return fmt.Errorf("Profile %q: %s", name, err)
+		}
+	}
+	return nil
+}
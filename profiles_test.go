@@ -0,0 +1,80 @@
+package gologsgo_test
+
+import (
+	"os"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+const profilesConfig = `{
+	"level": "INFO",
+	"label": "app",
+	"loggers": {"db": {"level": "WARN"}, "http": {"level": "INFO"}},
+	"profiles": {
+		"dev": {"level": "DEBUG", "loggers": {"db": {"level": "TRACE"}}},
+		"prod": {"level": "WARN", "handler": "json-stderr"}
+	}
+}`
+
+func TestProfiles(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(profilesConfig))
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	dev, err := config.WithProfile("dev")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if dev.Level != logs.Debug || dev.Label != "app" || dev.Loggers["db"].Level != logs.Trace || dev.Loggers["http"].Level != logs.Info {
+		test.Errorf("Expected the dev profile to be merged over the config. Found: %+v", dev)
+	}
+	if dev.Profile != "dev" || nil != dev.Profiles || len(config.Profiles) != 2 {
+		test.Errorf("Expected the profiles to be resolved in a copy. Found: %+v", dev)
+	}
+	prod, err := config.WithProfile("prod")
+	if err != nil || prod.Level != logs.Warn || nil == prod.Handler || prod.Handler.Name != "json-stderr" {
+		test.Errorf("Expected the prod handler. Found: %+v, %v", prod, err)
+	}
+	if _, err := config.WithProfile("qa"); err == nil {
+		test.Error("Expected an error for an unknown profile")
+	}
+
+	os.Setenv(logs.ProfileEnv, "dev")
+	defer os.Unsetenv(logs.ProfileEnv)
+	config.LogHandler = func(logs.LogMessage) {}
+	logger := logs.New(config)
+	if logger.Level() != logs.Debug || logger.ChildLogger("db").Level() != logs.Trace {
+		test.Errorf("Expected New to apply the profile selected by %s. Found: %s", logs.ProfileEnv, logs.LogLevels.Label(logger.Level()))
+	}
+
+	config.Profile = "prod"
+	if logger := logs.New(config); logger.Level() != logs.Warn {
+		test.Errorf("Expected Profile to take precedence over %s. Found: %s", logs.ProfileEnv, logs.LogLevels.Label(logger.Level()))
+	}
+}
+
+func TestProfilesErrors(test *testing.T) {
+	os.Setenv(logs.ProfileEnv, "qa")
+	defer os.Unsetenv(logs.ProfileEnv)
+	if _, err := logs.JsonConfig([]byte(profilesConfig)); err == nil {
+		test.Error("Expected an error for an unknown profile")
+	}
+	os.Unsetenv(logs.ProfileEnv)
+
+	for _, data := range []string{
+		`{"profiles": {"dev": {"profiles": {"local": {}}}}}`,
+		`{"profiles": {"dev": {"rules": [{"match": "("}]}}}`,
+		`{"profiles": {"dev": {"level": "LOUD"}}}`,
+	} {
+		if _, err := logs.JsonConfig([]byte(data)); err == nil {
+			test.Errorf("Expected an error for %s", data)
+		}
+	}
+
+	_, err := logs.StrictJsonConfig([]byte(`{"profiles": {"dev": {"levle": "DEBUG"}}}`))
+	if err == nil || err.Error() != "profiles.dev.levle: Unknown key" {
+		test.Errorf("Expected an unknown key in the profile. Found: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package logsprom exposes go-logs-go log volume as Prometheus metrics. It lives
+// in its own module so that users who don't use Prometheus don't inherit it as a
+// dependency.
+package logsprom
+
+import (
+	logs "github.com/big-squid/go-logs-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics counts log entries and handler errors. It is a prometheus.Collector,
+// so register it with a prometheus.Registerer:
+//
+//	metrics := logsprom.NewMetrics()
+//	prometheus.MustRegister(metrics)
+type Metrics struct {
+	entries       *prometheus.CounterVec
+	handlerErrors *prometheus.CounterVec
+}
+
+// NewMetrics returns Metrics with the counters log_entries_total{level,logger}
+// and log_handler_errors_total{handler}
+func NewMetrics() *Metrics {
+	return &Metrics{
+		entries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "log_entries_total",
+			Help: "Number of log entries, by level and logger.",
+		}, []string{"level", "logger"}),
+		handlerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "log_handler_errors_total",
+			Help: "Number of log entries a handler failed to deliver.",
+		}, []string{"handler"}),
+	}
+}
+
+// Hook returns a logs.Hook that counts each entry in log_entries_total. Add it
+// last to RootLogConfig.Hooks, so that entries dropped by other hooks aren't
+// counted.
+func (m *Metrics) Hook() logs.Hook {
+	return func(msg *logs.LogMessage) bool {
+		m.entries.WithLabelValues(msg.LevelLabel, msg.Logger).Inc()
+		return true
+	}
+}
+
+// Instrument wraps a logs.LogHandlerE so that the errors it returns are counted
+// in log_handler_errors_total with the given handler name
+func (m *Metrics) Instrument(name string, handler logs.LogHandlerE) logs.LogHandlerE {
+	errors := m.handlerErrors.WithLabelValues(name)
+	return func(msg logs.LogMessage) error {
+		err := handler(msg)
+		if err != nil {
+			errors.Inc()
+		}
+		return err
+	}
+}
+
+// Describe is part of prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.entries.Describe(ch)
+	m.handlerErrors.Describe(ch)
+}
+
+// Collect is part of prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.entries.Collect(ch)
+	m.handlerErrors.Collect(ch)
+}
@@ -0,0 +1,44 @@
+package logsprom_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+	logsprom "github.com/big-squid/go-logs-go/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(test *testing.T) {
+	metrics := logsprom.NewMetrics()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+
+	failing := func(logs.LogMessage) error {
+		return errors.New("disk full")
+	}
+	root := logs.New(&logs.RootLogConfig{
+		Label:         "app",
+		Hooks:         []logs.Hook{metrics.Hook()},
+		LogHandlerE:   metrics.Instrument("file", failing),
+		ErrorCallback: func(error, logs.LogMessage) {},
+	})
+	root.Info("one")
+	root.ChildLogger("db").Error("two")
+	root.ChildLogger("db").Error("three")
+
+	expected := `
+# HELP log_entries_total Number of log entries, by level and logger.
+# TYPE log_entries_total counter
+log_entries_total{level="ERROR",logger="app.db"} 2
+log_entries_total{level="INFO",logger="app"} 1
+# HELP log_handler_errors_total Number of log entries a handler failed to deliver.
+# TYPE log_handler_errors_total counter
+log_handler_errors_total{handler="file"} 3
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected)); err != nil {
+		test.Error(err)
+	}
+}
@@ -0,0 +1,104 @@
+package gologsgo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PropertiesConfig creates a RootLogConfig from a Java style properties file, for
+// teams migrating from log4j or logback. Only keys starting with "log." are
+// read, so the settings can share a file with others:
+//
+//	log.level=INFO
+//	log.label=app
+//	log.logger.main=WARN
+//	log.logger.main.db=DEBUG
+//
+// log.logger.<logger> sets the level of a logger, named with dots. Other keys
+// are those of the JSON accepted by JsonConfig(), with dots between the keys of
+// nested objects, such as log.colors.mode=always. Lines starting with # or ! are
+// comments, keys and values may be separated with = or :, and a line ending in a
+// backslash continues on the next.
+func PropertiesConfig(data []byte) (*RootLogConfig, error) {
+	cfg := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var line string
+	lineNo, start := 0, 0
+	for scanner.Scan() {
+		lineNo++
+		text := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			start = lineNo
+			if len(text) == 0 || text[0] == '#' || text[0] == '!' {
+				continue
+			}
+		}
+		if strings.HasSuffix(text, "\\") {
+			line += strings.TrimSuffix(text, "\\")
+			continue
+		}
+		line += text
+
+		if err := setProperty(cfg, line); err != nil {
+			return nil, fmt.Errorf("Invalid properties config on line %d: %s", start, err)
+		}
+		line = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(line) > 0 {
+		if err := setProperty(cfg, line); err != nil {
+			return nil, fmt.Errorf("Invalid properties config on line %d: %s", start, err)
+		}
+	}
+
+	return MapConfig(cfg)
+}
+
+// setProperty sets the config value described by a properties line
+func setProperty(cfg map[string]interface{}, line string) error {
+	sep := strings.IndexAny(line, "=:")
+	if sep < 0 {
+		return fmt.Errorf("%q is not a key=value pair", line)
+	}
+	key, value := strings.TrimSpace(line[:sep]), strings.TrimSpace(line[sep+1:])
+	if !strings.HasPrefix(key, "log.") {
+		return nil
+	}
+	keys := strings.Split(strings.TrimPrefix(key, "log."), ".")
+	for _, k := range keys {
+		if len(k) == 0 {
+			return fmt.Errorf("Invalid key %q", key)
+		}
+	}
+
+	lvlCfg := cfg
+	if keys[0] == "logger" {
+		if len(keys) == 1 {
+			return fmt.Errorf("%s requires a logger name, such as log.logger.main", key)
+		}
+		for _, k := range keys[1:] {
+			lvlCfg = childMap(childMap(lvlCfg, "loggers"), k)
+		}
+		lvlCfg["level"] = value
+		return nil
+	}
+	for _, k := range keys[:len(keys)-1] {
+		lvlCfg = childMap(lvlCfg, k)
+	}
+	lvlCfg[keys[len(keys)-1]] = value
+	return nil
+}
+
+// childMap returns the object at key in cfg, adding it if it doesn't exist
+func childMap(cfg map[string]interface{}, key string) map[string]interface{} {
+	child, ok := cfg[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		cfg[key] = child
+	}
+	return child
+}
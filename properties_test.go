@@ -0,0 +1,47 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestPropertiesConfig(test *testing.T) {
+	config, err := logs.PropertiesConfig([]byte(`
+# Logging
+log.level=INFO
+log.label : app
+! Colors
+log.colors.mode=never
+log.logger.main=WARN
+log.logger.main.db=\
+    DEBUG
+server.port=8080
+`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Info || config.Label != "app" {
+		test.Errorf("Unexpected config: %+v", config)
+	}
+	if nil == config.Colors || config.Colors.Mode != logs.ColorNever {
+		test.Errorf("Expected the colors to be read. Found: %+v", config.Colors)
+	}
+	main := config.Loggers["main"]
+	if nil == main || main.Level != logs.Warn || nil == main.Loggers["db"] || main.Loggers["db"].Level != logs.Debug {
+		test.Errorf("Expected the main loggers to be configured. Found: %+v", main)
+	}
+}
+
+func TestPropertiesConfigErrors(test *testing.T) {
+	for _, data := range []string{
+		"log.level",
+		"log.logger=DEBUG",
+		"log..level=DEBUG",
+		"log.logger.main=LOUD",
+	} {
+		if _, err := logs.PropertiesConfig([]byte(data)); err == nil {
+			test.Errorf("Expected an error for %q", data)
+		}
+	}
+}
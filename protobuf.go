@@ -0,0 +1,149 @@
+package gologsgo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// This file contains the small subset of the Protocol Buffers wire format
+// (https://protobuf.dev/programming-guides/encoding/) needed to encode the
+// LogEntry message defined in logentry.proto, so the package doesn't depend on
+// the protobuf runtime. Changes to logentry.proto must be made here too.
+
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+func appendProtoVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendProtoTag(b []byte, field int, wireType int) []byte {
+	return appendProtoVarint(b, uint64(field<<3|wireType))
+}
+
+// appendProtoString appends a string field. Empty strings are the default value,
+// so are omitted.
+func appendProtoString(b []byte, field int, s string) []byte {
+	if len(s) == 0 {
+		return b
+	}
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendProtoVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendProtoInt appends an int64 field. Zero is the default value, so is
+// omitted.
+func appendProtoInt(b []byte, field int, i int64) []byte {
+	if i == 0 {
+		return b
+	}
+	b = appendProtoTag(b, field, protoVarint)
+	return appendProtoVarint(b, uint64(i))
+}
+
+// appendProtoMessage appends an embedded message field
+func appendProtoMessage(b []byte, field int, message []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendProtoVarint(b, uint64(len(message)))
+	return append(b, message...)
+}
+
+// appendProtoValue appends a Value message. Members of a oneof are always
+// written, even when they hold the default value.
+func appendProtoValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return b
+	case string:
+		b = appendProtoTag(b, 1, protoBytes)
+		b = appendProtoVarint(b, uint64(len(v)))
+		return append(b, v...)
+	case bool:
+		b = appendProtoTag(b, 2, protoVarint)
+		if v {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case int:
+		return appendProtoIntValue(b, int64(v))
+	case int8:
+		return appendProtoIntValue(b, int64(v))
+	case int16:
+		return appendProtoIntValue(b, int64(v))
+	case int32:
+		return appendProtoIntValue(b, int64(v))
+	case int64:
+		return appendProtoIntValue(b, v)
+	case uint8:
+		return appendProtoIntValue(b, int64(v))
+	case uint16:
+		return appendProtoIntValue(b, int64(v))
+	case uint32:
+		return appendProtoIntValue(b, int64(v))
+	case float32:
+		return appendProtoDoubleValue(b, float64(v))
+	case float64:
+		return appendProtoDoubleValue(b, v)
+	default:
+		return appendProtoValue(b, fmt.Sprint(v))
+	}
+}
+
+func appendProtoIntValue(b []byte, i int64) []byte {
+	b = appendProtoTag(b, 3, protoVarint)
+	return appendProtoVarint(b, uint64(i))
+}
+
+func appendProtoDoubleValue(b []byte, f float64) []byte {
+	b = appendProtoTag(b, 4, protoFixed64)
+	b = append(b, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(b[len(b)-8:], math.Float64bits(f))
+	return b
+}
+
+func init() {
+	RegisterFormat("protobuf", FormatTraits{Binary: true})
+}
+
+// ProtobufEncoder encodes a LogMessage as the LogEntry message defined in
+// logentry.proto, so gRPC based collectors and the network handlers share a
+// stable wire format. Generate code from logentry.proto to decode it. Use it
+// with LengthPrefixFraming, since messages are not self-delimiting.
+func ProtobufEncoder(msg LogMessage) ([]byte, error) {
+	b := make([]byte, 0, 64+len(msg.Message))
+	if !msg.Time.IsZero() {
+		b = appendProtoInt(b, 1, msg.Time.UnixNano())
+	}
+	b = appendProtoInt(b, 2, int64(msg.Level))
+	b = appendProtoString(b, 3, msg.LevelLabel)
+	b = appendProtoString(b, 4, msg.Logger)
+	b = appendProtoString(b, 5, msg.Message)
+
+	var entry, value []byte
+	for _, k := range fieldKeys(msg.Fields) {
+		// Each map entry is a message with the key as field 1 and the value as field 2
+		value = appendProtoValue(value[:0], msg.Fields[k])
+		entry = appendProtoString(entry[:0], 1, k)
+		entry = appendProtoMessage(entry, 2, value)
+		b = appendProtoMessage(b, 6, entry)
+	}
+
+	if len(msg.Stack) > 0 {
+		frame := msg.Stack[0]
+		var caller []byte
+		caller = appendProtoString(caller, 1, frame.Function)
+		caller = appendProtoString(caller, 2, frame.File)
+		caller = appendProtoInt(caller, 3, int64(frame.Line))
+		b = appendProtoMessage(b, 7, caller)
+	}
+	return b, nil
+}
@@ -0,0 +1,41 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestProtobufEncoder(test *testing.T) {
+	data, err := logs.ProtobufEncoder(logs.LogMessage{
+		Level:      logs.Warn,
+		LevelLabel: "warn",
+		Logger:     "db",
+		Message:    "slow",
+		Time:       time.Unix(0, 300),
+		Fields:     map[string]interface{}{"ms": 1.5, "table": "users"},
+		Stack:      []logs.StackFrame{{Function: "main.f", File: "f.go", Line: 7}},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := []byte{
+		0x08, 0xac, 0x02, // time_unix_nano = 300
+		0x10, 0x05, // level = LEVEL_WARN
+		0x1a, 4, 'w', 'a', 'r', 'n',
+		0x22, 2, 'd', 'b',
+		0x2a, 4, 's', 'l', 'o', 'w',
+		// fields["ms"] = double_value 1.5
+		0x32, 15, 0x0a, 2, 'm', 's', 0x12, 9, 0x21, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f,
+		// fields["table"] = string_value "users"
+		0x32, 16, 0x0a, 5, 't', 'a', 'b', 'l', 'e', 0x12, 7, 0x0a, 5, 'u', 's', 'e', 'r', 's',
+		// caller
+		0x3a, 16, 0x0a, 6, 'm', 'a', 'i', 'n', '.', 'f', 0x12, 4, 'f', '.', 'g', 'o', 0x18, 7,
+	}
+	if !bytes.Equal(data, expected) {
+		test.Errorf("Expected:\n% x\nFound:\n% x", expected, data)
+	}
+}
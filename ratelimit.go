@@ -0,0 +1,54 @@
+package gologsgo
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit wraps handler so that at most perSec entries a second reach it, in
+// bursts of up to perSec entries (or 1, if perSec is less). Entries beyond the
+// limit are dropped, so a logger stuck in a loop can't flood its sinks.
+func RateLimit(handler LogHandler, perSec float64) LogHandler {
+	burst := perSec
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := &rateLimiter{
+		rate:   perSec,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+	return func(msg LogMessage) {
+		if limiter.allow() {
+			handler(msg)
+		}
+	}
+}
+
+// rateLimiter is a token bucket refilled at rate tokens a second
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token, reporting whether there was one
+func (l *rateLimiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,72 @@
+package gologsgo_test
+
+import (
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestRateLimit(test *testing.T) {
+	count := 0
+	handler := logs.RateLimit(func(logs.LogMessage) { count++ }, 20)
+	for i := 0; i < 100; i++ {
+		handler(logs.LogMessage{Level: logs.Error})
+	}
+	if count != 20 {
+		test.Errorf("Expected a burst of 20 entries. Found: %d", count)
+	}
+	// Entries are dropped until a token has been added
+	deadline := time.Now().Add(5 * time.Second)
+	for count == 20 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		handler(logs.LogMessage{Level: logs.Error})
+	}
+	if count != 21 {
+		test.Errorf("Expected the limit to refill. Found: %d", count)
+	}
+}
+
+func TestVolumeControlsFromConfig(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(`{
+		"loggers": {
+			"chatty": {"rateLimitPerSec": 5},
+			"verbose": {"sample": {"every": 10}}
+		}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	counts := map[string]int{}
+	config.LogHandler = func(msg logs.LogMessage) { counts[msg.Logger]++ }
+	logger := logs.New(config)
+
+	chatty := logger.ChildLogger("chatty")
+	verbose := logger.ChildLogger("verbose")
+	for i := 0; i < 100; i++ {
+		chatty.Info("Again")
+		chatty.ChildLogger("loop").Info("Again")
+		verbose.Info("Again")
+		verbose.Warn("Always")
+		logger.Info("Unlimited")
+	}
+	if counts["chatty"]+counts["chatty.loop"] != 5 {
+		test.Errorf("Expected chatty and its children to share a limit of 5. Found: %v", counts)
+	}
+	if counts["verbose"] != 110 {
+		test.Errorf("Expected every 10th INFO entry and every WARN entry of verbose. Found: %d", counts["verbose"])
+	}
+	if counts[""] != 100 {
+		test.Errorf("Expected the root to be unlimited. Found: %d", counts[""])
+	}
+
+	for _, data := range []string{
+		`{"sample": {"rate": 1.5}}`,
+		`{"rateLimitPerSec": -1}`,
+		`{"loggers": {"db": {"sample": {"every": -2}}}}`,
+	} {
+		if _, err := logs.JsonConfig([]byte(data)); err == nil {
+			test.Errorf("Expected an error for %s", data)
+		}
+	}
+}
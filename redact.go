@@ -0,0 +1,140 @@
+package gologsgo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactionConfig configures RedactionHook(). It can be set in config files as
+// "redaction".
+type RedactionConfig struct {
+	// Builtins names the built-in patterns to apply: "credit-card", "email" and
+	// "bearer-token". Use "all" for every built-in pattern.
+	Builtins []string `json:"builtins"`
+	// Rules are additional patterns to mask
+	Rules []RedactionRule `json:"rules"`
+	// Fields names fields whose values are always masked, such as "password".
+	// Names are matched case insensitively.
+	Fields []string `json:"fields"`
+	// Mask replaces redacted values. Defaults to "[REDACTED]".
+	Mask string `json:"mask"`
+}
+
+// RedactionRule masks text matching a regular expression
+type RedactionRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+type redactor struct {
+	pattern *regexp.Regexp
+	// replace, when set, builds the replacement for a match. It returns the
+	// match unchanged to leave it unredacted.
+	replace func(match string, mask string) string
+}
+
+var builtinRedactors = map[string]redactor{
+	"credit-card": {
+		pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		replace: func(match string, mask string) string {
+			if !luhn(match) {
+				return match
+			}
+			return mask
+		},
+	},
+	"email": {
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	"bearer-token": {
+		pattern: regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`),
+		replace: func(match string, mask string) string {
+			return match[:len("bearer")] + " " + mask
+		},
+	},
+}
+
+// RedactionHook returns a Hook that masks sensitive values in the message and
+// fields of each entry before it reaches any handler
+func RedactionHook(config RedactionConfig) (Hook, error) {
+	mask := config.Mask
+	if len(mask) == 0 {
+		mask = "[REDACTED]"
+	}
+
+	var redactors []redactor
+	for _, name := range config.Builtins {
+		if name == "all" {
+			for _, name := range []string{"credit-card", "email", "bearer-token"} {
+				redactors = append(redactors, builtinRedactors[name])
+			}
+			continue
+		}
+		r, ok := builtinRedactors[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown built-in redaction pattern %q", name)
+		}
+		redactors = append(redactors, r)
+	}
+	for _, rule := range config.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid redaction rule %q: %s", rule.Name, err)
+		}
+		redactors = append(redactors, redactor{pattern: pattern})
+	}
+
+	fields := make(map[string]bool, len(config.Fields))
+	for _, name := range config.Fields {
+		fields[strings.ToLower(name)] = true
+	}
+
+	redact := func(s string) string {
+		for _, r := range redactors {
+			if nil == r.replace {
+				s = r.pattern.ReplaceAllLiteralString(s, mask)
+				continue
+			}
+			s = r.pattern.ReplaceAllStringFunc(s, func(match string) string {
+				return r.replace(match, mask)
+			})
+		}
+		return s
+	}
+
+	return func(msg *LogMessage) bool {
+		msg.Message = redact(msg.Message)
+		for k, v := range msg.Fields {
+			if fields[strings.ToLower(k)] {
+				msg.Fields[k] = mask
+			} else if s, ok := v.(string); ok {
+				msg.Fields[k] = redact(s)
+			}
+		}
+		return true
+	}, nil
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum used by payment
+// card numbers
+func luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
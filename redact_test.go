@@ -0,0 +1,55 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestRedactionHook(test *testing.T) {
+	redact, err := logs.RedactionHook(logs.RedactionConfig{
+		Builtins: []string{"all"},
+		Rules:    []logs.RedactionRule{{Name: "ssn", Pattern: `\b\d{3}-\d{2}-\d{4}\b`}},
+		Fields:   []string{"Password"},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	msg := logs.LogMessage{
+		Message: "Charged 4111 1111 1111 1111 for jane@example.com (order 1234567890123), ssn 123-45-6789",
+		Fields: map[string]interface{}{
+			"password": "hunter2",
+			"header":   "Authorization: Bearer abc.def-ghi",
+			"count":    3,
+		},
+	}
+	redact(&msg)
+
+	expected := "Charged [REDACTED] for [REDACTED] (order 1234567890123), ssn [REDACTED]"
+	if msg.Message != expected {
+		test.Errorf("Expected %q. Found: %q", expected, msg.Message)
+	}
+	if msg.Fields["password"] != "[REDACTED]" || msg.Fields["header"] != "Authorization: Bearer [REDACTED]" || msg.Fields["count"] != 3 {
+		test.Errorf("Unexpected fields: %v", msg.Fields)
+	}
+}
+
+func TestRedactionFromConfig(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(`{"redaction": {"builtins": ["email"], "mask": "***"}}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	var received string
+	config.LogHandler = func(msg logs.LogMessage) {
+		received = msg.Message
+	}
+	logs.New(config).Info("Welcome, %s", "jane@example.com")
+	if received != "Welcome, ***" {
+		test.Errorf("Expected the configured redaction to apply. Found: %q", received)
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"redaction": {"rules": [{"name": "bad", "pattern": "("}]}}`)); err == nil {
+		test.Errorf("Expected an invalid rule to be a config error")
+	}
+}
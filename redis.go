@@ -0,0 +1,181 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// RedisHandlerConfig configures a Redis handler
+type RedisHandlerConfig struct {
+	// NetworkHandlerConfig configures the connection to Redis. Network defaults
+	// to "tcp". Encoder and Framing are ignored.
+	NetworkHandlerConfig
+	// Stream is the key of the stream entries are added to with XADD. Each entry
+	// has level, logger, message and time fields, plus any fields attached to
+	// the logger.
+	Stream string
+	// MaxLen caps the stream at approximately this many entries (XADD MAXLEN ~).
+	// Zero means the stream is not capped.
+	MaxLen int
+	// Channel, when set instead of Stream, PUBLISHes each entry to the channel
+	// as JSON
+	Channel string
+	// Password, when set, is sent with AUTH on each connection. Set Username as
+	// well to authenticate with a Redis 6 ACL user.
+	Username string
+	Password string
+	// DB, when non-zero, is selected on each connection
+	DB int
+}
+
+// NewRedisHandler returns a NetworkHandler that adds entries to a Redis stream,
+// or publishes them to a channel, for lightweight centralized log collection.
+func NewRedisHandler(config RedisHandlerConfig) (*NetworkHandler, error) {
+	if len(config.Network) == 0 {
+		config.Network = "tcp"
+	}
+	if (len(config.Stream) == 0) == (len(config.Channel) == 0) {
+		return nil, fmt.Errorf("RedisHandler requires exactly one of Stream or Channel")
+	}
+
+	if len(config.Stream) > 0 {
+		stream, maxLen := config.Stream, config.MaxLen
+		config.Encoder = func(msg LogMessage) ([]byte, error) {
+			return redisXAdd(stream, maxLen, msg), nil
+		}
+	} else {
+		channel := config.Channel
+		config.Encoder = func(msg LogMessage) ([]byte, error) {
+			data, err := JSONEncoder(msg)
+			if err != nil {
+				return nil, err
+			}
+			return redisCommand("PUBLISH", channel, string(data)), nil
+		}
+	}
+	config.Framing = func(data []byte) []byte {
+		return data
+	}
+
+	h, err := NewNetworkHandler(config.NetworkHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var setup [][]byte
+	if len(config.Password) > 0 && len(config.Username) > 0 {
+		setup = append(setup, redisCommand("AUTH", config.Username, config.Password))
+	} else if len(config.Password) > 0 {
+		setup = append(setup, redisCommand("AUTH", config.Password))
+	}
+	if config.DB != 0 {
+		setup = append(setup, redisCommand("SELECT", strconv.Itoa(config.DB)))
+	}
+	if len(setup) > 0 {
+		h.handshake = func(conn net.Conn) error {
+			for _, cmd := range setup {
+				if _, err := conn.Write(cmd); err != nil {
+					return err
+				}
+				if err := readRedisReply(conn); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	h.confirm = func(conn net.Conn, data []byte) error {
+		conn.SetReadDeadline(time.Now().Add(h.config.WriteTimeout))
+		return readRedisReply(conn)
+	}
+
+	return h, nil
+}
+
+// redisXAdd builds an XADD command for a LogMessage
+func redisXAdd(stream string, maxLen int, msg LogMessage) []byte {
+	args := []string{"XADD", stream}
+	if maxLen > 0 {
+		args = append(args, "MAXLEN", "~", strconv.Itoa(maxLen))
+	}
+	args = append(args, "*",
+		"time", msg.Time.Format(time.RFC3339Nano),
+		"level", levelLabel(msg),
+		"logger", msg.Logger,
+		"message", msg.Message,
+	)
+
+	keys := make([]string, 0, len(msg.Fields))
+	for k := range msg.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, k, fmt.Sprint(msg.Fields[k]))
+	}
+
+	return redisCommand(args...)
+}
+
+// redisCommand encodes a command as a RESP array of bulk strings
+func redisCommand(args ...string) []byte {
+	b := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		b = append(b, fmt.Sprintf("$%d\r\n", len(arg))...)
+		b = append(b, arg...)
+		b = append(b, "\r\n"...)
+	}
+	return b
+}
+
+// readRedisReply reads and discards a single RESP reply, returning an error if
+// it is an error reply. Only the reply types returned by the commands this
+// package sends are supported.
+func readRedisReply(r io.Reader) error {
+	line, err := readRedisLine(r)
+	if err != nil {
+		return err
+	}
+	if len(line) == 0 {
+		return fmt.Errorf("Empty reply from Redis")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return nil
+	case '-':
+		return fmt.Errorf("Redis error: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("Malformed reply from Redis: %q", line)
+		}
+		if n < 0 {
+			return nil
+		}
+		_, err = io.ReadFull(r, make([]byte, n+2))
+		return err
+	default:
+		return fmt.Errorf("Unexpected reply from Redis: %q", line)
+	}
+}
+
+// readRedisLine reads up to and excluding the next CRLF. It reads a byte at a time
+// so nothing past the reply is consumed from the connection.
+func readRedisLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return "", err
+		}
+		if b[0] == '\n' && len(line) > 0 && line[len(line)-1] == '\r' {
+			return string(line[:len(line)-1]), nil
+		}
+		line = append(line, b[0])
+	}
+}
@@ -0,0 +1,80 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// serveFakeRedis accepts one connection, sending each command it receives on the
+// returned channel and replying with +OK
+func serveFakeRedis(test *testing.T, listener net.Listener) <-chan []string {
+	commands := make(chan []string, 10)
+	go func() {
+		defer close(commands)
+		conn, err := listener.Accept()
+		if nil != err {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			header, err := reader.ReadString('\n')
+			if nil != err {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			args := make([]string, n)
+			for i := range args {
+				reader.ReadString('\n')
+				arg, _ := reader.ReadString('\n')
+				args[i] = strings.TrimSuffix(arg, "\r\n")
+			}
+			commands <- args
+			conn.Write([]byte("+OK\r\n"))
+		}
+	}()
+	return commands
+}
+
+func TestRedisHandlerStream(test *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		test.Fatalf("Unable to listen: %s", err)
+	}
+	defer listener.Close()
+	commands := serveFakeRedis(test, listener)
+
+	handler, err := logs.NewRedisHandler(logs.RedisHandlerConfig{
+		NetworkHandlerConfig: logs.NetworkHandlerConfig{Address: listener.Addr().String()},
+		Stream:               "logs",
+		MaxLen:               1000,
+		Password:             "secret",
+	})
+	if nil != err {
+		test.Fatalf("Error creating RedisHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.Warn("A warn log message")
+	if err := handler.Close(); nil != err {
+		test.Errorf("Error closing RedisHandler: %s", err)
+	}
+
+	auth := <-commands
+	if strings.Join(auth, " ") != "AUTH secret" {
+		test.Errorf("Expected AUTH. Found: %v", auth)
+	}
+	xadd := strings.Join(<-commands, " ")
+	if !strings.HasPrefix(xadd, "XADD logs MAXLEN ~ 1000 * time ") ||
+		!strings.HasSuffix(xadd, "level WARN logger main message A warn log message") {
+		test.Errorf("Unexpected XADD: %s", xadd)
+	}
+}
@@ -0,0 +1,266 @@
+package gologsgo
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisWatchConfig configures Logger.WatchRedisConfig()
+type RedisWatchConfig struct {
+	// Network defaults to "tcp"
+	Network string
+	// Address is the host:port of Redis, or the socket path for unix networks
+	Address string
+	// TLSConfig, when set, wraps TCP connections in TLS
+	TLSConfig *tls.Config
+	// Password, when set, is sent with AUTH on each connection. Set Username as
+	// well to authenticate with a Redis 6 ACL user.
+	Username string
+	Password string
+	// DB, when non-zero, is selected on each connection
+	DB int
+	// Channel is subscribed to. Each message published to it is a JSON config
+	// whose levels are applied.
+	Channel string
+	// Key, when set, holds a JSON config that is read and applied on each
+	// connection, so that new instances, and instances that were disconnected,
+	// pick up the current levels
+	Key string
+	// DialTimeout defaults to 5 seconds
+	DialTimeout time.Duration
+	// MinBackoff is the delay before the first reconnection attempt. It doubles
+	// on each failure up to MaxBackoff. Defaults to 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// WatchRedisConfig subscribes to a Redis channel and applies the levels of each
+// JSON config published to it with ApplyLevels(), so the levels of a fleet can be
+// changed centrally during an incident:
+//
+//	redis-cli PUBLISH log-config '{"loggers": {"db": {"level": "DEBUG"}}}'
+//
+// When Key is set, the config stored there is applied on each connection. The
+// subscription is reconnected with backoff when the connection fails. Configs
+// that fail to parse, and connection errors, are reported to LastResortWriter.
+// Watching stops when the returned io.Closer, or the Logger, is closed.
+func (logger *Logger) WatchRedisConfig(config RedisWatchConfig) (io.Closer, error) {
+	if len(config.Address) == 0 {
+		return nil, fmt.Errorf("WatchRedisConfig requires an Address")
+	}
+	if len(config.Channel) == 0 {
+		return nil, fmt.Errorf("WatchRedisConfig requires a Channel")
+	}
+	if len(config.Network) == 0 {
+		config.Network = "tcp"
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+
+	w := &redisWatcher{
+		logger: logger,
+		config: config,
+		done:   make(chan struct{}),
+	}
+	go w.watch()
+	logger.RegisterCloser(w)
+	return w, nil
+}
+
+// redisWatcher applies the configs published to a Redis channel
+type redisWatcher struct {
+	logger *Logger
+	config RedisWatchConfig
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.Mutex
+	// conn is the current connection, closed by Close() to interrupt reads
+	conn net.Conn
+}
+
+func (w *redisWatcher) watch() {
+	retryWatch(w.done, w.config.MinBackoff, w.config.MaxBackoff, func() error {
+		err := w.subscribe()
+		if nil != err {
+			err = fmt.Errorf("Redis config subscription to %s failed: %s", w.config.Address, err)
+		}
+		return err
+	})
+}
+
+// subscribe connects, applies the config at Key and applies each message
+// published to Channel until the connection fails
+func (w *redisWatcher) subscribe() error {
+	conn, err := w.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	var setup [][]string
+	if len(w.config.Password) > 0 && len(w.config.Username) > 0 {
+		setup = append(setup, []string{"AUTH", w.config.Username, w.config.Password})
+	} else if len(w.config.Password) > 0 {
+		setup = append(setup, []string{"AUTH", w.config.Password})
+	}
+	if w.config.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(w.config.DB)})
+	}
+	for _, cmd := range setup {
+		if _, err := conn.Write(redisCommand(cmd...)); err != nil {
+			return err
+		}
+		if err := readRedisReply(r); err != nil {
+			return err
+		}
+	}
+
+	if len(w.config.Key) > 0 {
+		if _, err := conn.Write(redisCommand("GET", w.config.Key)); err != nil {
+			return err
+		}
+		value, err := readRedisValue(r)
+		if err != nil {
+			return err
+		}
+		if data, ok := value.(string); ok {
+			w.apply(data)
+		}
+	}
+
+	if _, err := conn.Write(redisCommand("SUBSCRIBE", w.config.Channel)); err != nil {
+		return err
+	}
+	for {
+		value, err := readRedisValue(r)
+		if err != nil {
+			return err
+		}
+		// Pushed messages are ["message", channel, payload]
+		msg, ok := value.([]interface{})
+		if !ok || len(msg) != 3 || msg[0] != "message" {
+			continue
+		}
+		if data, ok := msg[2].(string); ok {
+			w.apply(data)
+		}
+	}
+}
+
+// dial connects to Redis, recording the connection so Close() can interrupt it
+func (w *redisWatcher) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: w.config.DialTimeout}
+	var conn net.Conn
+	var err error
+	if nil != w.config.TLSConfig {
+		conn, err = tls.DialWithDialer(dialer, w.config.Network, w.config.Address, w.config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial(w.config.Network, w.config.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	select {
+	case <-w.done:
+		conn.Close()
+		return nil, fmt.Errorf("Closed")
+	default:
+	}
+	w.conn = conn
+	return conn, nil
+}
+
+// apply applies the levels of a JSON config
+func (w *redisWatcher) apply(data string) {
+	config, err := JsonConfig([]byte(data))
+	if err != nil {
+		lastResortWarning("Invalid config from Redis: %s. Keeping the current levels", err)
+		return
+	}
+	w.logger.applyReloaded(config)
+}
+
+// Close stops watching Redis
+func (w *redisWatcher) Close() error {
+	w.once.Do(func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		close(w.done)
+		if nil != w.conn {
+			w.conn.Close()
+		}
+	})
+	return nil
+}
+
+// readRedisValue reads a single RESP reply, returning simple and bulk strings as
+// strings, integers as int64s, arrays as []interface{} and null replies as nil
+func readRedisValue(r io.Reader) (interface{}, error) {
+	line, err := readRedisLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("Empty reply from Redis")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("Redis error: %s", line[1:])
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Malformed reply from Redis: %q", line)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("Malformed reply from Redis: %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return string(b[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("Malformed reply from Redis: %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = readRedisValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("Unexpected reply from Redis: %q", line)
+	}
+}
@@ -0,0 +1,108 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+// bulk encodes a RESP bulk string
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// serveRedisConfig accepts connections, replying to GET with config and to
+// SUBSCRIBE with a confirmation followed by each message sent on publish
+func serveRedisConfig(listener net.Listener, config string, publish <-chan string) {
+	for {
+		conn, err := listener.Accept()
+		if nil != err {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for {
+				header, err := reader.ReadString('\n')
+				if nil != err {
+					return
+				}
+				n, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+				args := make([]string, n)
+				for i := range args {
+					reader.ReadString('\n')
+					arg, _ := reader.ReadString('\n')
+					args[i] = strings.TrimSuffix(arg, "\r\n")
+				}
+				switch args[0] {
+				case "GET":
+					conn.Write([]byte(bulk(config)))
+				case "SUBSCRIBE":
+					conn.Write([]byte("*3\r\n" + bulk("subscribe") + bulk(args[1]) + ":1\r\n"))
+					for msg := range publish {
+						conn.Write([]byte("*3\r\n" + bulk("message") + bulk(args[1]) + bulk(msg)))
+					}
+					return
+				default:
+					conn.Write([]byte("+OK\r\n"))
+				}
+			}
+		}(conn)
+	}
+}
+
+func TestWatchRedisConfig(test *testing.T) {
+	output := logs.LastResortWriter
+	logs.LastResortWriter = ioutil.Discard
+	defer func() { logs.LastResortWriter = output }()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		test.Fatalf("Unable to listen: %s", err)
+	}
+	defer listener.Close()
+	publish := make(chan string)
+	defer close(publish)
+	go serveRedisConfig(listener, `{"level": "WARN"}`, publish)
+
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	db := logger.ChildLogger("db")
+	watcher, err := logger.WatchRedisConfig(logs.RedisWatchConfig{
+		Address:  listener.Addr().String(),
+		Password: "secret",
+		Channel:  "log-config",
+		Key:      "log-config",
+	})
+	if nil != err {
+		test.Fatal(err)
+	}
+	defer watcher.Close()
+
+	// Each send returns once the previous message has been written
+	publish <- `{"level": `
+	publish <- `{"level": "WARN", "loggers": {"db": {"level": "TRACE"}}}`
+	deadline := time.Now().Add(5 * time.Second)
+	for db.Level() != logs.Trace && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if logger.Level() != logs.Warn || db.Level() != logs.Trace {
+		test.Errorf("Expected the published levels to be applied. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(db.Level()))
+	}
+}
+
+func TestWatchRedisConfigErrors(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	if _, err := logger.WatchRedisConfig(logs.RedisWatchConfig{Channel: "log-config"}); nil == err {
+		test.Error("Expected an error without an Address")
+	}
+	if _, err := logger.WatchRedisConfig(logs.RedisWatchConfig{Address: "localhost:6379"}); nil == err {
+		test.Error("Expected an error without a Channel")
+	}
+}
@@ -0,0 +1,270 @@
+package gologsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// HandlerFactory builds a LogHandler from the options given for it in config. The
+// options are nil when none are given.
+type HandlerFactory func(options json.RawMessage) (LogHandler, error)
+
+// HandlerConfig selects a registered handler by name. In JSON it is either the
+// name alone, `"handler": "json-stdout"`, or an object with options,
+// `"handler": {"name": "file", "options": {"path": "app.log"}}`.
+type HandlerConfig struct {
+	Name    string          `json:"name"`
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// UnmarshalJSON accepts a handler name or an object with a name and options
+func (hc *HandlerConfig) UnmarshalJSON(b []byte) error {
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '"' {
+		hc.Options = nil
+		return json.Unmarshal(b, &hc.Name)
+	}
+
+	type plain HandlerConfig
+	return json.Unmarshal(b, (*plain)(hc))
+}
+
+// MarshalJSON writes a handler without options as its name alone
+func (hc HandlerConfig) MarshalJSON() ([]byte, error) {
+	if len(hc.Options) == 0 {
+		return json.Marshal(hc.Name)
+	}
+	type plain HandlerConfig
+	return json.Marshal(plain(hc))
+}
+
+var registrylock sync.RWMutex
+
+// handlerFactory is a HandlerFactory that also returns an io.Closer, when not
+// nil, that releases the handler's resources, such as an open file
+type handlerFactory func(options json.RawMessage) (LogHandler, io.Closer, error)
+
+var handlerFactories = map[string]handlerFactory{
+	"default": func(json.RawMessage) (LogHandler, io.Closer, error) {
+		return DefaultLogHandler, nil, nil
+	},
+	"text-stdout": func(json.RawMessage) (LogHandler, io.Closer, error) {
+		return WriterHandler(os.Stdout, TextEncoder).LogHandler(nil), nil, nil
+	},
+	"text-stderr": func(json.RawMessage) (LogHandler, io.Closer, error) {
+		return WriterHandler(os.Stderr, TextEncoder).LogHandler(nil), nil, nil
+	},
+	"json-stdout": jsonHandler("stdout"),
+	"json-stderr": jsonHandler("console"),
+	"file":        fileHandler,
+}
+
+// fileHandler appends entries to the file at the "path" option, in the "format"
+// option: "text" (the default), "json" or "logstash"
+func fileHandler(options json.RawMessage) (LogHandler, io.Closer, error) {
+	output := OutputConfig{Type: "file"}
+	if len(options) > 0 {
+		if err := json.Unmarshal(options, &output); err != nil {
+			return nil, nil, err
+		}
+	}
+	return NewOutput(output)
+}
+
+// jsonHandler writes JSON entries to stdout or stderr (the "console" output
+// type). The "keys" option renames keys, as JSONEncoderWithKeys() does.
+func jsonHandler(outputType string) handlerFactory {
+	return func(options json.RawMessage) (LogHandler, io.Closer, error) {
+		output := OutputConfig{Type: outputType, Format: "json"}
+		if len(options) > 0 {
+			var opts struct {
+				Keys map[string]string `json:"keys"`
+			}
+			if err := json.Unmarshal(options, &opts); err != nil {
+				return nil, nil, err
+			}
+			output.Keys = opts.Keys
+		}
+		return NewOutput(output)
+	}
+}
+
+// RegisterHandler makes a handler available by name to the "handler" setting of
+// config files, so output can be rewired without code changes. Registering an
+// existing name replaces it.
+func RegisterHandler(name string, factory HandlerFactory) {
+	registrylock.Lock()
+	defer registrylock.Unlock()
+	handlerFactories[name] = func(options json.RawMessage) (LogHandler, io.Closer, error) {
+		handler, err := factory(options)
+		return handler, nil, err
+	}
+}
+
+// NewHandler builds the registered handler selected by config. The returned
+// io.Closer, when not nil, releases the handler's resources, such as an open
+// file.
+func NewHandler(config HandlerConfig) (LogHandler, io.Closer, error) {
+	factory, err := registeredHandler(config.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handler, closer, err := factory(config.Options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to create handler %q: %s", config.Name, err)
+	}
+	return handler, closer, nil
+}
+
+// registeredHandler returns the factory registered as name
+func registeredHandler(name string) (handlerFactory, error) {
+	registrylock.RLock()
+	factory, ok := handlerFactories[name]
+	registrylock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown handler %q. Registered handlers are: %s", name, registeredHandlers())
+	}
+	return factory, nil
+}
+
+func registeredHandlers() string {
+	registrylock.RLock()
+	defer registrylock.RUnlock()
+	var names []string
+	for name := range handlerFactories {
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// checkHandlers checks the "handler" settings of a RootLogConfig and its loggers
+// without building them, so parsing a config doesn't open files or start
+// goroutines. The handlers are built by New(), with resolveHandler().
+func (config *RootLogConfig) checkHandlers() error {
+	if nil != config.Handler && len(config.Handlers) > 0 {
+		return fmt.Errorf("Only one of \"handler\" and \"handlers\" may be set")
+	}
+	if nil != config.Handler {
+		if _, err := registeredHandler(config.Handler.Name); err != nil {
+			return err
+		}
+	}
+	for i, output := range config.Handlers {
+		if _, err := checkOutput(output); err != nil {
+			return fmt.Errorf("Handler %d: %s", i, err)
+		}
+	}
+	return checkLoggerHandlers(config.Loggers)
+}
+
+func checkLoggerHandlers(loggers map[string]*LogConfig) error {
+	for name, config := range loggers {
+		if nil == config {
+			continue
+		}
+		if nil != config.Handler {
+			if _, err := registeredHandler(config.Handler.Name); err != nil {
+				return fmt.Errorf("Logger %q: %s", name, err)
+			}
+		}
+		if err := checkLoggerHandlers(config.Loggers); err != nil {
+			return fmt.Errorf("Logger %q: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// resolveHandler builds the LogHandlers selected by the "handler" settings of a
+// RootLogConfig and its loggers, unless a LogHandler has been set in code. What
+// they open is added to Closers.
+func (config *RootLogConfig) resolveHandler() error {
+	if nil != config.Handler && len(config.Handlers) > 0 {
+		return fmt.Errorf("Only one of \"handler\" and \"handlers\" may be set")
+	}
+	var closers []io.Closer
+	if nil == config.LogHandler && nil == config.LogHandlerE {
+		if nil != config.Handler {
+			handler, closer, err := NewHandler(*config.Handler)
+			if err != nil {
+				return err
+			}
+			config.LogHandler = handler
+			if nil != closer {
+				closers = append(closers, closer)
+			}
+		} else if len(config.Handlers) > 0 {
+			var handlers []LogHandler
+			for i, output := range config.Handlers {
+				handler, closer, err := NewOutput(output)
+				if err != nil {
+					closeAll(closers)
+					return fmt.Errorf("Handler %d: %s", i, err)
+				}
+				handlers = append(handlers, handler)
+				if nil != closer {
+					closers = append(closers, closer)
+				}
+			}
+			config.LogHandler = fanOut(handlers)
+		}
+	}
+	loggerClosers, err := resolveLoggerHandlers(config.Loggers)
+	closers = append(closers, loggerClosers...)
+	if err != nil {
+		closeAll(closers)
+		return err
+	}
+	config.Closers = append(config.Closers, closers...)
+	return nil
+}
+
+// resolveHandler builds the LogHandler selected by the "handler" setting of a
+// LogConfig, unless a LogHandler has been set in code. The returned io.Closer,
+// when not nil, releases what the handler opened.
+func (config *LogConfig) resolveHandler() (io.Closer, error) {
+	if nil == config.Handler || nil != config.LogHandler {
+		return nil, nil
+	}
+	handler, closer, err := NewHandler(*config.Handler)
+	if err != nil {
+		return nil, err
+	}
+	config.LogHandler = handler
+	return closer, nil
+}
+
+func resolveLoggerHandlers(loggers map[string]*LogConfig) ([]io.Closer, error) {
+	var closers []io.Closer
+	for name, config := range loggers {
+		if nil == config {
+			continue
+		}
+		closer, err := config.resolveHandler()
+		if nil != closer {
+			closers = append(closers, closer)
+		}
+		if err != nil {
+			return closers, fmt.Errorf("Logger %q: %s", name, err)
+		}
+		children, err := resolveLoggerHandlers(config.Loggers)
+		closers = append(closers, children...)
+		if err != nil {
+			return closers, fmt.Errorf("Logger %q: %s", name, err)
+		}
+	}
+	return closers, nil
+}
+
+// closeAll closes closers, for handlers built for a config that failed
+func closeAll(closers []io.Closer) {
+	for _, closer := range closers {
+		closer.Close()
+	}
+}
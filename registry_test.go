@@ -0,0 +1,149 @@
+package gologsgo_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestRegisterHandler(test *testing.T) {
+	var prefix string
+	var received []string
+	logs.RegisterHandler("test-registry", func(options json.RawMessage) (logs.LogHandler, error) {
+		var opts struct {
+			Prefix string `json:"prefix"`
+		}
+		if err := json.Unmarshal(options, &opts); err != nil {
+			return nil, err
+		}
+		prefix = opts.Prefix
+		return func(msg logs.LogMessage) {
+			received = append(received, opts.Prefix+msg.Message)
+		}, nil
+	})
+
+	config, err := logs.JsonConfig([]byte(`{
+		"level": "DEBUG",
+		"handler": {"name": "test-registry", "options": {"prefix": "> "}}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	logs.New(config).ChildLogger("child").Debug("Configured")
+
+	if prefix != "> " || len(received) != 1 || received[0] != "> Configured" {
+		test.Errorf("Expected the registered handler to be built with its options. Found: %v", received)
+	}
+}
+
+func TestHandlerConfigJSON(test *testing.T) {
+	var config logs.RootLogConfig
+	if err := json.Unmarshal([]byte(`{"handler": "json-stdout"}`), &config); err != nil {
+		test.Fatal(err)
+	}
+	if config.Handler.Name != "json-stdout" {
+		test.Errorf("Expected a handler given by name. Found: %+v", config.Handler)
+	}
+
+	data, _ := json.Marshal(config.Handler)
+	if string(data) != `"json-stdout"` {
+		test.Errorf("Expected a handler without options to marshal as its name. Found: %s", data)
+	}
+}
+
+func TestUnknownHandler(test *testing.T) {
+	_, err := logs.JsonConfig([]byte(`{"handler": "nope"}`))
+	if err == nil || !strings.Contains(err.Error(), `"json-stdout"`) {
+		test.Errorf("Expected an error listing the registered handlers. Found: %v", err)
+	}
+}
+
+func TestPerLoggerHandler(test *testing.T) {
+	dir, err := ioutil.TempDir("", "handlers")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"loggers": map[string]interface{}{
+			"audit": map[string]interface{}{
+				"handler": map[string]interface{}{
+					"name":    "file",
+					"options": map[string]string{"path": path, "format": "json"},
+				},
+			},
+		},
+	})
+	config, err := logs.JsonConfig(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	var root []string
+	config.LogHandler = func(msg logs.LogMessage) {
+		root = append(root, msg.Logger)
+	}
+	logger := logs.New(config)
+
+	logger.ChildLogger("billing").Info("Sibling")
+	logger.ChildLogger("audit").Info("Audited")
+	logger.ChildLogger("audit.login").Info("Also audited")
+
+	if len(root) != 1 || root[0] != "billing" {
+		test.Errorf("Expected only the sibling to use the root handler. Found: %v", root)
+	}
+	written, _ := ioutil.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"logger":"audit.login"`) {
+		test.Errorf("Expected audit and its children to write JSON to the file. Found: %q", written)
+	}
+}
+
+func TestFileHandlerOpenedByNew(test *testing.T) {
+	dir, err := ioutil.TempDir("", "handlers")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	auditPath := filepath.Join(dir, "audit.log")
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"handler": map[string]interface{}{"name": "file", "options": map[string]string{"path": path}},
+		"loggers": map[string]interface{}{
+			"audit": map[string]interface{}{
+				"handler": map[string]interface{}{"name": "file", "options": map[string]string{"path": auditPath}},
+			},
+		},
+	})
+	config, err := logs.JsonConfig(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		test.Errorf("Expected parsing the config not to open the file. Found: %v", err)
+	}
+
+	logger := logs.New(config)
+	logger.Info("Written")
+	if len(config.Closers) != 2 {
+		test.Errorf("Expected the files to be registered as closers. Found: %d", len(config.Closers))
+	}
+	if err := logger.Close(); err != nil {
+		test.Error(err)
+	}
+	written, _ := ioutil.ReadFile(path)
+	if !strings.Contains(string(written), "Written") {
+		test.Errorf("Expected the entry in the file. Found: %q", written)
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"loggers": {"audit": {"handler": "nope"}}}`)); err == nil {
+		test.Error("Expected an unknown handler of a logger to fail to parse")
+	}
+}
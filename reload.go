@@ -0,0 +1,335 @@
+package gologsgo
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ApplyLevels changes the levels of a live Logger and its descendants to those
+// of config, for turning on verbose logging without a restart. config.Level
+// becomes the level of the Logger, and config.Loggers the configuration of its
+// descendants, which inherit the level of their parent unless they set one. A
+// config without a level keeps the current level of the Logger, and config.Rules,
+// when set, replace the rules of the Logger and its descendants. Only levels are
+// applied: handlers, hooks and other settings are unchanged, and temporary levels
+// set with SetLevelFor() are replaced. The tree is updated under a lock, so child
+// loggers created concurrently see either the old or the new configuration.
+func (logger *Logger) ApplyLevels(config *RootLogConfig) {
+	if nil == config {
+		return
+	}
+	if nil != logger.base {
+		logger.base.ApplyLevels(config)
+		return
+	}
+
+	childlock.Lock()
+	defer childlock.Unlock()
+
+	logger.cancelTemporaries()
+	if nil != config.Rules {
+		rules, err := compileRules(config.Rules)
+		if err != nil {
+			lastResortWarning("%s. Ignoring the rule", err)
+		}
+		logger.setRules(rules)
+	}
+	level := config.Level
+	if level == NotSet {
+		level = logger.Level()
+	}
+	applied := &LogConfig{
+		Level:      level,
+		Handler:    logger.logConfig.Handler,
+		LogHandler: logger.logConfig.LogHandler,
+	}
+	for name, child := range config.Loggers {
+		if nil == applied.Loggers {
+			applied.Loggers = make(map[string]*LogConfig, len(config.Loggers))
+		}
+		applied.Loggers[name] = copyLogConfig(child)
+	}
+	logger.logConfig = applied
+	atomic.StoreInt32(&logger.level, int32(level))
+	logger.applyChildLevels()
+}
+
+// SetLevel changes the level of a live Logger. Its descendants inherit the new
+// level unless they set their own. NotSet makes a child logger inherit the level
+// of its parent again; it leaves the level of a root Logger unchanged. SetLevel
+// replaces a temporary level set with SetLevelFor().
+func (logger *Logger) SetLevel(level LogLevel) {
+	if nil != logger.base {
+		logger.base.SetLevel(level)
+		return
+	}
+	childlock.Lock()
+	defer childlock.Unlock()
+
+	if level == NotSet && nil == logger.parent {
+		return
+	}
+	logger.cancelTemporary()
+	logger.setLevel(level)
+}
+
+// SetLevelFor changes the level of a live Logger, like SetLevel(), and reverts it
+// after d, so a level raised during an incident isn't forgotten:
+//
+//	logger.ChildLogger("main.payments").SetLevelFor(logs.Trace, 15*time.Minute)
+//
+// Setting another temporary level before d elapses restarts the expiry, still
+// reverting to the level from before the first. SetLevel() and ApplyLevels()
+// replace the temporary level. A d that is not positive is the same as SetLevel().
+func (logger *Logger) SetLevelFor(level LogLevel, d time.Duration) {
+	if d <= 0 {
+		logger.SetLevel(level)
+		return
+	}
+	if nil != logger.base {
+		logger.base.SetLevelFor(level, d)
+		return
+	}
+
+	childlock.Lock()
+	defer childlock.Unlock()
+
+	if level == NotSet && nil == logger.parent {
+		return
+	}
+	previous := logger.logConfig.Level
+	if nil != logger.temporary {
+		previous = logger.temporary.previous
+		logger.cancelTemporary()
+	}
+	logger.setLevel(level)
+
+	temporary := &temporaryLevel{
+		expires:  time.Now().Add(d),
+		previous: previous,
+	}
+	temporary.timer = time.AfterFunc(d, func() {
+		childlock.Lock()
+		defer childlock.Unlock()
+		if logger.temporary != temporary {
+			return
+		}
+		logger.temporary = nil
+		logger.setLevel(temporary.previous)
+	})
+	logger.temporary = temporary
+}
+
+// temporaryLevel records a level set by SetLevelFor(), and the level it reverts
+// to
+type temporaryLevel struct {
+	timer    *time.Timer
+	expires  time.Time
+	previous LogLevel
+}
+
+// cancelTemporary stops the expiry of a temporary level, leaving the level as it
+// is. childlock must be held.
+func (logger *Logger) cancelTemporary() {
+	if nil != logger.temporary {
+		logger.temporary.timer.Stop()
+		logger.temporary = nil
+	}
+}
+
+// cancelTemporaries cancels the temporary levels of a Logger and its
+// descendants. childlock must be held.
+func (logger *Logger) cancelTemporaries() {
+	logger.cancelTemporary()
+	for _, child := range logger.children {
+		child.cancelTemporaries()
+	}
+}
+
+// setLevel changes the level of a Logger and updates its descendants. childlock
+// must be held.
+func (logger *Logger) setLevel(level LogLevel) {
+	config := *logger.logConfig
+	config.Level = level
+	logger.logConfig = &config
+
+	effective := level
+	if nil != logger.parent {
+		// The parent's configuration is updated too, so the level is kept when
+		// the parent's levels are applied again
+		if nil == logger.parent.logConfig.Loggers {
+			logger.parent.logConfig.Loggers = make(map[string]*LogConfig)
+		}
+		logger.parent.logConfig.Loggers[logger.name] = &config
+		if level == NotSet {
+			effective = logger.parent.Level()
+		}
+	}
+	atomic.StoreInt32(&logger.level, int32(effective))
+	logger.applyChildLevels()
+}
+
+// applyChildLevels updates the configuration and levels of the descendants of a
+// Logger from its configuration. childlock must be held.
+func (logger *Logger) applyChildLevels() {
+	for name, child := range logger.children {
+		config := logger.childConfig(name)
+		level := logger.childLevel(name, config)
+		child.logConfig = config
+		atomic.StoreInt32(&child.level, int32(level))
+		child.applyChildLevels()
+	}
+}
+
+// ReloadOnSignal calls loader whenever the process receives sig, typically
+// syscall.SIGHUP, and applies the levels of the config it returns with
+// ApplyLevels(), following the convention of Unix daemons re-reading their config
+// on SIGHUP. For example:
+//
+//	logger.ReloadOnSignal(syscall.SIGHUP, func() (*logs.RootLogConfig, error) {
+//		return logs.FileConfig("/etc/my-app/log.json")
+//	})
+//
+// A loader error is reported to LastResortWriter and the current levels are
+// kept. The signal is handled until the returned io.Closer, or the Logger, is
+// closed.
+func (logger *Logger) ReloadOnSignal(sig os.Signal, loader func() (*RootLogConfig, error)) io.Closer {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig)
+	reloader := &signalReloader{
+		signals: signals,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for {
+			select {
+			case <-signals:
+				config, err := loader()
+				if err != nil {
+					lastResortWarning("Unable to reload the config on %s: %s. Keeping the current levels", sig, err)
+					continue
+				}
+				logger.applyReloaded(config)
+			case <-reloader.done:
+				return
+			}
+		}
+	}()
+	logger.RegisterCloser(reloader)
+	return reloader
+}
+
+// signalReloader stops ReloadOnSignal() when it is closed
+type signalReloader struct {
+	signals chan os.Signal
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Close stops handling the signal
+func (r *signalReloader) Close() error {
+	r.once.Do(func() {
+		signal.Stop(r.signals)
+		close(r.done)
+	})
+	return nil
+}
+
+// consumeUpdates applies the levels of each config received from updates, like a
+// reloaded config, until updates or the returned io.Closer is closed
+func (logger *Logger) consumeUpdates(updates <-chan *RootLogConfig) io.Closer {
+	consumer := &updatesConsumer{done: make(chan struct{})}
+	go func() {
+		for {
+			select {
+			case config, ok := <-updates:
+				if !ok {
+					return
+				}
+				logger.applyReloaded(config)
+			case <-consumer.done:
+				return
+			}
+		}
+	}()
+	return consumer
+}
+
+// updatesConsumer stops consuming RootLogConfig.Updates when it is closed
+type updatesConsumer struct {
+	done chan struct{}
+	once sync.Once
+}
+
+// Close stops consuming updates
+func (c *updatesConsumer) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+	})
+	return nil
+}
+
+// retryWatch calls watch, which watches a remote config source until it fails,
+// until done is closed. Failures are reported to LastResortWriter, and watch is
+// called again after a backoff that doubles from minBackoff up to maxBackoff.
+func retryWatch(done <-chan struct{}, minBackoff time.Duration, maxBackoff time.Duration, watch func() error) {
+	backoff := minBackoff
+	for {
+		start := time.Now()
+		err := watch()
+		select {
+		case <-done:
+			return
+		default:
+		}
+		lastResortWarning("%s", err)
+
+		// A watch that was up for a while starts backing off again
+		if time.Since(start) > maxBackoff {
+			backoff = minBackoff
+		}
+		select {
+		case <-time.After(backoff):
+		case <-done:
+			return
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// applyReloaded applies the levels of a reloaded config. Only levels are applied,
+// so anything opened for the config's handlers is released.
+func (logger *Logger) applyReloaded(config *RootLogConfig) {
+	if nil == config {
+		return
+	}
+	for _, closer := range config.Closers {
+		closer.Close()
+	}
+	logger.ApplyLevels(config)
+}
+
+// parseConfig expands environment variables in config data and parses it in the
+// format of the extension of the file it was read from: ".toml", ".hcl",
+// ".properties" or, for any other extension, JSON, with the files it includes
+func parseConfig(path string, data []byte) (*RootLogConfig, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return TomlConfig(ExpandEnv(data))
+	case ".hcl":
+		return HclConfig(ExpandEnv(data))
+	case ".properties":
+		return PropertiesConfig(ExpandEnv(data))
+	default:
+		return jsonFileConfig(path, data)
+	}
+}
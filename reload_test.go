@@ -0,0 +1,209 @@
+package gologsgo_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestApplyLevels(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{
+		Level:      logs.Info,
+		LogHandler: func(logs.LogMessage) {},
+		Loggers: map[string]*logs.LogConfig{
+			"db": {Level: logs.Error},
+		},
+	})
+	db := logger.ChildLogger("db")
+	cache := db.ChildLogger("cache")
+	http := logger.ChildLogger("http")
+
+	logger.ApplyLevels(&logs.RootLogConfig{
+		Level: logs.Warn,
+		Loggers: map[string]*logs.LogConfig{
+			"db": {Loggers: map[string]*logs.LogConfig{"cache": {Level: logs.Trace}}},
+		},
+	})
+	for _, c := range []struct {
+		logger   *logs.Logger
+		expected logs.LogLevel
+	}{
+		{logger, logs.Warn},
+		{db, logs.Warn},
+		{cache, logs.Trace},
+		{http, logs.Warn},
+		{logger.ChildLogger("new"), logs.Warn},
+	} {
+		if c.logger.Level() != c.expected {
+			test.Errorf("Expected %q to be at %s. Found: %s", c.logger.Label(), logs.LogLevels.Label(c.expected), logs.LogLevels.Label(c.logger.Level()))
+		}
+	}
+
+	logger.ApplyLevels(&logs.RootLogConfig{})
+	if logger.Level() != logs.Warn || cache.Level() != logs.Warn {
+		test.Errorf("Expected an empty config to keep the root level. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(cache.Level()))
+	}
+}
+
+func TestApplyLevelsWhileLogging(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			logger.ChildLogger("worker").Debug("Working")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		logger.ApplyLevels(&logs.RootLogConfig{Level: logs.Debug})
+	}
+	wg.Wait()
+}
+
+func TestReloadOnSignal(test *testing.T) {
+	output := logs.LastResortWriter
+	logs.LastResortWriter = ioutil.Discard
+	defer func() { logs.LastResortWriter = output }()
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	db := logger.ChildLogger("db")
+
+	loads := make(chan *logs.RootLogConfig, 1)
+	errs := make(chan error)
+	reloader := logger.ReloadOnSignal(syscall.SIGHUP, func() (*logs.RootLogConfig, error) {
+		select {
+		case err := <-errs:
+			return nil, err
+		case config := <-loads:
+			return config, nil
+		}
+	})
+	defer reloader.Close()
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		test.Fatal(err)
+	}
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		test.Skipf("Unable to send SIGHUP: %s", err)
+	}
+	// A failed load keeps the current levels
+	errs <- fmt.Errorf("Missing file")
+	loads <- &logs.RootLogConfig{Loggers: map[string]*logs.LogConfig{"db": {Level: logs.Trace}}}
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		test.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for db.Level() != logs.Trace && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if db.Level() != logs.Trace || logger.Level() != logs.Info {
+		test.Errorf("Expected the levels to be reloaded. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(db.Level()))
+	}
+}
+
+func TestUpdates(test *testing.T) {
+	updates := make(chan *logs.RootLogConfig)
+	logger := logs.New(&logs.RootLogConfig{
+		LogHandler: func(logs.LogMessage) {},
+		Updates:    updates,
+	})
+	worker := logger.ChildLogger("worker")
+
+	// The send returns once the update has been received
+	updates <- &logs.RootLogConfig{Level: logs.Error, Loggers: map[string]*logs.LogConfig{"worker": {Level: logs.Trace}}}
+	updates <- &logs.RootLogConfig{Level: logs.Warn, Loggers: map[string]*logs.LogConfig{"worker": {Level: logs.Debug}}}
+	deadline := time.Now().Add(5 * time.Second)
+	for worker.Level() != logs.Debug && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if logger.Level() != logs.Warn || worker.Level() != logs.Debug {
+		test.Errorf("Expected the updates to be applied. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(worker.Level()))
+	}
+
+	// Only levels are applied, so what was opened for an update is released
+	closed := make(chan struct{})
+	updates <- &logs.RootLogConfig{Level: logs.Warn, Closers: []io.Closer{closerFunc(func() error {
+		close(closed)
+		return nil
+	})}}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		test.Error("Expected the closers of an update to be closed")
+	}
+
+	if err := logger.Close(); err != nil {
+		test.Fatal(err)
+	}
+	select {
+	case updates <- &logs.RootLogConfig{Level: logs.Trace}:
+		test.Error("Expected updates to stop being read after Close()")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSetLevel(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	db := logger.ChildLogger("db")
+	cache := db.ChildLogger("cache")
+
+	db.SetLevel(logs.Trace)
+	if db.Level() != logs.Trace || cache.Level() != logs.Trace || logger.Level() != logs.Info {
+		test.Errorf("Expected db and its children to be at TRACE. Found: %s, %s", logs.LogLevels.Label(db.Level()), logs.LogLevels.Label(cache.Level()))
+	}
+	logger.SetLevel(logs.Warn)
+	if db.Level() != logs.Trace || logger.ChildLogger("http").Level() != logs.Warn {
+		test.Errorf("Expected db to keep its level. Found: %s", logs.LogLevels.Label(db.Level()))
+	}
+	db.SetLevel(logs.NotSet)
+	logger.SetLevel(logs.NotSet)
+	if db.Level() != logs.Warn || cache.Level() != logs.Warn || logger.Level() != logs.Warn {
+		test.Errorf("Expected db to inherit the WARN level. Found: %s, %s", logs.LogLevels.Label(db.Level()), logs.LogLevels.Label(cache.Level()))
+	}
+}
+
+func TestSetLevelFor(test *testing.T) {
+	logger := logs.New(&logs.RootLogConfig{
+		LogHandler: func(logs.LogMessage) {},
+		Loggers:    map[string]*logs.LogConfig{"payments": {Level: logs.Warn}},
+	})
+	payments := logger.ChildLogger("payments")
+
+	payments.SetLevelFor(logs.Debug, time.Hour)
+	payments.SetLevelFor(logs.Trace, 50*time.Millisecond)
+	levels := payments.Levels()
+	if payments.Level() != logs.Trace || nil == levels.Expires {
+		test.Errorf("Expected a temporary TRACE level. Found: %+v", levels)
+	}
+	waitForLevel(payments, logs.Warn)
+	if levels := payments.Levels(); levels.Configured != "WARN" || nil != levels.Expires {
+		test.Errorf("Expected the level from before the first temporary level. Found: %+v", levels)
+	}
+
+	// A witness in another tree reverts after the timer SetLevel() replaced
+	witness := logs.New(&logs.RootLogConfig{LogHandler: func(logs.LogMessage) {}})
+	payments.SetLevelFor(logs.Trace, 50*time.Millisecond)
+	witness.SetLevelFor(logs.Trace, 50*time.Millisecond)
+	payments.SetLevel(logs.Error)
+	waitForLevel(witness, logs.Info)
+	if payments.Level() != logs.Error {
+		test.Errorf("Expected SetLevel to replace the temporary level. Found: %s", logs.LogLevels.Label(payments.Level()))
+	}
+
+	payments.SetLevelFor(logs.Trace, 50*time.Millisecond)
+	witness.SetLevelFor(logs.Trace, 50*time.Millisecond)
+	logger.ApplyLevels(&logs.RootLogConfig{Loggers: map[string]*logs.LogConfig{"payments": {Level: logs.Debug}}})
+	waitForLevel(witness, logs.Info)
+	if payments.Level() != logs.Debug {
+		test.Errorf("Expected ApplyLevels to replace the temporary level. Found: %s", logs.LogLevels.Label(payments.Level()))
+	}
+}
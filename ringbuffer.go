@@ -0,0 +1,86 @@
+package gologsgo
+
+import (
+	"context"
+	"sync"
+)
+
+// RingBuffer retains the most recent records it receives, for post-mortem
+// retrieval of "what happened just before the error".
+type RingBuffer struct {
+	mu      sync.Mutex
+	records []LogMessage
+	start   int
+	count   int
+}
+
+// RingBufferHandler returns a RingBuffer that retains the last capacity records.
+// Use its LogHandler to retain the records a logger emits, or Attach() it to a
+// logger to retain records at every level, regardless of the configured levels.
+func RingBufferHandler(capacity int) *RingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer{
+		records: make([]LogMessage, capacity),
+	}
+}
+
+// LogHandler adds a record to the buffer, overwriting the oldest record once the
+// buffer is full. It is a LogHandler.
+func (r *RingBuffer) LogHandler(msg LogMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	end := (r.start + r.count) % len(r.records)
+	r.records[end] = msg
+	if r.count < len(r.records) {
+		r.count++
+	} else {
+		r.start = (r.start + 1) % len(r.records)
+	}
+}
+
+// Attach retains every record logged by the logger and its children, at every
+// level, regardless of their configured levels, until the returned Capture is
+// stopped.
+func (r *RingBuffer) Attach(logger *Logger) *Capture {
+	return logger.Capture(context.Background(), CaptureOptions{
+		Handler: r.LogHandler,
+	})
+}
+
+// Snapshot returns the retained records, oldest first, leaving them in the buffer
+func (r *RingBuffer) Snapshot() []LogMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot()
+}
+
+// Drain returns the retained records, oldest first, and empties the buffer
+func (r *RingBuffer) Drain() []LogMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := r.snapshot()
+	for i := range r.records {
+		r.records[i] = LogMessage{}
+	}
+	r.start = 0
+	r.count = 0
+	return records
+}
+
+// Len returns the number of retained records
+func (r *RingBuffer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+func (r *RingBuffer) snapshot() []LogMessage {
+	records := make([]LogMessage, r.count)
+	for i := range records {
+		records[i] = r.records[(r.start+i)%len(r.records)]
+	}
+	return records
+}
@@ -0,0 +1,42 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestRingBufferHandler(test *testing.T) {
+	root := logs.New(&logs.RootLogConfig{
+		Level:      logs.Error,
+		LogHandler: func(logs.LogMessage) {},
+	})
+
+	ring := logs.RingBufferHandler(3)
+	capture := ring.Attach(root)
+	defer capture.Stop()
+
+	root.Trace("1")
+	root.Debug("2")
+	root.Info("3")
+	root.Warn("4")
+	root.Error("5")
+
+	snapshot := ring.Snapshot()
+	if len(snapshot) != 3 || snapshot[0].Message != "3" || snapshot[2].Message != "5" {
+		test.Fatalf("Expected the last 3 records of every level. Found: %v", snapshot)
+	}
+	if ring.Len() != 3 {
+		test.Errorf("Expected Snapshot() to leave records in the buffer")
+	}
+
+	drained := ring.Drain()
+	if len(drained) != 3 || ring.Len() != 0 {
+		test.Errorf("Expected Drain() to return and remove 3 records. Found: %d, %d left", len(drained), ring.Len())
+	}
+
+	root.Info("6")
+	if snapshot := ring.Snapshot(); len(snapshot) != 1 || snapshot[0].Message != "6" {
+		test.Errorf("Expected the buffer to be reusable after Drain(). Found: %v", snapshot)
+	}
+}
@@ -0,0 +1,194 @@
+package gologsgo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateConfig configures the rotation of a RotatingFile
+type RotateConfig struct {
+	// MaxSizeMB rotates the file before a write would grow it beyond this many
+	// megabytes. 0 disables size based rotation.
+	MaxSizeMB int `json:"maxSizeMB,omitempty"`
+	// Daily rotates the file on the first write of each local day
+	Daily bool `json:"daily,omitempty"`
+	// MaxBackups is the number of rotated files kept. 0 keeps them all.
+	MaxBackups int `json:"maxBackups,omitempty"`
+	// MaxAgeDays removes rotated files older than this many days. 0 keeps them
+	// regardless of age.
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+}
+
+// backupTimeFormat is the timestamp rotated files are named with. It sorts in
+// time order.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotatingFile is an io.WriteCloser that appends to a file, renaming it with a
+// timestamp and starting a new one when it reaches a size or a new day begins.
+// Rotated files are named like app-20240131T235959.000.log for app.log, and
+// those beyond MaxBackups or older than MaxAgeDays are removed.
+type RotatingFile struct {
+	path   string
+	config RotateConfig
+	mu     sync.Mutex
+	f      *os.File
+	size   int64
+	opened time.Time
+	// rotated is the timestamp of the last backup, which the next exceeds so
+	// backups made within a millisecond aren't overwritten
+	rotated time.Time
+}
+
+// Validate checks that the settings are not negative and that something
+// triggers rotation
+func (config RotateConfig) Validate() error {
+	if config.MaxSizeMB < 0 || config.MaxBackups < 0 || config.MaxAgeDays < 0 {
+		return fmt.Errorf("Rotation settings may not be negative")
+	}
+	if config.MaxSizeMB == 0 && !config.Daily {
+		return fmt.Errorf("Rotation requires maxSizeMB or daily")
+	}
+	return nil
+}
+
+// NewRotatingFile opens path for appending, creating it if necessary
+func NewRotatingFile(path string, config RotateConfig) (*RotatingFile, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	rf := &RotatingFile{path: path, config: config}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write appends p to the file, rotating it first if necessary. Each call is
+// written to a single file, so write whole entries.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if nil == rf.f {
+		return 0, os.ErrClosed
+	}
+	if rf.due(len(p)) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.f.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close closes the file
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if nil == rf.f {
+		return nil
+	}
+	err := rf.f.Close()
+	rf.f = nil
+	return err
+}
+
+// due reports whether the file must be rotated before writing n bytes
+func (rf *RotatingFile) due(n int) bool {
+	if rf.size > 0 && rf.config.MaxSizeMB > 0 && rf.size+int64(n) > int64(rf.config.MaxSizeMB)<<20 {
+		return true
+	}
+	if rf.config.Daily {
+		y1, m1, d1 := rf.opened.Date()
+		y2, m2, d2 := time.Now().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+// open opens the file, recording its size and the time of its last write
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.f = f
+	rf.size = info.Size()
+	rf.opened = time.Now()
+	if rf.size > 0 {
+		// An existing file rotates on the day it was last written to
+		rf.opened = info.ModTime()
+	}
+	return nil
+}
+
+// rotate renames the file with a timestamp, opens a new one and removes old
+// backups
+func (rf *RotatingFile) rotate() error {
+	if err := rf.f.Close(); err != nil {
+		return err
+	}
+	rf.f = nil
+	now := time.Now().Truncate(time.Millisecond)
+	if !now.After(rf.rotated) {
+		now = rf.rotated.Add(time.Millisecond)
+	}
+	rf.rotated = now
+	ext := filepath.Ext(rf.path)
+	backup := strings.TrimSuffix(rf.path, ext) + "-" + now.Format(backupTimeFormat) + ext
+	if err := os.Rename(rf.path, backup); err != nil {
+		return err
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+	rf.prune()
+	return nil
+}
+
+// prune removes the backups beyond MaxBackups or older than MaxAgeDays
+func (rf *RotatingFile) prune() {
+	if rf.config.MaxBackups == 0 && rf.config.MaxAgeDays == 0 {
+		return
+	}
+	ext := filepath.Ext(rf.path)
+	prefix := strings.TrimSuffix(rf.path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return
+	}
+	var backups []string
+	for _, match := range matches {
+		// Skip other files that happen to share the prefix, like app-errors.log
+		if _, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	// Newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	cutoff := time.Now().AddDate(0, 0, -rf.config.MaxAgeDays)
+	for i, backup := range backups {
+		if rf.config.MaxBackups > 0 && i >= rf.config.MaxBackups {
+			os.Remove(backup)
+			continue
+		}
+		if rf.config.MaxAgeDays > 0 {
+			if info, err := os.Stat(backup); err == nil && info.ModTime().Before(cutoff) {
+				os.Remove(backup)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestRotatingFile(test *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	ioutil.WriteFile(filepath.Join(dir, "app-errors.log"), []byte("unrelated"), 0644)
+
+	rf, err := logs.NewRotatingFile(path, logs.RotateConfig{MaxSizeMB: 1, MaxBackups: 2})
+	if err != nil {
+		test.Fatal(err)
+	}
+	entry := bytes.Repeat([]byte("x"), 400<<10)
+	for i := 0; i < 10; i++ {
+		if _, err := rf.Write(entry); err != nil {
+			test.Fatal(err)
+		}
+	}
+	if err := rf.Close(); err != nil {
+		test.Fatal(err)
+	}
+	if _, err := rf.Write(entry); err == nil {
+		test.Error("Expected an error writing after Close")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != 2*int64(len(entry)) {
+		test.Errorf("Expected the current file to hold the last 2 entries. Found: %v, %v", info, err)
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(backups) != 3 {
+		test.Errorf("Expected 2 backups and the unrelated file. Found: %v", backups)
+	}
+
+	if _, err := logs.NewRotatingFile(path, logs.RotateConfig{}); err == nil {
+		test.Error("Expected an error without a size or daily rotation")
+	}
+}
@@ -0,0 +1,108 @@
+package gologsgo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LevelRule sets the level of the loggers whose names match a regular
+// expression. Rules are listed in RootLogConfig.Rules, and are evaluated in
+// order when a child logger is created: the first rule that matches sets its
+// level, so list specific rules before general ones. For example, to log SQL at
+// DEBUG wherever it is logged:
+//
+//	"rules": [{"match": "\\.sql$", "level": "DEBUG"}]
+//
+// Match is matched against the dotted path of a logger below the root Logger,
+// such as "main.db.sql". A level set for a logger in Loggers takes precedence
+// over rules; a logger that no rule matches inherits its parent's level.
+type LevelRule struct {
+	Match string   `json:"match"`
+	Level LogLevel `json:"level"`
+}
+
+// levelRule is a LevelRule with its expression compiled
+type levelRule struct {
+	match *regexp.Regexp
+	level LogLevel
+}
+
+// compileRules compiles rules, returning those that are valid and an error
+// describing the first that isn't
+func compileRules(rules []LevelRule) ([]levelRule, error) {
+	var compiled []levelRule
+	var err error
+	for i, rule := range rules {
+		c, e := compileRule(rule)
+		if e != nil {
+			if nil == err {
+				err = fmt.Errorf("Rule %d: %s", i, e)
+			}
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, err
+}
+
+// compileRule compiles the expression of a rule
+func compileRule(rule LevelRule) (levelRule, error) {
+	if rule.Level == NotSet {
+		return levelRule{}, fmt.Errorf("A level is required")
+	}
+	re, err := regexp.Compile(rule.Match)
+	if err != nil {
+		return levelRule{}, fmt.Errorf("Invalid match: %s", err)
+	}
+	return levelRule{match: re, level: rule.Level}, nil
+}
+
+// path returns the dotted path of a Logger below its root
+func (logger *Logger) path() string {
+	var names []string
+	for l := logger; nil != l.parent; l = l.parent {
+		names = append([]string{l.name}, names...)
+	}
+	return strings.Join(names, ".")
+}
+
+// childLevel returns the level of the named child of a Logger with config: the
+// level set in config, or else that of the first matching rule, or else the
+// level of the Logger. childlock must be held.
+func (logger *Logger) childLevel(name string, config *LogConfig) LogLevel {
+	if config.Level != NotSet {
+		return config.Level
+	}
+	if rule, ok := logger.matchRule(name); ok {
+		return rule.level
+	}
+	return logger.Level()
+}
+
+// matchRule returns the first rule matching the named child of a Logger.
+// childlock must be held.
+func (logger *Logger) matchRule(name string) (levelRule, bool) {
+	if len(logger.rules) == 0 {
+		return levelRule{}, false
+	}
+	path := name
+	if nil != logger.parent {
+		path = logger.path() + "." + name
+	}
+	for _, rule := range logger.rules {
+		if rule.match.MatchString(path) {
+			return rule, true
+		}
+	}
+	return levelRule{}, false
+}
+
+// setRules replaces the rules of a Logger and its descendants. childlock must be
+// held.
+func (logger *Logger) setRules(rules []levelRule) {
+	logger.rules = rules
+	for _, child := range logger.children {
+		child.setRules(rules)
+	}
+}
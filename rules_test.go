@@ -0,0 +1,59 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestLevelRules(test *testing.T) {
+	config, err := logs.JsonConfig([]byte(`{
+		"label": "app",
+		"level": "WARN",
+		"rules": [
+			{"match": "^reports\\.sql$", "level": "ERROR"},
+			{"match": "\\.sql$", "level": "DEBUG"},
+			{"match": "^audit", "level": "TRACE"}
+		],
+		"loggers": {"billing": {"loggers": {"sql": {"level": "INFO"}}}}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	config.LogHandler = func(logs.LogMessage) {}
+	logger := logs.New(config)
+
+	cases := map[string]logs.LogLevel{
+		"db.sql":        logs.Debug,
+		"db.sql.conn":   logs.Debug,
+		"reports.sql":   logs.Error,
+		"billing.sql":   logs.Info,
+		"db.sqlite":     logs.Warn,
+		"audit":         logs.Trace,
+		"audit.actions": logs.Trace,
+	}
+	for name, level := range cases {
+		if found := logger.ChildLogger(name).Level(); found != level {
+			test.Errorf("Expected %s to be at %s. Found: %s", name, logs.LogLevels.Label(level), logs.LogLevels.Label(found))
+		}
+	}
+
+	logger.ApplyLevels(&logs.RootLogConfig{Rules: []logs.LevelRule{{Match: "sqlite", Level: logs.Off}}})
+	if found := logger.ChildLogger("db.sqlite").Level(); found != logs.Off {
+		test.Errorf("Expected applied rules to replace the rules. Found: %s", logs.LogLevels.Label(found))
+	}
+	if found := logger.ChildLogger("db.sql").Level(); found != logs.Warn {
+		test.Errorf("Expected db.sql to inherit the WARN level. Found: %s", logs.LogLevels.Label(found))
+	}
+}
+
+func TestLevelRulesErrors(test *testing.T) {
+	for _, data := range []string{
+		`{"rules": [{"match": "(", "level": "DEBUG"}]}`,
+		`{"rules": [{"match": "sql"}]}`,
+	} {
+		if _, err := logs.JsonConfig([]byte(data)); err == nil {
+			test.Errorf("Expected an error for %s", data)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gologsgo
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+)
+
+// SampleConfig thins entries per level. Levels that aren't listed are kept in
+// full. A level with both a rate and an interval keeps entries that pass both.
+type SampleConfig struct {
+	// Rates maps levels to the fraction of their entries that are kept, chosen at
+	// random. 0 drops every entry and 1 keeps every entry.
+	Rates map[LogLevel]float64
+	// Every maps levels to n, keeping only every nth entry
+	Every map[LogLevel]int
+}
+
+// Sample wraps handler so that only the given fraction of Trace, Debug and Info
+// entries, chosen at random, reach it. Warn and Error entries are always kept.
+// This lets verbose levels stay enabled in production without drowning sinks.
+func Sample(handler LogHandler, rate float64) LogHandler {
+	return SampleLevels(handler, SampleConfig{
+		Rates: map[LogLevel]float64{
+			Trace: rate,
+			Debug: rate,
+			Info:  rate,
+		},
+	})
+}
+
+// SampleLevels wraps handler so that entries are thinned as configured per level
+func SampleLevels(handler LogHandler, config SampleConfig) LogHandler {
+	rates := make(map[LogLevel]float64, len(config.Rates))
+	for level, rate := range config.Rates {
+		rates[level] = rate
+	}
+	every := make(map[LogLevel]int, len(config.Every))
+	counters := make(map[LogLevel]*uint64, len(config.Every))
+	for level, n := range config.Every {
+		if n > 1 {
+			every[level] = n
+			counters[level] = new(uint64)
+		}
+	}
+
+	return func(msg LogMessage) {
+		if n, ok := every[msg.Level]; ok {
+			if (atomic.AddUint64(counters[msg.Level], 1)-1)%uint64(n) != 0 {
+				return
+			}
+		}
+		if rate, ok := rates[msg.Level]; ok && rand.Float64() >= rate {
+			return
+		}
+		handler(msg)
+	}
+}
+
+// SampleOptions configures sampling in the "sample" setting of a config
+type SampleOptions struct {
+	// Rate is the fraction of entries that are kept, chosen at random, from 0
+	// (exclusive) to 1
+	Rate float64 `json:"rate,omitempty"`
+	// Every, when above 1, keeps only every nth entry
+	Every int `json:"every,omitempty"`
+	// Levels are the levels that are sampled. Defaults to TRACE, DEBUG and INFO,
+	// so warnings and errors are always kept.
+	Levels []LogLevel `json:"levels,omitempty"`
+}
+
+// Validate checks that the rate is a fraction and every is not negative
+func (o SampleOptions) Validate() error {
+	if o.Rate < 0 || o.Rate > 1 {
+		return fmt.Errorf("A sample rate must be from 0 to 1. Found: %v", o.Rate)
+	}
+	if o.Every < 0 {
+		return fmt.Errorf("A sample every may not be negative. Found: %d", o.Every)
+	}
+	return nil
+}
+
+// wrap wraps handler with the sampling configured by the options
+func (o SampleOptions) wrap(handler LogHandler) LogHandler {
+	levels := o.Levels
+	if len(levels) == 0 {
+		levels = []LogLevel{Trace, Debug, Info}
+	}
+	config := SampleConfig{}
+	for _, level := range levels {
+		if o.Rate > 0 {
+			if nil == config.Rates {
+				config.Rates = make(map[LogLevel]float64)
+			}
+			config.Rates[level] = o.Rate
+		}
+		if o.Every > 1 {
+			if nil == config.Every {
+				config.Every = make(map[LogLevel]int)
+			}
+			config.Every[level] = o.Every
+		}
+	}
+	return SampleLevels(handler, config)
+}
+
+// volumeControls wraps handler with the sampling and rate limit of a config,
+// when they are set. Sampling comes first, so sampled out entries don't count
+// against the limit.
+func volumeControls(handler LogHandler, sample *SampleOptions, rateLimitPerSec float64) LogHandler {
+	if nil != sample {
+		handler = sample.wrap(handler)
+	}
+	if rateLimitPerSec > 0 {
+		handler = RateLimit(handler, rateLimitPerSec)
+	}
+	return handler
+}
+
+// validateVolumeControls checks the sampling and rate limit of a config
+func validateVolumeControls(sample *SampleOptions, rateLimitPerSec float64) error {
+	if nil != sample {
+		if err := sample.Validate(); err != nil {
+			return err
+		}
+	}
+	if rateLimitPerSec < 0 {
+		return fmt.Errorf("A rateLimitPerSec may not be negative. Found: %v", rateLimitPerSec)
+	}
+	return nil
+}
+
+// validateLoggersVolumeControls checks the sampling and rate limits of child
+// logger configs
+func validateLoggersVolumeControls(loggers map[string]*LogConfig) error {
+	for name, config := range loggers {
+		if nil == config {
+			continue
+		}
+		if err := validateVolumeControls(config.Sample, config.RateLimitPerSec); err != nil {
+			return fmt.Errorf("Logger %q: %s", name, err)
+		}
+		if err := validateLoggersVolumeControls(config.Loggers); err != nil {
+			return fmt.Errorf("Logger %q: %s", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestSample(test *testing.T) {
+	counts := map[logs.LogLevel]int{}
+	handler := logs.Sample(func(msg logs.LogMessage) {
+		counts[msg.Level]++
+	}, 0.1)
+
+	for i := 0; i < 1000; i++ {
+		handler(logs.LogMessage{Level: logs.Debug})
+		handler(logs.LogMessage{Level: logs.Warn})
+	}
+
+	if counts[logs.Warn] != 1000 {
+		test.Errorf("Expected every Warn entry to be kept. Found: %d", counts[logs.Warn])
+	}
+	if counts[logs.Debug] < 50 || counts[logs.Debug] > 150 {
+		test.Errorf("Expected about 10%% of Debug entries to be kept. Found: %d", counts[logs.Debug])
+	}
+}
+
+func TestSampleEvery(test *testing.T) {
+	var kept []int
+	handler := logs.SampleLevels(func(msg logs.LogMessage) {
+		kept = append(kept, msg.Fields["i"].(int))
+	}, logs.SampleConfig{
+		Every: map[logs.LogLevel]int{logs.Info: 3},
+		Rates: map[logs.LogLevel]float64{logs.Trace: 0},
+	})
+
+	for i := 0; i < 7; i++ {
+		handler(logs.LogMessage{Level: logs.Info, Fields: map[string]interface{}{"i": i}})
+		handler(logs.LogMessage{Level: logs.Trace, Fields: map[string]interface{}{"i": -1}})
+	}
+
+	if len(kept) != 3 || kept[0] != 0 || kept[1] != 3 || kept[2] != 6 {
+		test.Errorf("Expected every 3rd Info entry and no Trace entries. Found: %v", kept)
+	}
+}
@@ -0,0 +1,192 @@
+package gologsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SentryHandlerConfig configures a SentryHandler
+type SentryHandlerConfig struct {
+	// HTTPHandlerConfig configures delivery. URL is derived from DSN and Encoder
+	// is ignored.
+	HTTPHandlerConfig
+	// DSN is the project's Sentry DSN
+	DSN string
+	// MinLevel is the lowest level forwarded to Sentry. Defaults to Error.
+	MinLevel LogLevel
+	// SampleRate is the fraction of events sent, between 0 and 1. Defaults to 1.
+	SampleRate float64
+	// Environment and Release are sent with every event
+	Environment string
+	Release     string
+	// Tags are sent with every event, in addition to a `logger` tag
+	Tags map[string]string
+}
+
+// SentryHandler forwards ERROR level records - with stack traces, fields as extra
+// data and the logger label as a tag - to Sentry, so exceptions get alerting
+// while normal logs stay local. It is usually combined with another handler.
+type SentryHandler struct {
+	*HTTPHandler
+	minLevel   LogLevel
+	sampleRate float64
+	mu         sync.Mutex
+	random     *rand.Rand
+}
+
+// NewSentryHandler validates the config and starts a SentryHandler. Register it
+// as a closer (or call Close()) so pending events are flushed on shutdown.
+func NewSentryHandler(config SentryHandlerConfig) (*SentryHandler, error) {
+	dsn, err := url.Parse(config.DSN)
+	if err != nil || nil == dsn.User || len(dsn.User.Username()) == 0 {
+		return nil, fmt.Errorf("Invalid Sentry DSN %q", config.DSN)
+	}
+	path := strings.Trim(dsn.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	projectID := path[slash+1:]
+	if len(projectID) == 0 {
+		return nil, fmt.Errorf("Sentry DSN %q has no project ID", config.DSN)
+	}
+	publicKey := dsn.User.Username()
+
+	endpoint := url.URL{
+		Scheme: dsn.Scheme,
+		Host:   dsn.Host,
+		Path:   "/" + path[:slash+1] + "api/" + projectID + "/envelope/",
+	}
+	config.URL = endpoint.String()
+	config.Headers = cloneHeader(config.Headers)
+	config.Headers.Set("Content-Type", "application/x-sentry-envelope")
+	config.Headers.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=go-logs-go, sentry_key=%s", publicKey))
+	// Sentry accepts one event per envelope
+	config.MaxBatchSize = 1
+
+	if config.MinLevel == NotSet {
+		config.MinLevel = Error
+	}
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = 1
+	}
+
+	h, err := NewHTTPHandler(config.HTTPHandlerConfig)
+	if err != nil {
+		return nil, err
+	}
+	dsnString := config.DSN
+	h.body = func(batch []LogMessage) ([]byte, error) {
+		return sentryEnvelope(batch[0], dsnString, config)
+	}
+
+	return &SentryHandler{
+		HTTPHandler: h,
+		minLevel:    config.MinLevel,
+		sampleRate:  config.SampleRate,
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// LogHandler captures the stack of records at or above MinLevel and queues them
+// for Sentry, subject to sampling. It is a LogHandler.
+func (h *SentryHandler) LogHandler(msg LogMessage) {
+	if msg.Level < h.minLevel {
+		return
+	}
+	if h.sampleRate < 1 {
+		h.mu.Lock()
+		sampled := h.random.Float64() < h.sampleRate
+		h.mu.Unlock()
+		if !sampled {
+			return
+		}
+	}
+	if nil == msg.Stack {
+		msg.Stack = CaptureStack(0)
+	}
+	h.HTTPHandler.LogHandler(msg)
+}
+
+// sentryLevels maps log levels to Sentry's levels
+var sentryLevels = map[LogLevel]string{
+	Trace: "debug",
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warning",
+	Error: "error",
+}
+
+// sentryEnvelope builds an envelope containing a single event
+func sentryEnvelope(msg LogMessage, dsn string, config SentryHandlerConfig) ([]byte, error) {
+	eventID := strings.Replace(NewID(), "-", "", -1)
+
+	tags := make(map[string]string, len(config.Tags)+1)
+	for k, v := range config.Tags {
+		tags[k] = v
+	}
+	if len(msg.Logger) > 0 {
+		tags["logger"] = msg.Logger
+	}
+
+	// Sentry expects frames oldest first
+	frames := make([]map[string]interface{}, len(msg.Stack))
+	for i, frame := range msg.Stack {
+		frames[len(frames)-1-i] = map[string]interface{}{
+			"function": frame.Function,
+			"abs_path": frame.File,
+			"lineno":   frame.Line,
+			"in_app":   true,
+		}
+	}
+
+	level, ok := sentryLevels[msg.Level]
+	if !ok {
+		level = "error"
+	}
+
+	event := map[string]interface{}{
+		"event_id":  eventID,
+		"timestamp": msg.Time.UTC().Format(time.RFC3339Nano),
+		"platform":  "go",
+		"level":     level,
+		"logger":    msg.Logger,
+		"logentry": map[string]string{
+			"message":   msg.Format,
+			"formatted": msg.Message,
+		},
+		"tags": tags,
+		"exception": map[string]interface{}{
+			"values": []interface{}{map[string]interface{}{
+				"type":       strings.ToUpper(msg.LevelLabel),
+				"value":      msg.Message,
+				"stacktrace": map[string]interface{}{"frames": frames},
+			}},
+		},
+	}
+	if len(msg.Fields) > 0 {
+		event["extra"] = msg.Fields
+	}
+	if len(config.Environment) > 0 {
+		event["environment"] = config.Environment
+	}
+	if len(config.Release) > 0 {
+		event["release"] = config.Release
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, part := range []interface{}{
+		map[string]string{"event_id": eventID, "dsn": dsn},
+		map[string]string{"type": "event"},
+		event,
+	} {
+		if err := encoder.Encode(part); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package gologsgo_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestSentryHandler(test *testing.T) {
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" || !strings.Contains(r.Header.Get("X-Sentry-Auth"), "sentry_key=public") {
+			test.Errorf("Unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		scanner := bufio.NewScanner(r.Body)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 3 {
+			test.Errorf("Expected an envelope header, item header and event. Found: %v", lines)
+			return
+		}
+		var event map[string]interface{}
+		json.Unmarshal([]byte(lines[2]), &event)
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewSentryHandler(logs.SentryHandlerConfig{
+		DSN:         strings.Replace(server.URL, "http://", "http://public@", 1) + "/42",
+		Environment: "test",
+	})
+	if nil != err {
+		test.Fatalf("Error creating SentryHandler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: handler.LogHandler,
+	})
+	logger.SetField("order", "1234")
+	logger.Warn("Not sent to Sentry")
+	logger.Error("Payment failed")
+	handler.Close()
+
+	if len(events) != 1 {
+		test.Fatalf("Expected 1 event. Found: %d", len(events))
+	}
+	event := events[0]
+	if event["level"] != "error" || event["environment"] != "test" {
+		test.Errorf("Unexpected event: %v", event)
+	}
+	if tags, _ := event["tags"].(map[string]interface{}); tags["logger"] != "main" {
+		test.Errorf("Expected a logger tag. Found: %v", event["tags"])
+	}
+	if extra, _ := event["extra"].(map[string]interface{}); extra["order"] != "1234" {
+		test.Errorf("Expected fields as extra data. Found: %v", event["extra"])
+	}
+
+	exception := event["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	frames := exception["stacktrace"].(map[string]interface{})["frames"].([]interface{})
+	last := frames[len(frames)-1].(map[string]interface{})
+	if last["function"] != "github.com/big-squid/go-logs-go_test.TestSentryHandler" {
+		test.Errorf("Expected the most recent frame to be the log call. Found: %v", last)
+	}
+}
@@ -0,0 +1,59 @@
+package gologsgo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// EscapeLine escapes newlines, carriage returns and other control characters in s
+// so that it is guaranteed to be a single line. Newlines and carriage returns
+// become \n and \r, and other control characters (including the Unicode line
+// and paragraph separators) become \xNN or \uNNNN. Tabs are kept.
+func EscapeLine(s string) string {
+	clean := true
+	for _, r := range s {
+		if needsEscape(r) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case !needsEscape(r):
+			b.WriteRune(r)
+		case r < 0x100:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	return b.String()
+}
+
+func needsEscape(r rune) bool {
+	return r != '\t' && (unicode.IsControl(r) || r == '\u2028' || r == '\u2029')
+}
+
+// SingleLine wraps encoder so that each encoded entry is a single line, as many
+// shippers (such as Docker's json-file driver and journald) require. It is only
+// needed for encoders that may write raw newlines, such as TextEncoder; JSON
+// encoders already escape them.
+func SingleLine(encoder Encoder) Encoder {
+	return func(msg LogMessage) ([]byte, error) {
+		data, err := encoder(msg)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(EscapeLine(string(data))), nil
+	}
+}
@@ -0,0 +1,47 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestEscapeLine(test *testing.T) {
+	for s, expected := range map[string]string{
+		"plain\ttext":           "plain\ttext",
+		"two\nlines\r\n":        `two\nlines\r\n`,
+		"bell\a and \x1b[31m":   `bell\x07 and \x1b[31m`,
+		"para\u2029graph\u0085": `para\u2029graph\x85`,
+	} {
+		if actual := logs.EscapeLine(s); actual != expected {
+			test.Errorf("Expected %q for %q. Found: %q", expected, s, actual)
+		}
+	}
+}
+
+func TestSingleLine(test *testing.T) {
+	var buf bytes.Buffer
+	handler := logs.LeveledLogHandler{
+		Format:       "%s [%s]: %s",
+		RootFormat:   "%s: %s",
+		Logger:       log.New(&buf, "", 0),
+		ExpandFields: true,
+		SingleLine:   true,
+	}
+	handler.LogHandler(logs.LogMessage{
+		Level:      logs.Error,
+		LevelLabel: "error",
+		Message:    "Request failed:\ntimeout",
+		Fields:     map[string]interface{}{"body": "a\nb"},
+	})
+	if buf.String() != "ERROR: Request failed:\\ntimeout body=a\\nb\n" {
+		test.Errorf("Unexpected output: %q", buf.String())
+	}
+
+	data, _ := logs.SingleLine(logs.TextEncoder)(logs.LogMessage{LevelLabel: "info", Message: "a\nb"})
+	if bytes.Contains(data, []byte("\n")) {
+		test.Errorf("Expected a single line. Found: %q", data)
+	}
+}
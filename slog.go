@@ -0,0 +1,178 @@
+package gologsgo
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// SlogHandler returns a slog.Handler that logs through logger, so code written
+// for log/slog shares the level tree, hooks and handlers of this package:
+//
+//	slog.SetDefault(slog.New(logs.SlogHandler(logger.ChildLogger("legacy"))))
+//
+// Records are filtered by the level of logger, mapping slog.LevelDebug and
+// below to Debug (and below slog.LevelDebug to Trace), and slog.LevelInfo,
+// slog.LevelWarn and slog.LevelError to Info, Warn and Error. Attributes become
+// Fields, added to those of logger, and groups prefix the keys of their
+// attributes with the group name and a dot, such as "request.method".
+func SlogHandler(logger *Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+// slogHandler is the slog.Handler returned by SlogHandler()
+type slogHandler struct {
+	logger *Logger
+	// fields are the attributes added with WithAttrs()
+	fields map[string]interface{}
+	// prefix is the prefix of the groups opened with WithGroup()
+	prefix string
+}
+
+// Enabled reports whether the Logger logs records of level
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return fromSlogLevel(level) >= h.logger.Level() || atomic.LoadInt32(&activeCaptures) > 0
+}
+
+// Handle logs a record
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	level := fromSlogLevel(r.Level)
+	capturing := atomic.LoadInt32(&activeCaptures) > 0
+	if level < h.logger.Level() && !capturing {
+		atomic.AddUint64(&filteredCount, 1)
+		return nil
+	}
+
+	fields := h.logger.Fields()
+	if len(h.fields) > 0 || r.NumAttrs() > 0 {
+		if nil == fields {
+			fields = make(map[string]interface{}, len(h.fields)+r.NumAttrs())
+		}
+		for k, v := range h.fields {
+			fields[k] = v
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			addSlogAttr(fields, h.prefix, a)
+			return true
+		})
+		if len(fields) == 0 {
+			fields = nil
+		}
+	}
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	h.logger.emit(LogMessage{
+		Level:      level,
+		LevelLabel: LogLevels.Label(level),
+		Logger:     h.logger.rendered,
+		Message:    r.Message,
+		Format:     r.Message,
+		Time:       t,
+		Fields:     fields,
+	}, capturing)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to each record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := make(map[string]interface{}, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, a := range attrs {
+		addSlogAttr(fields, h.prefix, a)
+	}
+	return &slogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+}
+
+// WithGroup returns a handler that prefixes the keys of later attributes with
+// name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return &slogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// addSlogAttr adds an attribute to fields, flattening groups into dotted keys
+func addSlogAttr(fields map[string]interface{}, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		// A group without a key is inlined
+		if len(a.Key) > 0 {
+			prefix += a.Key + "."
+		}
+		for _, member := range a.Value.Group() {
+			addSlogAttr(fields, prefix, member)
+		}
+		return
+	}
+	fields[prefix+a.Key] = a.Value.Any()
+}
+
+// SlogBackend returns a LogHandler that forwards each LogMessage to l, so a
+// codebase that already configures log/slog can keep its slog handlers while
+// using the level tree of this package. Entries are passed to the handler of l
+// at the slog level of their level, with the label of their logger as the
+// "logger" attribute and their Fields as attributes.
+func SlogBackend(l *slog.Logger) LogHandler {
+	handler := l.Handler()
+	return func(msg LogMessage) {
+		ctx := context.Background()
+		level := toSlogLevel(msg.Level)
+		if !handler.Enabled(ctx, level) {
+			return
+		}
+		r := slog.NewRecord(msg.Time, level, msg.Message, 0)
+		if len(msg.Logger) > 0 {
+			r.AddAttrs(slog.String("logger", msg.Logger))
+		}
+		for _, k := range fieldKeys(msg.Fields) {
+			r.AddAttrs(slog.Any(k, msg.Fields[k]))
+		}
+		handler.Handle(ctx, r)
+	}
+}
+
+// fromSlogLevel converts a slog level to the level of this package it falls in
+func fromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return Trace
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warn
+	default:
+		return Error
+	}
+}
+
+// toSlogLevel converts a level of this package to a slog level. Trace is
+// slog.LevelDebug-4.
+func toSlogLevel(level LogLevel) slog.Level {
+	switch {
+	case level <= Trace:
+		return slog.LevelDebug - 4
+	case level <= Debug:
+		return slog.LevelDebug
+	case level <= Info:
+		return slog.LevelInfo
+	case level <= Warn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,64 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestSlogHandler(test *testing.T) {
+	var entries []logs.LogMessage
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "app",
+		Level:      logs.Info,
+		LogHandler: func(msg logs.LogMessage) { entries = append(entries, msg) },
+		Loggers:    map[string]*logs.LogConfig{"legacy": {Level: logs.Debug}},
+	})
+
+	root := slog.New(logs.SlogHandler(logger))
+	root.Debug("Filtered")
+	root.Warn("Disk low", "free", 10)
+
+	legacy := slog.New(logs.SlogHandler(logger.ChildLogger("legacy")))
+	legacy.With("service", "billing").WithGroup("request").Debug("Handled", slog.Group("client", "ip", "10.0.0.1"), "method", "GET")
+
+	if len(entries) != 2 {
+		test.Fatalf("Expected 2 entries. Found: %+v", entries)
+	}
+	if entries[0].Level != logs.Warn || entries[0].Message != "Disk low" || entries[0].Fields["free"] != int64(10) {
+		test.Errorf("Unexpected entry: %+v", entries[0])
+	}
+	expected := map[string]interface{}{"service": "billing", "request.method": "GET", "request.client.ip": "10.0.0.1"}
+	if entries[1].Level != logs.Debug || entries[1].Logger != "app.legacy" || len(entries[1].Fields) != len(expected) {
+		test.Errorf("Unexpected entry: %+v", entries[1])
+	}
+	for k, v := range expected {
+		if entries[1].Fields[k] != v {
+			test.Errorf("Expected field %s to be %v. Found: %v", k, v, entries[1].Fields[k])
+		}
+	}
+}
+
+func TestSlogBackend(test *testing.T) {
+	var buffer bytes.Buffer
+	backend := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "app",
+		Level:      logs.Trace,
+		LogHandler: logs.SlogBackend(backend),
+	})
+	logger.SetField("region", "eu")
+	logger.Debug("Filtered by slog")
+	logger.ChildLogger("db").Error("Query failed")
+
+	out := buffer.String()
+	if strings.Contains(out, "Filtered") {
+		test.Errorf("Expected the slog handler's level to apply. Found: %s", out)
+	}
+	if !strings.Contains(out, `level=ERROR msg="Query failed" logger=app.db region=eu`) {
+		test.Errorf("Expected the entry to be forwarded. Found: %s", out)
+	}
+}
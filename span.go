@@ -0,0 +1,75 @@
+package gologsgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanFunc starts a tracing span named name, as a child of any span already in
+// ctx. It returns a context carrying the new span and a function that ends it.
+// See the github.com/big-squid/go-logs-go/otel package for an OpenTelemetry
+// implementation.
+type SpanFunc func(ctx context.Context, name string) (context.Context, func())
+
+var spanFunc SpanFunc
+var spanlock sync.RWMutex
+
+// SetSpanFunc registers the SpanFunc used by Logger.StartSpan(). Without one,
+// StartSpan still creates a child logger and logs the span's duration, but no
+// tracing span is started.
+func SetSpanFunc(fn SpanFunc) {
+	spanlock.Lock()
+	defer spanlock.Unlock()
+	spanFunc = fn
+}
+
+// StartSpan unifies tracing and the logger hierarchy. It starts a tracing span
+// (if a SpanFunc has been registered) and returns a context carrying it, a
+// ChildLogger named after the span, and a function that ends the span and logs
+// its duration at the DEBUG level. The returned function should be deferred.
+// Dots, and the separator of the LabelFormat, are replaced with underscores in
+// the child's name, so the span is a single child rather than a path of
+// descendants. Like ChildLogger, StartSpan panics if name is empty.
+func (logger *Logger) StartSpan(ctx context.Context, name string) (context.Context, *Logger, func()) {
+	if len(name) < 1 {
+		panic(fmt.Errorf("Spans require a name"))
+	}
+
+	spanlock.RLock()
+	fn := spanFunc
+	spanlock.RUnlock()
+
+	endSpan := func() {}
+	if nil != fn {
+		ctx, endSpan = fn(ctx, name)
+	}
+
+	child := logger.ChildLogger(logger.spanLabel(name))
+	start := time.Now()
+	child.Trace("Span %s started", name)
+
+	var once sync.Once
+	end := func() {
+		once.Do(func() {
+			endSpan()
+			child.Debug("Span %s ended after %s", name, time.Since(start))
+		})
+	}
+
+	return ctx, child, end
+}
+
+// spanLabel returns the name of the child logger of a span, without the
+// characters ChildLogger would split it at
+func (logger *Logger) spanLabel(name string) string {
+	if nil != logger.base {
+		logger = logger.base
+	}
+	if nil != logger.labelFormat && len(logger.labelFormat.Separator) > 0 {
+		name = strings.Replace(name, logger.labelFormat.Separator, "_", -1)
+	}
+	return strings.Replace(name, ".", "_", -1)
+}
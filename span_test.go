@@ -0,0 +1,69 @@
+package gologsgo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+type spanKey struct{}
+
+func TestStartSpan(test *testing.T) {
+	var ended []string
+	logs.SetSpanFunc(func(ctx context.Context, name string) (context.Context, func()) {
+		return context.WithValue(ctx, spanKey{}, name), func() {
+			ended = append(ended, name)
+		}
+	})
+	defer logs.SetSpanFunc(nil)
+
+	var messages []logs.LogMessage
+	root := logs.New(&logs.RootLogConfig{
+		Label: "main",
+		Level: logs.Debug,
+		LogHandler: func(msg logs.LogMessage) {
+			messages = append(messages, msg)
+		},
+	})
+
+	ctx, spanLogger, end := root.StartSpan(context.Background(), "charge")
+	if ctx.Value(spanKey{}) != "charge" {
+		test.Error("Expected the context returned by StartSpan to carry the span")
+	}
+	if spanLogger.Label() != "main.charge" {
+		test.Errorf("Expected span logger label to be main.charge. Found: %s", spanLogger.Label())
+	}
+	spanLogger.Info("Charging card")
+	end()
+	end()
+
+	if len(ended) != 1 {
+		test.Errorf("Expected the span to be ended once. Ended: %d", len(ended))
+	}
+	if len(messages) != 2 || !strings.HasPrefix(messages[1].Message, "Span charge ended after ") {
+		test.Errorf("Expected a span end message. Found: %v", messages)
+	}
+}
+
+func TestStartSpanNames(test *testing.T) {
+	root := logs.New(&logs.RootLogConfig{
+		Label:      "main",
+		LogHandler: func(logs.LogMessage) {},
+	})
+
+	// Dotted names are a single child rather than a path of descendants
+	_, spanLogger, end := root.StartSpan(context.Background(), "GET /v1.2/users")
+	end()
+	if spanLogger.Label() != "main.GET /v1_2/users" {
+		test.Errorf("Expected the dots in the span name to be replaced. Found: %s", spanLogger.Label())
+	}
+
+	defer func() {
+		if recover() == nil {
+			test.Error("Expected a panic for an empty span name")
+		}
+	}()
+	root.StartSpan(context.Background(), "")
+}
@@ -0,0 +1,247 @@
+package gologsgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const spoolExt = ".spool"
+
+// corruptExt is added to the name of a spool file that can't be decoded, which
+// sets it aside for inspection
+const corruptExt = ".corrupt"
+
+// Spool is a dead-letter directory for serialized entries that a handler could
+// not deliver. Set it as NetworkHandlerConfig.Spool or HTTPHandlerConfig.Spool and
+// the handler spools what it would otherwise drop, then replays it, oldest first,
+// once the sink recovers. Because entries are on disk, they also survive a restart.
+type Spool struct {
+	dir string
+	// mu guards the files of the spool. It is never held while entries are
+	// delivered, so a slow sink doesn't block writers.
+	mu sync.Mutex
+	// replaying is held by Replay(), so entries are only delivered once
+	replaying sync.Mutex
+	seq       uint64
+	maxSize   int64
+}
+
+// NewSpool returns a Spool that keeps files in dir, creating it if necessary
+func NewSpool(dir string) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("Unable to create spool directory: %s", err)
+	}
+	return &Spool{dir: dir}, nil
+}
+
+// SetMaxSize limits the total size of the spool in bytes. Writes that would exceed
+// it fail. Zero, the default, is unlimited.
+func (s *Spool) SetMaxSize(bytes int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxSize = bytes
+}
+
+// Write adds entries to the spool as a single file
+func (s *Spool) Write(entries ...[]byte) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSize > 0 {
+		size := s.size()
+		for _, entry := range entries {
+			size += int64(len(entry)) + 4
+		}
+		if size > s.maxSize {
+			return fmt.Errorf("Spool %s is full", s.dir)
+		}
+	}
+
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1000000, spoolExt)
+	return s.writeFile(filepath.Join(s.dir, name), entries)
+}
+
+// Replay passes each spooled entry, oldest first, to deliver, removing entries as
+// they are delivered. It stops at the first error, which is returned, leaving the
+// remaining entries in the spool. The number of entries delivered is returned.
+// A file that can't be decoded is renamed with a .corrupt extension, reported to
+// LastResortWriter and skipped; a file that can't be read is left in place and
+// the error is returned.
+func (s *Spool) Replay(deliver func([]byte) error) (int, error) {
+	s.replaying.Lock()
+	defer s.replaying.Unlock()
+
+	s.mu.Lock()
+	files := s.files()
+	s.mu.Unlock()
+
+	delivered := 0
+	for _, file := range files {
+		// Files are renamed into place complete, and only Replay() changes them
+		// afterwards, so they can be read without the lock
+		entries, err := readSpoolFile(file)
+		if _, corrupt := err.(*spoolFormatError); corrupt {
+			// A corrupt file can never be replayed, but what it holds may still
+			// be recovered by hand
+			s.quarantine(file, err)
+			continue
+		}
+		if err != nil {
+			return delivered, err
+		}
+		for i, entry := range entries {
+			if err := deliver(entry); err != nil {
+				if i > 0 {
+					s.mu.Lock()
+					writeErr := s.writeFile(file, entries[i:])
+					s.mu.Unlock()
+					if writeErr != nil {
+						lastResortWarning("Unable to remove %d delivered entries from %s: %s. They will be replayed again", i, file, writeErr)
+					}
+				}
+				return delivered, err
+			}
+			delivered++
+		}
+		s.remove(file)
+	}
+	return delivered, nil
+}
+
+// remove removes a file that has been replayed
+func (s *Spool) remove(file string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	os.Remove(file)
+}
+
+// quarantine renames a spool file that can't be decoded so it is no longer
+// replayed, and reports it
+func (s *Spool) quarantine(file string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if renameErr := os.Rename(file, file+corruptExt); renameErr != nil {
+		lastResortWarning("%s. Unable to set it aside: %s", err, renameErr)
+		return
+	}
+	lastResortWarning("%s. Moved it to %s", err, file+corruptExt)
+}
+
+// Len returns the number of files in the spool
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.files())
+}
+
+// files returns the spool files, oldest first
+func (s *Spool) files() []string {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), spoolExt) {
+			files = append(files, filepath.Join(s.dir, info.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func (s *Spool) size() int64 {
+	var size int64
+	for _, file := range s.files() {
+		if info, err := os.Stat(file); nil == err {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// writeFile writes length prefixed entries to a temporary file and renames it into
+// place, so a partially written file is never replayed
+func (s *Spool) writeFile(path string, entries [][]byte) error {
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	for _, entry := range entries {
+		w.Write(LengthPrefixFraming(entry))
+	}
+	err = w.Flush()
+	if closeErr := tmp.Close(); nil == err {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// spoolFormatError is returned by readSpoolFile() for a file that isn't made of
+// length prefixed entries, so it can never be replayed
+type spoolFormatError struct {
+	path   string
+	reason string
+}
+
+func (e *spoolFormatError) Error() string {
+	return fmt.Sprintf("Spool file %s is corrupt: %s", e.path, e.reason)
+}
+
+func readSpoolFile(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(f)
+	remaining := info.Size()
+	var entries [][]byte
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err == io.EOF {
+			return entries, nil
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, &spoolFormatError{path, "truncated length"}
+		} else if err != nil {
+			return nil, err
+		}
+		remaining -= 4
+		// The length is checked before anything is allocated for it
+		if int64(size) > remaining {
+			return nil, &spoolFormatError{path, fmt.Sprintf("entry of %d bytes with %d bytes left", size, remaining)}
+		}
+		entry := make([]byte, size)
+		if _, err := io.ReadFull(r, entry); err == io.ErrUnexpectedEOF || err == io.EOF {
+			return nil, &spoolFormatError{path, "truncated entry"}
+		} else if err != nil {
+			return nil, err
+		}
+		remaining -= int64(size)
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,210 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestSpool(test *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spool, err := logs.NewSpool(dir)
+	if err != nil {
+		test.Fatal(err)
+	}
+	spool.Write([]byte("a"), []byte("b"), []byte("c"))
+	spool.Write([]byte("d"))
+
+	var replayed []string
+	delivered, err := spool.Replay(func(entry []byte) error {
+		if string(entry) == "c" {
+			return errors.New("down again")
+		}
+		replayed = append(replayed, string(entry))
+		return nil
+	})
+	if err == nil || delivered != 2 || strings.Join(replayed, "") != "ab" {
+		test.Fatalf("Expected replay to stop at the failure. Found: %d %v %v", delivered, replayed, err)
+	}
+
+	replayed = nil
+	delivered, err = spool.Replay(func(entry []byte) error {
+		replayed = append(replayed, string(entry))
+		return nil
+	})
+	if err != nil || delivered != 2 || strings.Join(replayed, "") != "cd" {
+		test.Errorf("Expected the remaining entries to be replayed in order. Found: %d %v %v", delivered, replayed, err)
+	}
+	if spool.Len() != 0 {
+		test.Errorf("Expected replayed files to be removed. Found: %d", spool.Len())
+	}
+
+	spool.SetMaxSize(10)
+	if err := spool.Write([]byte("this entry is too large")); err == nil {
+		test.Errorf("Expected a write beyond the max size to fail")
+	}
+}
+
+func TestHTTPHandlerSpool(test *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	spool, err := logs.NewSpool(dir)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	down := true
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, string(body))
+	}))
+	defer server.Close()
+
+	handler, err := logs.NewHTTPHandler(logs.HTTPHandlerConfig{
+		URL:         server.URL,
+		MaxRetries:  -1,
+		MaxBatchAge: 10 * time.Millisecond,
+		Spool:       spool,
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	handler.LogHandler(logs.LogMessage{Level: logs.Error, LevelLabel: "ERROR", Message: "during the outage"})
+	deadline := time.Now().Add(time.Second)
+	for spool.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if spool.Len() != 1 {
+		test.Fatalf("Expected the failed batch to be spooled")
+	}
+
+	mu.Lock()
+	down = false
+	mu.Unlock()
+	handler.LogHandler(logs.LogMessage{Level: logs.Info, LevelLabel: "INFO", Message: "after recovery"})
+	handler.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || !strings.Contains(received[1], "during the outage") {
+		test.Errorf("Expected the spooled batch to be replayed after recovery. Found: %v", received)
+	}
+	if handler.Dropped() != 0 || spool.Len() != 0 {
+		test.Errorf("Expected nothing to be lost. Found %d dropped, %d spooled", handler.Dropped(), spool.Len())
+	}
+}
+
+func TestNetworkHandlerSpoolsOverflow(test *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	spool, err := logs.NewSpool(dir)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// Nothing listens on the address, and the backoff outlasts the test, so the
+	// queue overflows
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		test.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	handler, err := logs.NewNetworkHandler(logs.NetworkHandlerConfig{
+		Network:      "tcp",
+		Address:      address,
+		QueueSize:    1,
+		MinBackoff:   time.Minute,
+		DrainTimeout: 10 * time.Millisecond,
+		Spool:        spool,
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		handler.LogHandler(logs.LogMessage{Level: logs.Info, Message: fmt.Sprintf("entry %d", i)})
+	}
+	if spool.Len() == 0 {
+		test.Error("Expected the entries pushed out of the queue to be spooled")
+	}
+	handler.Close()
+
+	replayed := 0
+	spool.Replay(func([]byte) error {
+		replayed++
+		return nil
+	})
+	if replayed != 5 || handler.Dropped() != 0 {
+		test.Errorf("Expected every entry to be spooled. Found %d spooled and %d dropped", replayed, handler.Dropped())
+	}
+}
+
+func TestSpoolSetsAsideCorruptFiles(test *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var warnings bytes.Buffer
+	defer func(w io.Writer) { logs.LastResortWriter = w }(logs.LastResortWriter)
+	logs.LastResortWriter = &warnings
+
+	spool, err := logs.NewSpool(dir)
+	if err != nil {
+		test.Fatal(err)
+	}
+	spool.Write([]byte("kept"))
+	// Sorts before the file written above, and claims an entry of 4GB
+	corrupt := filepath.Join(dir, "00000000000000000000-000000.spool")
+	if err := ioutil.WriteFile(corrupt, []byte{0xff, 0xff, 0xff, 0xff, 'x'}, 0600); err != nil {
+		test.Fatal(err)
+	}
+
+	var replayed []string
+	delivered, err := spool.Replay(func(entry []byte) error {
+		replayed = append(replayed, string(entry))
+		return nil
+	})
+	if err != nil || delivered != 1 || strings.Join(replayed, "") != "kept" {
+		test.Errorf("Expected the corrupt file to be skipped. Found: %d %v %v", delivered, replayed, err)
+	}
+	if _, err := os.Stat(corrupt + ".corrupt"); err != nil {
+		test.Errorf("Expected the corrupt file to be set aside: %s", err)
+	}
+	if !strings.Contains(warnings.String(), "is corrupt") {
+		test.Errorf("Expected the corrupt file to be reported. Found: %q", warnings.String())
+	}
+}
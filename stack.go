@@ -0,0 +1,169 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// packagePrefix identifies functions in this package, which are left out of
+// captured stacks
+const packagePrefix = "github.com/big-squid/go-logs-go."
+
+// StackFrame is a single frame of a captured stack trace
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// CaptureStack returns the calling goroutine's stack, most recent call first,
+// leaving out frames in this package (so a stack captured by a handler starts at
+// the log call) and skipping a further skip frames.
+func CaptureStack(skip int) []StackFrame {
+	pc := make([]uintptr, 64)
+	// 0 is runtime.Callers, 1 is CaptureStack
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	var stack []StackFrame
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			if skip > 0 {
+				skip--
+			} else {
+				stack = append(stack, StackFrame{
+					Function: frame.Function,
+					File:     frame.File,
+					Line:     frame.Line,
+				})
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// formatStack renders a stack in the layout of Go's panic output
+func formatStack(stack []StackFrame) string {
+	var b strings.Builder
+	for _, frame := range stack {
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// StackStyle is a layout StackFormat renders stacks in
+type StackStyle string
+
+const (
+	// StackCompact renders a stack on a single line, as "function (file:line)"
+	// frames separated by " < "
+	StackCompact StackStyle = "compact"
+	// StackIndented renders each frame on its own indented line below the entry
+	StackIndented StackStyle = "indented"
+	// StackJSON renders a stack as a JSON array of frames
+	StackJSON StackStyle = "json"
+)
+
+// StackFormat configures how a handler renders the captured stack of an entry
+type StackFormat struct {
+	// Style defaults to StackIndented
+	Style StackStyle `json:"style,omitempty"`
+	// MaxFrames, when positive, limits the frames rendered, most recent first
+	MaxFrames int `json:"maxFrames,omitempty"`
+	// TrimPackages shortens package paths to their last element
+	// ("net/http.HandlerFunc.ServeHTTP" becomes "http.HandlerFunc.ServeHTTP")
+	// and files to their base name
+	TrimPackages bool `json:"trimPackages,omitempty"`
+}
+
+// Validate returns an error if the Style is unknown
+func (f StackFormat) Validate() error {
+	switch f.Style {
+	case "", StackCompact, StackIndented, StackJSON:
+		return nil
+	}
+	return fmt.Errorf("Unknown stack style %q. Use %q, %q or %q", f.Style, StackCompact, StackIndented, StackJSON)
+}
+
+// Render renders a stack. Compact and JSON stacks are a single line; indented
+// stacks start with a newline.
+func (f StackFormat) Render(stack []StackFrame) string {
+	if f.MaxFrames > 0 && len(stack) > f.MaxFrames {
+		stack = stack[:f.MaxFrames]
+	}
+	if f.TrimPackages {
+		trimmed := make([]StackFrame, len(stack))
+		for i, frame := range stack {
+			trimmed[i] = StackFrame{
+				Function: trimPackage(frame.Function),
+				File:     path.Base(frame.File),
+				Line:     frame.Line,
+			}
+		}
+		stack = trimmed
+	}
+
+	switch f.Style {
+	case StackCompact:
+		frames := make([]string, len(stack))
+		for i, frame := range stack {
+			frames[i] = fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		return strings.Join(frames, " < ")
+	case StackJSON:
+		if nil == stack {
+			stack = []StackFrame{}
+		}
+		data, _ := json.Marshal(stack)
+		return string(data)
+	default:
+		var b strings.Builder
+		for _, frame := range stack {
+			fmt.Fprintf(&b, "\n    at %s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		return b.String()
+	}
+}
+
+// appendStack renders a stack to be appended to a text entry. Indented stacks
+// are rendered compact when the entry must be a single line.
+func appendStack(format StackFormat, stack []StackFrame, singleLine bool) string {
+	if singleLine && format.Style != StackCompact && format.Style != StackJSON {
+		format.Style = StackCompact
+	}
+	if format.Style == StackCompact || format.Style == StackJSON {
+		return " stack=" + format.Render(stack)
+	}
+	return format.Render(stack)
+}
+
+// trimPackage removes the package path from a function name, leaving its last
+// element
+func trimPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	return function[slash+1:]
+}
+
+// StackHook returns a Hook that captures the stack of entries at or above level,
+// so handlers can render it
+func StackHook(level LogLevel) Hook {
+	return func(msg *LogMessage) bool {
+		if msg.Level >= level && nil == msg.Stack {
+			msg.Stack = CaptureStack(0)
+		}
+		return true
+	}
+}
@@ -0,0 +1,65 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestStackFormat(test *testing.T) {
+	stack := []logs.StackFrame{
+		{Function: "example.com/app/api.(*Server).handle", File: "/src/app/api/server.go", Line: 42},
+		{Function: "net/http.HandlerFunc.ServeHTTP", File: "/go/src/net/http/server.go", Line: 2109},
+		{Function: "net/http.serverHandler.ServeHTTP", File: "/go/src/net/http/server.go", Line: 2947},
+	}
+
+	for _, c := range []struct {
+		format   logs.StackFormat
+		expected string
+	}{
+		{
+			logs.StackFormat{Style: logs.StackCompact, MaxFrames: 2, TrimPackages: true},
+			"api.(*Server).handle (server.go:42) < http.HandlerFunc.ServeHTTP (server.go:2109)",
+		},
+		{
+			logs.StackFormat{MaxFrames: 1},
+			"\n    at example.com/app/api.(*Server).handle (/src/app/api/server.go:42)",
+		},
+		{
+			logs.StackFormat{Style: logs.StackJSON, MaxFrames: 1, TrimPackages: true},
+			`[{"function":"api.(*Server).handle","file":"server.go","line":42}]`,
+		},
+	} {
+		if actual := c.format.Render(stack); actual != c.expected {
+			test.Errorf("Expected %q for %+v. Found: %q", c.expected, c.format, actual)
+		}
+	}
+
+	if err := (logs.StackFormat{Style: "fancy"}).Validate(); err == nil {
+		test.Error("Expected an error for an unknown style")
+	}
+}
+
+func TestCaptureStacks(test *testing.T) {
+	var buf bytes.Buffer
+	handler := logs.LeveledLogHandler{
+		Format:     "%s [%s]: %s",
+		RootFormat: "%s: %s",
+		Logger:     log.New(&buf, "", 0),
+		Stack:      &logs.StackFormat{Style: logs.StackCompact, MaxFrames: 1, TrimPackages: true},
+	}
+	logger := logs.New(&logs.RootLogConfig{
+		CaptureStacks: logs.Error,
+		LogHandler:    handler.LogHandler,
+	})
+	logger.Warn("No stack")
+	logger.Error("Failed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[0] != "WARN: No stack" || !strings.HasPrefix(lines[1], "ERROR: Failed stack=go-logs-go_test.TestCaptureStacks (stack_test.go:") {
+		test.Errorf("Unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,169 @@
+package gologsgo
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var statslock sync.Mutex
+var handlerStats = make(map[string]*HandlerStats)
+
+// HandlerStats accumulates the time spent in a LogHandler - encoding and
+// delivering (or, for asynchronous handlers, queueing) each record - so the
+// overhead each configured destination adds to logging calls can be quantified.
+type HandlerStats struct {
+	// Counters are first to guarantee 64-bit alignment for atomic operations
+	count uint64
+	total uint64
+	max   uint64
+	name  string
+}
+
+// HandlerStatsSnapshot is a point in time copy of a HandlerStats
+type HandlerStatsSnapshot struct {
+	Name  string
+	Count uint64
+	Total time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+}
+
+// MeasureHandler wraps a LogHandler so the time spent in it is recorded in the
+// HandlerStats registered under name. Handlers measured under the same name
+// share their stats.
+func MeasureHandler(name string, handler LogHandler) LogHandler {
+	stats := NamedHandlerStats(name)
+	return func(msg LogMessage) {
+		start := time.Now()
+		handler(msg)
+		stats.observe(time.Since(start))
+	}
+}
+
+// NamedHandlerStats returns the HandlerStats registered under name, creating it if needed
+func NamedHandlerStats(name string) *HandlerStats {
+	statslock.Lock()
+	defer statslock.Unlock()
+	stats, ok := handlerStats[name]
+	if !ok {
+		stats = &HandlerStats{name: name}
+		handlerStats[name] = stats
+	}
+	return stats
+}
+
+// AllHandlerStats returns a snapshot of every registered HandlerStats, sorted
+// by name
+func AllHandlerStats() []HandlerStatsSnapshot {
+	statslock.Lock()
+	all := make([]*HandlerStats, 0, len(handlerStats))
+	for _, stats := range handlerStats {
+		all = append(all, stats)
+	}
+	statslock.Unlock()
+
+	snapshots := make([]HandlerStatsSnapshot, len(all))
+	for i, stats := range all {
+		snapshots[i] = stats.Snapshot()
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
+
+func (s *HandlerStats) observe(d time.Duration) {
+	atomic.AddUint64(&s.count, 1)
+	atomic.AddUint64(&s.total, uint64(d))
+	for {
+		max := atomic.LoadUint64(&s.max)
+		if uint64(d) <= max || atomic.CompareAndSwapUint64(&s.max, max, uint64(d)) {
+			return
+		}
+	}
+}
+
+// Snapshot returns a point in time copy of the stats
+func (s *HandlerStats) Snapshot() HandlerStatsSnapshot {
+	snapshot := HandlerStatsSnapshot{
+		Name:  s.name,
+		Count: atomic.LoadUint64(&s.count),
+		Total: time.Duration(atomic.LoadUint64(&s.total)),
+		Max:   time.Duration(atomic.LoadUint64(&s.max)),
+	}
+	if snapshot.Count > 0 {
+		snapshot.Mean = snapshot.Total / time.Duration(snapshot.Count)
+	}
+	return snapshot
+}
+
+// Reset zeroes the stats
+func (s *HandlerStats) Reset() {
+	atomic.StoreUint64(&s.count, 0)
+	atomic.StoreUint64(&s.total, 0)
+	atomic.StoreUint64(&s.max, 0)
+}
+
+// Counters behind Stats(). They are package level so that handlers, which don't
+// know which Logger they serve, can count into them.
+var (
+	emittedCount       uint64
+	filteredCount      uint64
+	hookDroppedCount   uint64
+	droppedCount       uint64
+	handlerErrorsCount uint64
+)
+
+// RuntimeStats are counters for every Logger in the process, so operators can
+// verify that no logs are being silently lost
+type RuntimeStats struct {
+	// Emitted is the number of entries passed to a handler
+	Emitted uint64
+	// Filtered is the number of entries below their logger's level
+	Filtered uint64
+	// DroppedByHooks is the number of entries dropped by a Hook
+	DroppedByHooks uint64
+	// DroppedByHandlers is the number of entries accepted by a handler but then
+	// discarded - because a queue overflowed, they could not be delivered, or
+	// they were logged after the handler was closed
+	DroppedByHandlers uint64
+	// HandlerErrors is the number of errors returned by LogHandlerE handlers
+	HandlerErrors uint64
+}
+
+// Stats returns a snapshot of the RuntimeStats
+func Stats() RuntimeStats {
+	return RuntimeStats{
+		Emitted:           atomic.LoadUint64(&emittedCount),
+		Filtered:          atomic.LoadUint64(&filteredCount),
+		DroppedByHooks:    atomic.LoadUint64(&hookDroppedCount),
+		DroppedByHandlers: atomic.LoadUint64(&droppedCount),
+		HandlerErrors:     atomic.LoadUint64(&handlerErrorsCount),
+	}
+}
+
+var publishOnce sync.Once
+
+// PublishExpvar publishes Stats() and AllHandlerStats() with the expvar package
+// as "gologsgo", so they are served on /debug/vars. It is safe to call more than
+// once.
+func PublishExpvar() {
+	publishOnce.Do(func() {
+		expvar.Publish("gologsgo", expvar.Func(func() interface{} {
+			return map[string]interface{}{
+				"stats":    Stats(),
+				"handlers": AllHandlerStats(),
+			}
+		}))
+	})
+}
+
+// drop counts n entries dropped by a handler, in both the handler's own counter
+// and Stats()
+func drop(counter *uint64, n uint64) {
+	atomic.AddUint64(counter, n)
+	atomic.AddUint64(&droppedCount, n)
+}
@@ -0,0 +1,82 @@
+package gologsgo_test
+
+import (
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestMeasureHandler(test *testing.T) {
+	slow := logs.MeasureHandler("test-slow", func(logs.LogMessage) {
+		time.Sleep(10 * time.Millisecond)
+	})
+	fast := logs.MeasureHandler("test-fast", func(logs.LogMessage) {})
+
+	logger := logs.New(&logs.RootLogConfig{
+		LogHandler: func(msg logs.LogMessage) {
+			slow(msg)
+			fast(msg)
+		},
+	})
+	logger.Info("First")
+	logger.Info("Second")
+
+	stats := logs.NamedHandlerStats("test-slow").Snapshot()
+	if stats.Count != 2 || stats.Mean < 10*time.Millisecond || stats.Max < stats.Mean {
+		test.Errorf("Unexpected stats for the slow handler: %+v", stats)
+	}
+
+	found := false
+	for _, s := range logs.AllHandlerStats() {
+		if s.Name == "test-fast" {
+			found = s.Count == 2
+		}
+	}
+	if !found {
+		test.Error("Expected stats for the fast handler in AllHandlerStats()")
+	}
+}
+
+func TestRuntimeStats(test *testing.T) {
+	before := logs.Stats()
+
+	root := logs.New(&logs.RootLogConfig{
+		Level: logs.Info,
+		Hooks: []logs.Hook{func(msg *logs.LogMessage) bool {
+			return msg.Message != "hooked"
+		}},
+		LogHandlerE: func(msg logs.LogMessage) error {
+			if msg.Level == logs.Error {
+				return errors.New("failed")
+			}
+			return nil
+		},
+		ErrorCallback: func(error, logs.LogMessage) {},
+	})
+	root.Debug("filtered")
+	root.Info("hooked")
+	root.Info("emitted")
+	root.Error("failed")
+
+	async := logs.Async(func(logs.LogMessage) {}, 1, 1)
+	async.Close()
+	async.LogHandler(logs.LogMessage{})
+
+	after := logs.Stats()
+	if after.Filtered-before.Filtered != 1 ||
+		after.DroppedByHooks-before.DroppedByHooks != 1 ||
+		after.Emitted-before.Emitted != 2 ||
+		after.HandlerErrors-before.HandlerErrors != 1 ||
+		after.DroppedByHandlers-before.DroppedByHandlers < 1 {
+		test.Errorf("Unexpected stats. Before: %+v, after: %+v", before, after)
+	}
+
+	logs.PublishExpvar()
+	logs.PublishExpvar()
+	if expvar.Get("gologsgo") == nil {
+		test.Errorf("Expected stats to be published with expvar")
+	}
+}
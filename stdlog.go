@@ -0,0 +1,92 @@
+package gologsgo
+
+import (
+	"io"
+	"log"
+	"sync"
+)
+
+// StdLogger returns a *log.Logger that logs each line printed with it as an entry
+// at level, for libraries that take a *log.Logger, such as http.Server.ErrorLog:
+//
+//	server := &http.Server{ErrorLog: logger.StdLogger(logs.Error)}
+//
+// The returned logger adds no prefix or timestamp, as entries have their own.
+func (logger *Logger) StdLogger(level LogLevel) *log.Logger {
+	return log.New(logger.Writer(level), "", 0)
+}
+
+var stdliblock sync.RWMutex
+
+// hijacked is the current redirection of the standard library's logger by
+// HijackStdlib(). stdliblock guards it.
+var hijacked *stdlibHijack
+
+// HijackStdlib redirects the standard library's logger to the "stdlib" child of
+// the Logger, so third-party libraries that call log.Printf() respect its levels,
+// handlers and format. Each line they print is logged at the Info level, without
+// the prefix and timestamp of the standard library's logger. The default handler
+// of this package, which writes through the standard library's logger, keeps
+// writing where it did. Closing the returned io.Closer, or the Logger, restores
+// the standard library's logger.
+func (logger *Logger) HijackStdlib() io.Closer {
+	stdliblock.Lock()
+	defer stdliblock.Unlock()
+
+	h := &stdlibHijack{writer: logger.ChildLogger("stdlib").Writer(Info)}
+	if nil != hijacked {
+		// The original output is kept when hijacking again
+		h.original = hijacked.original
+		h.output, h.flags, h.prefix = hijacked.output, hijacked.flags, hijacked.prefix
+	} else {
+		h.output, h.flags, h.prefix = log.Writer(), log.Flags(), log.Prefix()
+		h.original = log.New(h.output, h.prefix, h.flags)
+	}
+	hijacked = h
+	log.SetOutput(h.writer)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	logger.RegisterCloser(h)
+	return h
+}
+
+// stdlibHijack restores the standard library's logger when it is closed
+type stdlibHijack struct {
+	writer io.WriteCloser
+	// output, flags and prefix are the settings of the standard library's
+	// logger before it was hijacked
+	output io.Writer
+	flags  int
+	prefix string
+	// original writes where the standard library's logger did
+	original *log.Logger
+	once     sync.Once
+}
+
+// Close restores the standard library's logger, unless it has been hijacked
+// again since
+func (h *stdlibHijack) Close() error {
+	h.once.Do(func() {
+		stdliblock.Lock()
+		if hijacked == h {
+			log.SetOutput(h.output)
+			log.SetFlags(h.flags)
+			log.SetPrefix(h.prefix)
+			hijacked = nil
+		}
+		stdliblock.Unlock()
+		h.writer.Close()
+	})
+	return nil
+}
+
+// stdlibLogger returns a logger that writes where the standard library's logger
+// wrote before HijackStdlib(), or nil if it isn't hijacked
+func stdlibLogger() *log.Logger {
+	stdliblock.RLock()
+	defer stdliblock.RUnlock()
+	if nil == hijacked {
+		return nil
+	}
+	return hijacked.original
+}
@@ -0,0 +1,50 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestStdLogger(test *testing.T) {
+	var entries []logs.LogMessage
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "app",
+		Level:      logs.Info,
+		LogHandler: func(msg logs.LogMessage) { entries = append(entries, msg) },
+	})
+	logger.StdLogger(logs.Error).Printf("http: TLS handshake error from %s", "10.0.0.1")
+	logger.StdLogger(logs.Debug).Print("Filtered")
+
+	if len(entries) != 1 || entries[0].Level != logs.Error || entries[0].Message != "http: TLS handshake error from 10.0.0.1" {
+		test.Errorf("Expected an ERROR entry without a prefix or timestamp. Found: %+v", entries)
+	}
+}
+
+func TestHijackStdlib(test *testing.T) {
+	var buffer bytes.Buffer
+	output, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(&buffer)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}()
+
+	// The default handler writes through the standard library's logger
+	logger := logs.New(&logs.RootLogConfig{Label: "app", Level: logs.Info, Colors: &logs.ColorConfig{Mode: logs.ColorNever}})
+	hijack := logger.HijackStdlib()
+	log.Printf("From a library")
+	if err := hijack.Close(); err != nil {
+		test.Fatal(err)
+	}
+	log.Printf("After closing")
+
+	expected := "INFO [app.stdlib]: From a library\nAfter closing\n"
+	if buffer.String() != expected {
+		test.Errorf("Unexpected output:\n%s\nShould be:\n%s", buffer.String(), expected)
+	}
+}
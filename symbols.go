@@ -0,0 +1,62 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultSymbols are the symbols a LeveledLogHandler prefixes entries with when
+// SymbolConfig doesn't override them
+var DefaultSymbols = map[LogLevel]string{
+	Trace: "·",
+	Debug: "○",
+	Info:  "●",
+	Warn:  "⚠",
+	Error: "✖",
+}
+
+// SymbolConfig turns on symbol prefixes (such as ✖ for errors) for friendly CLI
+// output
+type SymbolConfig struct {
+	// Mode is "auto", "always" or "never", like ColorConfig.Mode. In the "auto"
+	// mode, symbols are only written to terminals. Defaults to "auto".
+	Mode ColorMode `json:"mode,omitempty"`
+	// Levels overrides DefaultSymbols for individual levels, keyed by level
+	// label. An empty symbol leaves the level's entries without one.
+	Levels map[string]string `json:"levels,omitempty"`
+}
+
+// NewSymbols builds the symbols described by a SymbolConfig
+func NewSymbols(config SymbolConfig) (map[LogLevel]string, error) {
+	switch config.Mode {
+	case "", ColorAuto, ColorAlways, ColorNever:
+	default:
+		return nil, fmt.Errorf("Unknown symbol mode %q. Use \"auto\", \"always\" or \"never\"", config.Mode)
+	}
+
+	symbols := make(map[LogLevel]string, len(DefaultSymbols))
+	for lvl, symbol := range DefaultSymbols {
+		symbols[lvl] = symbol
+	}
+	for label, symbol := range config.Levels {
+		lvl, ok := LogLevels.Level(strings.ToUpper(label))
+		if !ok {
+			return nil, fmt.Errorf("Unknown level %q in symbols", label)
+		}
+		symbols[lvl] = symbol
+	}
+	return symbols, nil
+}
+
+// symbolsShown reports whether symbols should be written to w in a mode
+func (mode ColorMode) symbolsShown(w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return isTerminal(w)
+	}
+}
@@ -0,0 +1,42 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestSymbols(test *testing.T) {
+	symbols, err := logs.NewSymbols(logs.SymbolConfig{Levels: map[string]string{"info": "", "warn": "!"}})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	handler := logs.LeveledLogHandler{
+		Format:     "%s [%s]: %s",
+		RootFormat: "%s: %s",
+		Logger:     log.New(&buf, "", 0),
+		Symbols:    symbols,
+	}
+	// Symbols aren't written to a buffer in the auto mode
+	handler.LogHandler(logs.LogMessage{Level: logs.Error, LevelLabel: "ERROR", Message: "auto"})
+	handler.SymbolMode = logs.ColorAlways
+	handler.LogHandler(logs.LogMessage{Level: logs.Error, LevelLabel: "ERROR", Message: "failed"})
+	handler.LogHandler(logs.LogMessage{Level: logs.Warn, LevelLabel: "WARN", Logger: "db", Message: "slow"})
+	handler.LogHandler(logs.LogMessage{Level: logs.Info, LevelLabel: "INFO", Message: "plain"})
+
+	expected := "ERROR: auto\n✖ ERROR: failed\n! WARN [db]: slow\nINFO: plain\n"
+	if buf.String() != expected {
+		test.Errorf("Expected %q. Found: %q", expected, buf.String())
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"symbols": {"mode": "loud"}}`)); err == nil {
+		test.Error("Expected an error for an unknown mode")
+	}
+	if _, err := logs.JsonConfig([]byte(`{"handlers": [{"type": "stdout", "format": "json", "symbols": {}}]}`)); err == nil {
+		test.Error("Expected an error for symbols with the json format")
+	}
+}
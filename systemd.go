@@ -0,0 +1,54 @@
+package gologsgo
+
+import (
+	"net"
+	"os"
+	"strings"
+)
+
+// SdNotify sends a state notification (such as "READY=1") to systemd's notify
+// socket, for services run with Type=notify. It reports whether a notification
+// was sent; when not running under systemd ($NOTIFY_SOCKET is unset) it does
+// nothing and returns false.
+func SdNotify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if len(socket) == 0 {
+		return false, nil
+	}
+	// A leading @ denotes a socket in the abstract namespace
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NotifyReady logs that the service is ready and tells systemd (READY=1), so
+// the readiness record and the unit becoming active line up in the journal.
+func (logger *Logger) NotifyReady() error {
+	logger.Info("Service ready")
+	_, err := SdNotify("READY=1\nSTATUS=Ready")
+	return err
+}
+
+// NotifyStopping tells systemd the service is stopping (STOPPING=1), logs a
+// shutdown record and closes the Logger, flushing its registered closers so
+// nothing queued is lost when systemd stops the process. Records logged after
+// this are written to LastResortWriter, which systemd also captures.
+func (logger *Logger) NotifyStopping() error {
+	_, notifyErr := SdNotify("STOPPING=1\nSTATUS=Stopping")
+	logger.Info("Service stopping")
+	if err := logger.Close(); err != nil {
+		return err
+	}
+	return notifyErr
+}
@@ -0,0 +1,55 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestSystemdNotify(test *testing.T) {
+	dir, err := ioutil.TempDir("", "gologsgo")
+	if nil != err {
+		test.Fatalf("Unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if nil != err {
+		test.Fatalf("Unable to listen on %s: %s", path, err)
+	}
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", path)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	var messages []string
+	logger := logs.New(&logs.RootLogConfig{
+		LogHandler: func(msg logs.LogMessage) {
+			messages = append(messages, msg.Message)
+		},
+	})
+
+	if err := logger.NotifyReady(); nil != err {
+		test.Fatalf("Error notifying systemd: %s", err)
+	}
+	if err := logger.NotifyStopping(); nil != err {
+		test.Fatalf("Error notifying systemd: %s", err)
+	}
+
+	buf := make([]byte, 256)
+	for _, expected := range []string{"READY=1\nSTATUS=Ready", "STOPPING=1\nSTATUS=Stopping"} {
+		n, err := conn.Read(buf)
+		if nil != err || string(buf[:n]) != expected {
+			test.Errorf("Expected notification %q. Found: %q (%v)", expected, buf[:n], err)
+		}
+	}
+
+	if len(messages) != 2 || !logger.Closed() {
+		test.Errorf("Expected ready and stopping records and a closed logger. Found: %v", messages)
+	}
+}
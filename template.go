@@ -0,0 +1,72 @@
+package gologsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateEntry is the data a TemplateEncoder template is executed with
+type templateEntry struct {
+	LogMessage
+	// Label is the entry's level label, as other handlers print it
+	Label string
+	// Caller is the most recent frame of the entry's stack, when one was captured
+	Caller *StackFrame
+}
+
+// templateFuncs are the functions available to TemplateEncoder templates
+var templateFuncs = template.FuncMap{
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"time":   FormatTime,
+	"fields": formatFields,
+	"pad": func(width int, v interface{}) string {
+		return fmt.Sprintf("%-*v", width, v)
+	},
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func init() {
+	// A template may write newlines of its own
+	RegisterFormat("template", FormatTraits{MultiLine: true})
+}
+
+// TemplateEncoder returns an Encoder that lays out each entry with a Go
+// text/template, so arbitrary formats can be expressed in config. The template is
+// executed with the entry's LogMessage fields, plus .Label (the level label) and
+// .Caller (the captured stack's most recent frame, or nil). These functions are
+// available:
+//
+//   - time: formats a time with a layout or special format, {{time .Time "rfc3339"}}
+//   - fields: renders fields as key=value pairs, {{fields .Fields}}
+//   - json: encodes a value as JSON, {{json .Message}}
+//   - pad: pads a value to a width, {{pad 5 .Label}}
+//   - upper and lower: change the case of a string
+//
+// For example:
+//
+//	{{time .Time "15:04:05"}} {{pad 5 .Label}} {{.Logger}}: {{.Message}}{{with .Caller}} ({{.File}}:{{.Line}}){{end}}
+func TemplateEncoder(text string) (Encoder, error) {
+	tmpl, err := template.New("entry").Option("missingkey=zero").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid template: %s", err)
+	}
+
+	return func(msg LogMessage) ([]byte, error) {
+		entry := templateEntry{LogMessage: msg, Label: levelLabel(msg)}
+		if len(msg.Stack) > 0 {
+			entry.Caller = &msg.Stack[0]
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, entry); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}, nil
+}
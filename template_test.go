@@ -0,0 +1,47 @@
+package gologsgo_test
+
+import (
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestTemplateEncoder(test *testing.T) {
+	encode, err := logs.TemplateEncoder(`{{time .Time "15:04:05"}} {{pad 5 .Label}} {{.Logger}}: {{.Message}} {{fields .Fields}}{{with .Caller}} ({{.File}}:{{.Line}}){{end}}`)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	msg := logs.LogMessage{
+		Level:      logs.Info,
+		LevelLabel: "info",
+		Logger:     "api",
+		Message:    "Started",
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Fields:     map[string]interface{}{"port": 8080},
+	}
+	data, err := encode(msg)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if string(data) != "03:04:05 INFO  api: Started port=8080" {
+		test.Errorf("Unexpected output: %q", data)
+	}
+
+	msg.Stack = []logs.StackFrame{{Function: "main.main", File: "main.go", Line: 12}}
+	data, _ = encode(msg)
+	if string(data) != "03:04:05 INFO  api: Started port=8080 (main.go:12)" {
+		test.Errorf("Unexpected output with a caller: %q", data)
+	}
+
+	if _, err := logs.TemplateEncoder(`{{.Message`); err == nil {
+		test.Error("Expected an error for an invalid template")
+	}
+	if _, err := logs.JsonConfig([]byte(`{"handlers": [{"type": "stdout", "format": "template", "template": "{{json .Message}}"}]}`)); err != nil {
+		test.Error(err)
+	}
+	if _, err := logs.JsonConfig([]byte(`{"handlers": [{"type": "stdout", "format": "template"}]}`)); err == nil {
+		test.Error("Expected an error for a missing template")
+	}
+}
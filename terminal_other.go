@@ -0,0 +1,12 @@
+//go:build !windows
+// +build !windows
+
+package gologsgo
+
+import "os"
+
+// enableANSI reports whether a terminal can render ANSI escape sequences, which
+// all terminals outside of Windows can
+func enableANSI(f *os.File) bool {
+	return true
+}
@@ -0,0 +1,296 @@
+package gologsgo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Theme assigns a Formatter to each log level. It can be used as the Levels of a
+// LeveledLogHandler.
+type Theme map[LogLevel]Formatter
+
+// ColorMode controls whether a LeveledLogHandler writes ANSI colors
+type ColorMode string
+
+const (
+	// ColorAuto colors output written to a terminal, unless the NO_COLOR
+	// environment variable is set or TERM is "dumb"
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors output, even when it is piped or redirected
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors output
+	ColorNever ColorMode = "never"
+)
+
+// ColorConfig configures the colors of the default handler or of a "color"
+// output
+type ColorConfig struct {
+	// Mode is "auto", "always" or "never". Defaults to "auto".
+	Mode ColorMode `json:"mode,omitempty"`
+	// Theme is the name of a theme registered with RegisterTheme(). The built-in
+	// themes are "default", "solarized", "monochrome" and "vivid". Defaults to
+	// "default".
+	Theme string `json:"theme,omitempty"`
+	// Levels overrides the theme's color for individual levels, keyed by level
+	// label. See ColorFormatter() for the values.
+	Levels map[string]string `json:"levels,omitempty"`
+}
+
+var themelock sync.RWMutex
+
+var themes = map[string]Theme{
+	"default": mustTheme(map[LogLevel]string{
+		Trace: "grey bold",
+		Debug: "grey bold",
+		Info:  "white",
+		Warn:  "yellow",
+		Error: "red",
+	}),
+	// solarized avoids grey, which is unreadable on light backgrounds
+	"solarized": mustTheme(map[LogLevel]string{
+		Trace: "cyan",
+		Debug: "blue",
+		Info:  "green",
+		Warn:  "yellow",
+		Error: "red bold",
+	}),
+	"monochrome": mustTheme(map[LogLevel]string{
+		Trace: "faint",
+		Debug: "faint",
+		Info:  "none",
+		Warn:  "bold",
+		Error: "bold underline",
+	}),
+	// vivid uses the 256 color palette, and highlights errors with a background
+	"vivid": mustTheme(map[LogLevel]string{
+		Trace: "color(245)",
+		Debug: "color(110)",
+		Info:  "color(252)",
+		Warn:  "color(214) bold",
+		Error: "color(231) bg-color(160) bold",
+	}),
+}
+
+// sgrCodes maps color and attribute names to ANSI SGR parameters
+var sgrCodes = sgrColorCodes(map[string]string{
+	"bold":      "1",
+	"faint":     "2",
+	"italic":    "3",
+	"underline": "4",
+	"blink":     "5",
+	"reverse":   "7",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"grey":      "90",
+	"gray":      "90",
+})
+
+// sgrColorCodes adds the bright and background variants of the 8 basic colors
+func sgrColorCodes(codes map[string]string) map[string]string {
+	for i, name := range []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"} {
+		codes["hi-"+name] = fmt.Sprint(90 + i)
+		codes["bg-"+name] = fmt.Sprint(40 + i)
+		codes["bg-hi-"+name] = fmt.Sprint(100 + i)
+	}
+	return codes
+}
+
+// RegisterTheme makes a Theme available by name to ColorConfig. Registering an
+// existing name replaces it.
+func RegisterTheme(name string, theme Theme) {
+	themelock.Lock()
+	defer themelock.Unlock()
+	themes[name] = theme
+}
+
+// LookupTheme returns a copy of the theme registered with name
+func LookupTheme(name string) (Theme, bool) {
+	themelock.RLock()
+	defer themelock.RUnlock()
+	theme, ok := themes[name]
+	if !ok {
+		return nil, false
+	}
+	copied := make(Theme, len(theme))
+	for lvl, f := range theme {
+		copied[lvl] = f
+	}
+	return copied, true
+}
+
+// terminals caches whether each file written to is a terminal
+var terminals sync.Map
+
+// ansiFiles records the files that ColorAlways has enabled ANSI escapes for
+var ansiFiles sync.Map
+
+// ansiEscapes matches ANSI SGR escape sequences
+var ansiEscapes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// ColorsEnabled reports whether output written to w should be colored in the
+// ColorAuto mode: w must be a terminal, NO_COLOR must not be set (see
+// https://no-color.org) and TERM must not be "dumb". On Windows, it enables ANSI
+// escape sequences in the console, and reports false if they aren't supported.
+func ColorsEnabled(w io.Writer) bool {
+	if len(os.Getenv("NO_COLOR")) > 0 {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a terminal that can render ANSI escapes and
+// unicode symbols. TERM=dumb terminals can't.
+func isTerminal(w io.Writer) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	if terminal, ok := terminals.Load(f); ok {
+		return terminal.(bool)
+	}
+	terminal := (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())) && enableANSI(f)
+	terminals.Store(f, terminal)
+	return terminal
+}
+
+// colored reports whether output written to w should be colored in a mode
+func (mode ColorMode) colored(w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		if f, ok := w.(*os.File); ok {
+			if _, enabled := ansiFiles.LoadOrStore(f, true); !enabled {
+				enableANSI(f)
+			}
+		}
+		return true
+	case ColorNever:
+		return false
+	default:
+		return ColorsEnabled(w)
+	}
+}
+
+// stripColors removes ANSI SGR escape sequences from s
+func stripColors(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	return ansiEscapes.ReplaceAllString(s, "")
+}
+
+// ColorFormatter returns a Formatter that colors its output as described by spec:
+// space separated color and attribute names, such as "yellow", "hi-red bold" or
+// "white bg-red", or raw ANSI SGR parameters, such as "38;5;208". The names are
+// black, red, green, yellow, blue, magenta, cyan, white and grey, which may be
+// prefixed with "hi-" and/or "bg-", and bold, faint, italic, underline, blink and
+// reverse. Colors from the 256 color palette are written "color(208)" and
+// truecolors "#ff8700", either of which may also be prefixed with "bg-".
+// Truecolors are approximated with the 256 color palette unless the COLORTERM
+// environment variable is "truecolor" or "24bit". "none" (or an empty spec)
+// disables coloring. The Formatter always colors its output; a LeveledLogHandler
+// decides whether to use it from its ColorMode.
+func ColorFormatter(spec string) (Formatter, error) {
+	var codes []string
+	for _, token := range strings.Fields(strings.ToLower(spec)) {
+		if token == "none" {
+			continue
+		}
+		if code, ok := sgrCodes[token]; ok {
+			codes = append(codes, code)
+			continue
+		}
+		code, ok, err := paletteCode(token)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			codes = append(codes, code)
+			continue
+		}
+		if strings.Trim(token, "0123456789;") != "" || strings.Trim(token, ";") == "" {
+			return nil, fmt.Errorf("Unknown color %q. Use a color name, such as \"red\", \"hi-blue bold\", \"color(208)\" or \"bg-#ff8700\", or ANSI SGR parameters, such as \"38;5;208\"", token)
+		}
+		codes = append(codes, strings.Trim(token, ";"))
+	}
+	if len(codes) == 0 {
+		return fmt.Sprintf, nil
+	}
+
+	start := "\x1b[" + strings.Join(codes, ";") + "m"
+	return func(format string, args ...interface{}) string {
+		return start + fmt.Sprintf(format, args...) + "\x1b[0m"
+	}, nil
+}
+
+// NewTheme builds the Theme described by a ColorConfig. It also validates the
+// config's Mode.
+func NewTheme(config ColorConfig) (Theme, error) {
+	switch config.Mode {
+	case "", ColorAuto, ColorAlways, ColorNever:
+	default:
+		return nil, fmt.Errorf("Unknown color mode %q. Use \"auto\", \"always\" or \"never\"", config.Mode)
+	}
+
+	name := config.Theme
+	if len(name) == 0 {
+		name = "default"
+	}
+	theme, ok := LookupTheme(name)
+	if !ok {
+		themelock.RLock()
+		names := make([]string, 0, len(themes))
+		for n := range themes {
+			names = append(names, fmt.Sprintf("%q", n))
+		}
+		themelock.RUnlock()
+		sort.Strings(names)
+		return nil, fmt.Errorf("Unknown theme %q. Known themes are: %s", name, strings.Join(names, ", "))
+	}
+
+	for label, spec := range config.Levels {
+		lvl, ok := LogLevels.Level(strings.ToUpper(label))
+		if !ok {
+			return nil, fmt.Errorf("Unknown level %q in colors", label)
+		}
+		f, err := ColorFormatter(spec)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid color for %s: %s", strings.ToUpper(label), err)
+		}
+		theme[lvl] = f
+	}
+	return theme, nil
+}
+
+// mustTheme builds a built-in theme from color specs
+func mustTheme(specs map[LogLevel]string) Theme {
+	theme := make(Theme, len(specs))
+	for lvl, spec := range specs {
+		theme[lvl] = mustColor(spec)
+	}
+	return theme
+}
+
+// mustColor builds a built-in Formatter from a color spec
+func mustColor(spec string) Formatter {
+	f, err := ColorFormatter(spec)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
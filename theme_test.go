@@ -0,0 +1,140 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestColorFormatter(test *testing.T) {
+	for spec, expected := range map[string]string{
+		"red":          "\x1b[31mhi\x1b[0m",
+		"hi-red bold":  "\x1b[91;1mhi\x1b[0m",
+		"White BG-Red": "\x1b[37;41mhi\x1b[0m",
+		"38;5;208":     "\x1b[38;5;208mhi\x1b[0m",
+		"none":         "hi",
+		"":             "hi",
+	} {
+		f, err := logs.ColorFormatter(spec)
+		if err != nil {
+			test.Errorf("Unexpected error for %q: %s", spec, err)
+			continue
+		}
+		if actual := f("%s", "hi"); actual != expected {
+			test.Errorf("Expected %q for %q. Found: %q", expected, spec, actual)
+		}
+	}
+
+	for _, spec := range []string{"purple", "1;x", ";"} {
+		if _, err := logs.ColorFormatter(spec); err == nil {
+			test.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestColorsConfig(test *testing.T) {
+	var buf bytes.Buffer
+	output := log.Writer()
+	flags := log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer log.SetOutput(output)
+	defer log.SetFlags(flags)
+
+	config, err := logs.JsonConfig([]byte(`{
+		"level": "TRACE",
+		"colors": {"mode": "always", "theme": "solarized", "levels": {"warn": "hi-yellow"}}
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	logger := logs.New(config)
+	logger.Trace("t")
+	logger.Warn("w")
+
+	expected := "\x1b[36mTRACE: t\x1b[0m\n\x1b[93mWARN: w\x1b[0m\n"
+	if buf.String() != expected {
+		test.Errorf("Expected %q. Found: %q", expected, buf.String())
+	}
+
+	for _, data := range []string{
+		`{"colors": {"theme": "neon"}}`,
+		`{"colors": {"mode": "sometimes"}}`,
+		`{"colors": {"levels": {"LOUD": "red"}}}`,
+		`{"colors": {"levels": {"INFO": "purple"}}}`,
+		`{"handlers": [{"type": "stdout", "format": "json", "colors": {}}]}`,
+	} {
+		if _, err := logs.JsonConfig([]byte(data)); err == nil {
+			test.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestColorMode(test *testing.T) {
+	var buf bytes.Buffer
+	if logs.ColorsEnabled(&buf) {
+		test.Error("Expected no colors for a buffer")
+	}
+
+	yellow, _ := logs.ColorFormatter("yellow")
+	handler := logs.LeveledLogHandler{
+		Format:     "%s [%s]: %s",
+		RootFormat: "%s: %s",
+		Levels:     logs.Theme{logs.Warn: yellow},
+		Prefixes:   &logs.LoggerPrefixes{},
+		Logger:     log.New(&buf, "", 0),
+	}
+	handler.LogHandler(logs.LogMessage{Level: logs.Warn, LevelLabel: "warn", Logger: "api", Message: "auto"})
+	handler.ColorMode = logs.ColorAlways
+	handler.LogHandler(logs.LogMessage{Level: logs.Warn, LevelLabel: "warn", Logger: "api", Message: "always"})
+	handler.ColorMode = logs.ColorNever
+	handler.LogHandler(logs.LogMessage{Level: logs.Warn, LevelLabel: "warn", Logger: "api", Message: "never"})
+
+	expected := "api | WARN [api]: auto\n" +
+		"\x1b[36mapi | \x1b[0m\x1b[33mWARN [api]: always\x1b[0m\n" +
+		"api | WARN [api]: never\n"
+	if buf.String() != expected {
+		test.Errorf("Expected %q. Found: %q", expected, buf.String())
+	}
+}
+
+func TestPaletteColors(test *testing.T) {
+	os.Setenv("COLORTERM", "truecolor")
+	defer os.Unsetenv("COLORTERM")
+
+	for spec, expected := range map[string]string{
+		"color(208)":         "\x1b[38;5;208mhi\x1b[0m",
+		"bg-color(160) bold": "\x1b[48;5;160;1mhi\x1b[0m",
+		"#FF8700 bg-#000000": "\x1b[38;2;255;135;0;48;2;0;0;0mhi\x1b[0m",
+	} {
+		f, err := logs.ColorFormatter(spec)
+		if err != nil {
+			test.Errorf("Unexpected error for %q: %s", spec, err)
+			continue
+		}
+		if actual := f("%s", "hi"); actual != expected {
+			test.Errorf("Expected %q for %q. Found: %q", expected, spec, actual)
+		}
+	}
+
+	// Without truecolor support, the closest 256 color is used
+	os.Unsetenv("COLORTERM")
+	for spec, expected := range map[string]string{
+		"#ff8700": "\x1b[38;5;208mhi\x1b[0m",
+		"#808080": "\x1b[38;5;244mhi\x1b[0m",
+	} {
+		f, _ := logs.ColorFormatter(spec)
+		if actual := f("%s", "hi"); actual != expected {
+			test.Errorf("Expected %q for %q. Found: %q", expected, spec, actual)
+		}
+	}
+
+	for _, spec := range []string{"color(256)", "color(x)", "#ff87", "#gg0000"} {
+		if _, err := logs.ColorFormatter(spec); err == nil {
+			test.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package gologsgo
+
+import (
+	"strconv"
+	"time"
+)
+
+// Special time formats accepted wherever a time layout is configured, in addition
+// to any layout understood by time.Time.Format()
+const (
+	// TimeRFC3339Nano is time.RFC3339Nano
+	TimeRFC3339Nano = "rfc3339nano"
+	// TimeRFC3339 is time.RFC3339
+	TimeRFC3339 = "rfc3339"
+	// TimeEpoch is seconds since the Unix epoch
+	TimeEpoch = "epoch"
+	// TimeEpochMillis is milliseconds since the Unix epoch
+	TimeEpochMillis = "epoch-millis"
+	// TimeEpochNanos is nanoseconds since the Unix epoch
+	TimeEpochNanos = "epoch-nanos"
+)
+
+// FormatTime renders t with a time layout or one of the special time formats
+func FormatTime(t time.Time, format string) string {
+	switch format {
+	case TimeRFC3339Nano:
+		return t.Format(time.RFC3339Nano)
+	case TimeRFC3339:
+		return t.Format(time.RFC3339)
+	case TimeEpoch:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeEpochMillis:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	case TimeEpochNanos:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	return t.Format(format)
+}
+
+// InLocation wraps handler so that the Time of each entry is in loc, for handlers
+// and encoders that render it as is. Use it to force UTC timestamps regardless
+// of the host's timezone, or local time for developer consoles.
+func InLocation(handler LogHandler, loc *time.Location) LogHandler {
+	return func(msg LogMessage) {
+		msg.Time = msg.Time.In(loc)
+		handler(msg)
+	}
+}
+
+// UTC wraps handler so that the Time of each entry is in UTC
+func UTC(handler LogHandler) LogHandler {
+	return InLocation(handler, time.UTC)
+}
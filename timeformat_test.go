@@ -0,0 +1,72 @@
+package gologsgo_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestFormatTime(test *testing.T) {
+	t := time.Date(2020, 3, 4, 5, 6, 7, 8000000, time.UTC)
+	for format, expected := range map[string]string{
+		logs.TimeRFC3339Nano: "2020-03-04T05:06:07.008Z",
+		logs.TimeRFC3339:     "2020-03-04T05:06:07Z",
+		logs.TimeEpoch:       "1583298367",
+		logs.TimeEpochMillis: "1583298367008",
+		"15:04:05.000":       "05:06:07.008",
+	} {
+		if actual := logs.FormatTime(t, format); actual != expected {
+			test.Errorf("Expected %q for %q. Found: %q", expected, format, actual)
+		}
+	}
+}
+
+func TestLeveledLogHandlerTimeFormat(test *testing.T) {
+	var buf bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(output)
+
+	config, err := logs.JsonConfig([]byte(`{"label": "app", "timeFormat": "epoch-millis"}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	logs.New(config).Info("Stamped")
+
+	line := buf.String()
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields[0]) != 13 || strings.Contains(line, "/") || !strings.Contains(line, "Stamped") {
+		test.Errorf("Expected an epoch millis timestamp instead of the log flags. Found: %q", line)
+	}
+}
+
+func TestTimezones(test *testing.T) {
+	var received time.Time
+	handler := logs.UTC(func(msg logs.LogMessage) {
+		received = msg.Time
+	})
+	local := time.Date(2020, 1, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	handler(logs.LogMessage{Time: local})
+	if received.Location() != time.UTC || received.Hour() != 17 {
+		test.Errorf("Expected the time in UTC. Found: %s", received)
+	}
+
+	var buf bytes.Buffer
+	h := logs.LeveledLogHandler{
+		Format:   "%s [%s]: %s",
+		Logger:   log.New(&buf, "", log.LstdFlags),
+		Location: time.UTC,
+	}
+	h.LogHandler(logs.LogMessage{LevelLabel: "INFO", Logger: "app", Message: "hi", Time: local})
+	if buf.String() != "2020/01/01 17:00:00 INFO [app]: hi\n" {
+		test.Errorf("Expected a UTC timestamp in place of the log flags. Found: %q", buf.String())
+	}
+
+	if _, err := logs.JsonConfig([]byte(`{"timezone": "Mars/Olympus_Mons"}`)); err == nil {
+		test.Errorf("Expected an error for an unknown timezone")
+	}
+}
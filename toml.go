@@ -0,0 +1,26 @@
+package gologsgo
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TomlConfig creates a RootLogConfig from TOML data. The keys are those of the
+// JSON accepted by JsonConfig(), with child loggers as tables:
+//
+//	level = "INFO"
+//
+//	[loggers.db]
+//	level = "DEBUG"
+//
+// To embed logging settings in an application's own TOML config, decode its
+// section to a map[string]interface{} and pass it to MapConfig().
+func TomlConfig(data []byte) (*RootLogConfig, error) {
+	cfg := make(map[string]interface{})
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		return nil, fmt.Errorf("Invalid TOML config: %s", err)
+	}
+
+	return MapConfig(cfg)
+}
@@ -0,0 +1,40 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestTomlConfig(test *testing.T) {
+	config, err := logs.TomlConfig([]byte(`
+level = "warn"
+label = "app"
+timeFormat = "15:04"
+
+[loggers.db]
+level = "DEBUG"
+
+[loggers.db.loggers.cache]
+level = "TRACE"
+`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Warn || config.Label != "app" || config.TimeFormat != "15:04" {
+		test.Errorf("Unexpected config: %+v", config)
+	}
+	db := config.Loggers["db"]
+	if nil == db || db.Level != logs.Debug || nil == db.Loggers["cache"] || db.Loggers["cache"].Level != logs.Trace {
+		test.Errorf("Expected the db loggers to be configured. Found: %+v", db)
+	}
+}
+
+func TestTomlConfigErrors(test *testing.T) {
+	if _, err := logs.TomlConfig([]byte(`level = `)); err == nil {
+		test.Error("Expected an error for invalid TOML")
+	}
+	if _, err := logs.TomlConfig([]byte(`level = "LOUD"`)); err == nil {
+		test.Error("Expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,181 @@
+package gologsgo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDumpBytes is the default cap on the bytes of each body dumped by a
+// LoggingTransport
+const DefaultMaxDumpBytes = 4096
+
+// LoggingTransport is an http.RoundTripper that logs outbound HTTP calls. See
+// Transport().
+type LoggingTransport struct {
+	// Base makes the requests. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Logger logs the calls
+	Logger *Logger
+	// MaxDumpBytes caps the bytes of each body dumped at the TRACE level
+	MaxDumpBytes int
+	// RedactHeaders names the headers whose values are masked in dumps. Names are
+	// matched case insensitively.
+	RedactHeaders []string
+}
+
+// Transport returns a LoggingTransport that makes requests with base, and logs
+// each call with logger, for debugging integrations with third-party APIs:
+//
+//	client := &http.Client{Transport: logs.Transport(http.DefaultTransport, logger.ChildLogger("payments-api"))}
+//
+// Each call is logged with the "method", "url", "status" and "latency_ms" fields,
+// at the DEBUG level, WARN for 5xx statuses, or ERROR when no response is
+// received. Passwords in URLs are masked. When logger is at the TRACE level, the
+// headers and bodies of requests and responses are dumped too, each body capped
+// at MaxDumpBytes, with the Authorization, Proxy-Authorization, Cookie and
+// Set-Cookie headers masked. Dumping a response body waits for its first
+// MaxDumpBytes, so streamed responses should not be dumped.
+func Transport(base http.RoundTripper, logger *Logger) *LoggingTransport {
+	return &LoggingTransport{
+		Base:          base,
+		Logger:        logger,
+		MaxDumpBytes:  DefaultMaxDumpBytes,
+		RedactHeaders: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+	}
+}
+
+// RoundTrip makes a request with Base and logs it
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if nil == base {
+		base = http.DefaultTransport
+	}
+	url := req.URL.Redacted()
+	dump := t.Logger.Level() <= Trace
+
+	if dump {
+		body, err := t.dumpBody(&req.Body)
+		if err != nil {
+			if nil != req.Body {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+		// The body of a RoundTripper's request may not be modified, so a
+		// shallow copy carries the replaced body
+		copied := new(http.Request)
+		*copied = *req
+		copied.Body = body.reader
+		req = copied
+		t.Logger.Trace("%s %s request\n%s%s", req.Method, url, t.formatHeaders(req.Header), body)
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	latency := time.Since(start)
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"url":        url,
+		"latency_ms": float64(latency) / float64(time.Millisecond),
+	}
+	if err != nil {
+		t.Logger.WithFields(fields).Error("%s %s failed after %s: %s", req.Method, url, latency, err)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	call := t.Logger.WithFields(fields)
+	if resp.StatusCode >= 500 {
+		call.Warn("%s %s %d in %s", req.Method, url, resp.StatusCode, latency)
+	} else {
+		call.Debug("%s %s %d in %s", req.Method, url, resp.StatusCode, latency)
+	}
+
+	if dump {
+		body, err := t.dumpBody(&resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		resp.Body = body.reader
+		t.Logger.Trace("%s %s response %s\n%s%s", req.Method, url, resp.Status, t.formatHeaders(resp.Header), body)
+	}
+	return resp, nil
+}
+
+// dumpedBody is the start of a body read for a dump, and a reader of the whole
+// body
+type dumpedBody struct {
+	dumped    []byte
+	truncated bool
+	reader    io.ReadCloser
+}
+
+// String returns the dumped bytes, marking a truncated body
+func (b dumpedBody) String() string {
+	if len(b.dumped) == 0 {
+		return ""
+	}
+	if b.truncated {
+		return fmt.Sprintf("\n%s... (truncated)", b.dumped)
+	}
+	return "\n" + string(b.dumped)
+}
+
+// dumpBody reads up to MaxDumpBytes of a body, replacing it with a reader of the
+// whole body
+func (t *LoggingTransport) dumpBody(body *io.ReadCloser) (dumpedBody, error) {
+	if nil == *body || *body == http.NoBody {
+		return dumpedBody{reader: *body}, nil
+	}
+	max := t.MaxDumpBytes
+	if max < 0 {
+		max = 0
+	}
+	// One more byte tells whether the body is truncated
+	dumped, err := ioutil.ReadAll(io.LimitReader(*body, int64(max)+1))
+	if err != nil {
+		return dumpedBody{}, err
+	}
+	reader := readCloser{io.MultiReader(bytes.NewReader(dumped), *body), *body}
+	if len(dumped) > max {
+		return dumpedBody{dumped: dumped[:max], truncated: true, reader: reader}, nil
+	}
+	return dumpedBody{dumped: dumped, reader: reader}, nil
+}
+
+// formatHeaders renders headers one per line, sorted by name, with the values of
+// RedactHeaders masked
+func (t *LoggingTransport) formatHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		for _, value := range header[name] {
+			for _, redacted := range t.RedactHeaders {
+				if strings.EqualFold(name, redacted) {
+					value = "[REDACTED]"
+					break
+				}
+			}
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+	return b.String()
+}
+
+// readCloser reads from one reader and closes another
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
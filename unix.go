@@ -0,0 +1,38 @@
+package gologsgo
+
+import (
+	"encoding/binary"
+)
+
+// Framing delimits encoded entries written to a byte stream so the receiving
+// end can split them apart again
+type Framing func([]byte) []byte
+
+// NewlineFraming terminates each entry with a newline. It is understood by most
+// collectors, but requires an Encoder that never emits a raw newline (such as
+// JSONEncoder).
+func NewlineFraming(data []byte) []byte {
+	return append(data, '\n')
+}
+
+// LengthPrefixFraming precedes each entry with its length as a 4 byte, big endian
+// unsigned integer (Vector's "length_delimited" framing), so entries may safely
+// contain newlines.
+func LengthPrefixFraming(data []byte) []byte {
+	framed := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(framed, uint32(len(data)))
+	copy(framed[4:], data)
+	return framed
+}
+
+// NewUnixSocketHandler returns a NetworkHandler that writes newline delimited JSON
+// entries to the unix domain socket at path, so sidecar collectors such as Vector
+// or Fluent Bit can ingest logs without files or network ports. Use
+// NewNetworkHandler directly with Network set to "unix" or "unixgram" for other
+// encodings or framing.
+func NewUnixSocketHandler(path string) (*NetworkHandler, error) {
+	return NewNetworkHandler(NetworkHandlerConfig{
+		Network: "unix",
+		Address: path,
+	})
+}
@@ -0,0 +1,75 @@
+package gologsgo_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestUnixSocketHandlerLengthPrefixed(test *testing.T) {
+	dir, err := ioutil.TempDir("", "gologsgo")
+	if nil != err {
+		test.Fatalf("Unable to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs.sock")
+	listener, err := net.Listen("unix", path)
+	if nil != err {
+		test.Fatalf("Unable to listen on %s: %s", path, err)
+	}
+	defer listener.Close()
+
+	frames := make(chan []byte, 10)
+	go func() {
+		defer close(frames)
+		conn, err := listener.Accept()
+		if nil != err {
+			return
+		}
+		defer conn.Close()
+		for {
+			var size uint32
+			if err := binary.Read(conn, binary.BigEndian, &size); nil != err {
+				return
+			}
+			frame := make([]byte, size)
+			if _, err := io.ReadFull(conn, frame); nil != err {
+				return
+			}
+			frames <- frame
+		}
+	}()
+
+	handler, err := logs.NewNetworkHandler(logs.NetworkHandlerConfig{
+		Network: "unix",
+		Address: path,
+		Framing: logs.LengthPrefixFraming,
+	})
+	if nil != err {
+		test.Fatalf("Error creating unix socket handler: %s", err)
+	}
+
+	logger := logs.New(&logs.RootLogConfig{LogHandler: handler.LogHandler})
+	logger.Error("A multi-line\nerror log message")
+	handler.Close()
+
+	frame, ok := <-frames
+	if !ok {
+		test.Fatal("Expected a frame")
+	}
+	var entry map[string]string
+	if err := json.Unmarshal(frame, &entry); nil != err {
+		test.Fatalf("Unable to parse %q as JSON: %s", frame, err)
+	}
+	if entry["message"] != "A multi-line\nerror log message" {
+		test.Errorf("Unexpected entry: %s", frame)
+	}
+}
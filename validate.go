@@ -0,0 +1,309 @@
+package gologsgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigError is a problem with a setting of a config
+type ConfigError struct {
+	// Path is the dotted path of the setting, such as "loggers.db.level" or
+	// "handlers[0].type", or the name of the environment variable it was read
+	// from by EnvPrefixConfig()
+	Path    string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ConfigErrors are all the problems found with a config by StrictJsonConfig(),
+// RootLogConfig.Validate() or EnvPrefixConfig(), in the order of their paths
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d config errors: %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// add records a problem with the setting at path
+func (errs *ConfigErrors) add(path string, format string, args ...interface{}) {
+	*errs = append(*errs, &ConfigError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the errors sorted by path, or nil if there are none
+func (errs ConfigErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Path < errs[j].Path
+	})
+	return errs
+}
+
+// StrictJsonConfig creates a RootLogConfig from JSON data like JsonConfig(), but
+// rejects keys that aren't settings and levels that aren't known, which
+// JsonConfig() ignores or reports without saying where, and then checks the
+// config with Validate(). All the problems found are returned as ConfigErrors:
+//
+//	loggers.db.level: Unknown level "VERBOSE"; loggers.http.levle: Unknown key
+func StrictJsonConfig(data []byte) (*RootLogConfig, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var config *RootLogConfig
+	err := withConfigLabels(data, func() error {
+		var errs ConfigErrors
+		checkKeys("", reflect.TypeOf(RootLogConfig{}), doc, &errs)
+		if err := errs.err(); err != nil {
+			return err
+		}
+
+		var err error
+		if config, err = jsonConfig(data); err != nil {
+			return err
+		}
+		return config.Validate()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate checks a config for settings that would otherwise be ignored or
+// replaced by defaults: unknown levels, empty or dotted logger names, malformed
+// logger patterns, invalid timezones, presets, colors, symbols, label formats,
+// redaction settings, rules, sampling and rate limits, profiles, and settings that
+// conflict with each other. All the problems found are returned as ConfigErrors.
+func (config *RootLogConfig) Validate() error {
+	var errs ConfigErrors
+	validateLevel("level", config.Level, &errs)
+	if nil != config.Handler && len(config.Handlers) > 0 {
+		errs.add("handlers", "Only one of \"handler\" and \"handlers\" may be set")
+	}
+	if nil != config.LogHandler && nil != config.LogHandlerE {
+		errs.add("LogHandlerE", "Only one of LogHandler and LogHandlerE may be set")
+	}
+	if len(config.Timezone) > 0 {
+		if _, err := time.LoadLocation(config.Timezone); err != nil {
+			errs.add("timezone", "Unknown timezone %q", config.Timezone)
+		}
+	}
+	if err := validatePreset(config.Preset); err != nil {
+		errs.add("preset", "%s", err)
+	}
+	if nil != config.Colors {
+		if _, err := NewTheme(*config.Colors); err != nil {
+			errs.add("colors", "%s", err)
+		}
+	}
+	if nil != config.Symbols {
+		if _, err := NewSymbols(*config.Symbols); err != nil {
+			errs.add("symbols", "%s", err)
+		}
+	}
+	if nil != config.LabelFormat {
+		if err := config.LabelFormat.Validate(); err != nil {
+			errs.add("labelFormat", "%s", err)
+		}
+	}
+	if nil != config.Redaction {
+		if _, err := RedactionHook(*config.Redaction); err != nil {
+			errs.add("redaction", "%s", err)
+		}
+	}
+	validateLevel("captureStacks", config.CaptureStacks, &errs)
+	if nil != config.Sample {
+		if err := config.Sample.Validate(); err != nil {
+			errs.add("sample", "%s", err)
+		}
+	}
+	if config.RateLimitPerSec < 0 {
+		errs.add("rateLimitPerSec", "May not be negative")
+	}
+	for i, rule := range config.Rules {
+		if _, err := compileRule(rule); err != nil {
+			errs.add(fmt.Sprintf("rules[%d]", i), "%s", err)
+		}
+	}
+	validateLoggers("loggers", config.Loggers, &errs)
+	if _, err := config.WithProfile(""); err != nil {
+		errs.add("profile", "%s", err)
+	}
+	for name, profile := range config.Profiles {
+		if nil == profile {
+			continue
+		}
+		if len(profile.Profiles) > 0 {
+			errs.add("profiles."+name+".profiles", "Profiles may not be nested")
+		}
+		if err := profile.Validate(); err != nil {
+			for _, e := range err.(ConfigErrors) {
+				errs.add(joinPath("profiles."+name, e.Path), "%s", e.Message)
+			}
+		}
+	}
+	return errs.err()
+}
+
+// validateLoggers checks the child logger configs of a config
+func validateLoggers(prefix string, loggers map[string]*LogConfig, errs *ConfigErrors) {
+	for name, config := range loggers {
+		p := prefix + "." + name
+		switch {
+		case len(name) == 0:
+			errs.add(prefix, "Logger names may not be empty")
+			continue
+		case isPattern(name):
+			if _, err := path.Match(name, ""); err != nil {
+				errs.add(p, "Malformed pattern")
+			}
+		case strings.Contains(name, "."):
+			errs.add(p, "Logger names may not contain dots. Nest the logger in the loggers of its parent")
+		}
+		if nil == config {
+			continue
+		}
+		validateLevel(p+".level", config.Level, errs)
+		if nil != config.Sample {
+			if err := config.Sample.Validate(); err != nil {
+				errs.add(p+".sample", "%s", err)
+			}
+		}
+		if config.RateLimitPerSec < 0 {
+			errs.add(p+".rateLimitPerSec", "May not be negative")
+		}
+		validateLoggers(p+".loggers", config.Loggers, errs)
+	}
+}
+
+// validateLevel checks that a level set in code is one of the known levels
+func validateLevel(setting string, level LogLevel, errs *ConfigErrors) {
+	if level != NotSet && len(LogLevels.Label(level)) == 0 {
+		errs.add(setting, "Unknown level %d", level)
+	}
+}
+
+var (
+	logLevelType      = reflect.TypeOf(LogLevel(0))
+	handlerConfigType = reflect.TypeOf(HandlerConfig{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+)
+
+// checkKeys checks that the keys of the decoded JSON value v are fields of t, and
+// that the levels in it are known, recursively. Mismatched types are left to
+// json.Unmarshal() to report.
+func checkKeys(prefix string, t reflect.Type, v interface{}, errs *ConfigErrors) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == logLevelType:
+		switch value := v.(type) {
+		case nil:
+		case string:
+			if _, ok := parseLevel(value); !ok {
+				errs.add(prefix, "Unknown level %q", value)
+			}
+		default:
+			errs.add(prefix, "Levels must be labels, such as \"DEBUG\"")
+		}
+		return
+	case t == rawMessageType:
+		return
+	case t == handlerConfigType:
+		if _, ok := v.(string); ok {
+			return
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range obj {
+			field, ok := jsonField(t, key)
+			if !ok {
+				errs.add(joinPath(prefix, key), "Unknown key")
+				continue
+			}
+			checkKeys(joinPath(prefix, key), field.Type, value, errs)
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range obj {
+			checkKeys(joinPath(prefix, key), t.Elem(), value, errs)
+		}
+	case reflect.Slice:
+		list, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		for i, value := range list {
+			checkKeys(fmt.Sprintf("%s[%d]", prefix, i), t.Elem(), value, errs)
+		}
+	}
+}
+
+// jsonField finds the field of a struct that a JSON key decodes into. Like
+// json.Unmarshal(), it prefers an exact match of the key, but accepts any case.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var folded reflect.StructField
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); len(tag) > 0 {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; len(n) > 0 {
+				name = n
+			}
+		}
+		if name == key {
+			return field, true
+		}
+		if !found && strings.EqualFold(name, key) {
+			folded = field
+			found = true
+		}
+	}
+	return folded, found
+}
+
+// joinPath appends a key to a dotted path
+func joinPath(prefix string, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
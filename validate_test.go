@@ -0,0 +1,100 @@
+package gologsgo_test
+
+import (
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestStrictJsonConfig(test *testing.T) {
+	config, err := logs.StrictJsonConfig([]byte(`{
+		"level": "warn",
+		"handler": {"name": "json-stderr"},
+		"loggers": {"db": {"level": "DEBUG", "handler": "text-stderr", "loggers": {"cache": {"level": "ERROR"}}}},
+		"rules": [{"match": "sql$", "level": "DEBUG"}]
+	}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if config.Level != logs.Warn || config.Loggers["db"].Loggers["cache"].Level != logs.Error {
+		test.Errorf("Unexpected config: %+v", config)
+	}
+
+	_, err = logs.StrictJsonConfig([]byte(`{
+		"level": "LOUD",
+		"timeFormatt": "15:04",
+		"loggers": {
+			"db": {"levle": "DEBUG", "loggers": {"cache": {"level": 3}}},
+			"http": {"level": "VERBOSE"}
+		},
+		"handlers": [{"type": "console", "colour": true}],
+		"rules": [{"match": "sql$", "level": "DEBUG", "name": "sql"}]
+	}`))
+	errs, ok := err.(logs.ConfigErrors)
+	if !ok {
+		test.Fatalf("Expected ConfigErrors. Found: %v", err)
+	}
+	expected := []string{
+		`handlers[0].colour: Unknown key`,
+		`level: Unknown level "LOUD"`,
+		`loggers.db.levle: Unknown key`,
+		`loggers.db.loggers.cache.level: Levels must be labels, such as "DEBUG"`,
+		`loggers.http.level: Unknown level "VERBOSE"`,
+		`rules[0].name: Unknown key`,
+		`timeFormatt: Unknown key`,
+	}
+	if len(errs) != len(expected) {
+		test.Fatalf("Expected %d errors. Found: %v", len(expected), err)
+	}
+	for i, e := range errs {
+		if e.Error() != expected[i] {
+			test.Errorf("Expected %q. Found: %q", expected[i], e.Error())
+		}
+	}
+}
+
+func TestValidate(test *testing.T) {
+	config := &logs.RootLogConfig{
+		Level:       logs.LogLevel(42),
+		Timezone:    "Mars/Olympus_Mons",
+		Preset:      "fast",
+		LogHandler:  func(logs.LogMessage) {},
+		LogHandlerE: func(logs.LogMessage) error { return nil },
+		Rules:       []logs.LevelRule{{Match: "(", Level: logs.Debug}, {Match: "sql"}},
+		Loggers: map[string]*logs.LogConfig{
+			"":         {},
+			"db.cache": {Level: logs.Debug},
+			"http.[":   {Level: logs.Debug},
+			"api":      {Loggers: map[string]*logs.LogConfig{"v1": {Level: logs.LogLevel(-3)}}},
+		},
+	}
+	err := config.Validate()
+	errs, ok := err.(logs.ConfigErrors)
+	if !ok {
+		test.Fatalf("Expected ConfigErrors. Found: %v", err)
+	}
+	paths := []string{
+		"LogHandlerE",
+		"level",
+		"loggers",
+		"loggers.api.loggers.v1.level",
+		"loggers.db.cache",
+		"loggers.http.[",
+		"preset",
+		"rules[0]",
+		"rules[1]",
+		"timezone",
+	}
+	if len(errs) != len(paths) {
+		test.Fatalf("Expected %d errors. Found: %v", len(paths), err)
+	}
+	for i, e := range errs {
+		if e.Path != paths[i] {
+			test.Errorf("Expected an error for %s. Found: %v", paths[i], e)
+		}
+	}
+
+	if err := (&logs.RootLogConfig{Level: logs.Debug}).Validate(); err != nil {
+		test.Errorf("Expected a valid config. Found: %v", err)
+	}
+}
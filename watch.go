@@ -0,0 +1,144 @@
+package gologsgo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a config file must be left unchanged before it is
+// reloaded, so that a file written in several steps is read once
+const watchDebounce = 100 * time.Millisecond
+
+// WatchFileConfig reloads the levels of the Logger and its descendants from a
+// config file whenever it changes, so operators can adjust verbosity by editing
+// the file, or a mounted Kubernetes ConfigMap, without a restart. The file is
+// parsed in the format of its extension: ".toml", ".hcl", ".properties" or JSON.
+// Its levels are applied with ApplyLevels(); its other settings are ignored. A
+// file that fails to parse is reported to LastResortWriter and the current levels
+// are kept. Watching stops when the returned io.Closer, or the Logger, is
+// closed.
+func (logger *Logger) WatchFileConfig(path string) (io.Closer, error) {
+	return logger.watchFile(path, 0)
+}
+
+// watchFile starts a fileWatcher for path. The file is also read every resync,
+// when it's not 0.
+func (logger *Logger) watchFile(path string, resync time.Duration) (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// The directory is watched, as editors and ConfigMaps replace files rather
+	// than writing them in place
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("Unable to watch %s: %s", path, err)
+	}
+
+	fw := &fileWatcher{
+		logger:  logger,
+		path:    path,
+		watcher: watcher,
+		resync:  resync,
+		done:    make(chan struct{}),
+	}
+	fw.last, _ = ioutil.ReadFile(path)
+	go fw.watch()
+	logger.RegisterCloser(fw)
+	return fw, nil
+}
+
+// fileWatcher reloads a Logger's levels when a config file changes
+type fileWatcher struct {
+	logger  *Logger
+	path    string
+	watcher *fsnotify.Watcher
+	// resync, when not 0, is how often the file is read again, in case an
+	// update was missed
+	resync time.Duration
+	// last is the content of the file when it was last read
+	last []byte
+	done chan struct{}
+	once sync.Once
+}
+
+func (fw *fileWatcher) watch() {
+	name := filepath.Base(fw.path)
+	var resync <-chan time.Time
+	if fw.resync > 0 {
+		ticker := time.NewTicker(fw.resync)
+		defer ticker.Stop()
+		resync = ticker.C
+	}
+	var pending <-chan time.Time
+	for {
+		select {
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			// ConfigMaps are updated by replacing the ..data symlink. The file
+			// is read through it, so it is never read halfway through an update.
+			base := filepath.Base(event.Name)
+			if base == name || strings.HasPrefix(base, "..") {
+				pending = time.After(watchDebounce)
+			}
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+			lastResortWarning("Error watching %s: %s", fw.path, err)
+		case <-pending:
+			pending = nil
+			fw.reload()
+		case <-resync:
+			fw.reload()
+		case <-fw.done:
+			return
+		}
+	}
+}
+
+// reload applies the levels of the config file if it has changed
+func (fw *fileWatcher) reload() {
+	data, err := ioutil.ReadFile(fw.path)
+	if os.IsNotExist(err) {
+		// The file is being replaced, or was removed from a ConfigMap. It is
+		// read again when it is created.
+		return
+	}
+	if err != nil {
+		lastResortWarning("Unable to read %s: %s", fw.path, err)
+		return
+	}
+	if bytes.Equal(data, fw.last) {
+		return
+	}
+	fw.last = data
+
+	config, err := parseConfig(fw.path, data)
+	if err != nil {
+		lastResortWarning("Unable to reload %s: %s. Keeping the current levels", fw.path, err)
+		return
+	}
+	fw.logger.applyReloaded(config)
+}
+
+// Close stops watching the file
+func (fw *fileWatcher) Close() error {
+	var err error
+	fw.once.Do(func() {
+		close(fw.done)
+		err = fw.watcher.Close()
+	})
+	return err
+}
@@ -0,0 +1,55 @@
+package gologsgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestWatchFileConfig(test *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.json")
+	if err := ioutil.WriteFile(path, []byte(`{"level": "INFO"}`), 0644); err != nil {
+		test.Fatal(err)
+	}
+	config, err := logs.FileConfig(path)
+	if err != nil {
+		test.Fatal(err)
+	}
+	config.LogHandler = func(logs.LogMessage) {}
+	logger := logs.New(config)
+	db := logger.ChildLogger("db")
+
+	watcher, err := logger.WatchFileConfig(path)
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer watcher.Close()
+
+	// Replace the file, as editors do
+	tmp := filepath.Join(dir, "log.json.tmp")
+	ioutil.WriteFile(tmp, []byte(`{"level": "WARN", "loggers": {"db": {"level": "TRACE"}}}`), 0644)
+	if err := os.Rename(tmp, path); err != nil {
+		test.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for db.Level() != logs.Trace && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if logger.Level() != logs.Warn || db.Level() != logs.Trace {
+		test.Errorf("Expected the levels to be reloaded. Found: %s, %s", logs.LogLevels.Label(logger.Level()), logs.LogLevels.Label(db.Level()))
+	}
+
+	if err := watcher.Close(); err != nil {
+		test.Error(err)
+	}
+}
@@ -0,0 +1,91 @@
+package gologsgo
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// writerMaxLine is the longest line a Writer() buffers. Longer lines are logged
+// in pieces of this size.
+const writerMaxLine = 64 * 1024
+
+// Writer returns an io.WriteCloser that logs each line written to it as an entry
+// at level, for output that can only go to a Writer, such as that of a command:
+//
+//	cmd := exec.Command("migrate", "up")
+//	stderr := logger.ChildLogger("migrate").Writer(logs.Warn)
+//	defer stderr.Close()
+//	cmd.Stderr = stderr
+//
+// or, through StdLogger(), the errors of an http.Server.
+// Lines may end with "\n" or "\r\n", and blank lines are skipped. A line is
+// logged once it is complete, so Close() logs the last line if it doesn't end
+// with a newline. Writes after Close() fail with os.ErrClosed. It is safe for
+// concurrent use.
+func (logger *Logger) Writer(level LogLevel) io.WriteCloser {
+	return &levelWriter{logger: logger, level: level}
+}
+
+// levelWriter is the io.WriteCloser returned by Writer()
+type levelWriter struct {
+	logger *Logger
+	level  LogLevel
+	mu     sync.Mutex
+	// buffer holds the incomplete last line
+	buffer []byte
+	closed bool
+}
+
+// Write logs the complete lines of p, buffering the rest
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	w.buffer = append(w.buffer, p...)
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(w.buffer[:i])
+		w.buffer = w.buffer[i+1:]
+	}
+	for len(w.buffer) >= writerMaxLine {
+		w.logLine(w.buffer[:writerMaxLine])
+		w.buffer = w.buffer[writerMaxLine:]
+	}
+	if len(w.buffer) == 0 {
+		// Release the memory of long writes
+		w.buffer = nil
+	}
+	return len(p), nil
+}
+
+// Close logs the incomplete last line, if any
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.logLine(w.buffer)
+	w.buffer = nil
+	return nil
+}
+
+// logLine logs a line, unless it is blank
+func (w *levelWriter) logLine(line []byte) {
+	text := strings.TrimSuffix(string(line), "\r")
+	if len(strings.TrimSpace(text)) == 0 {
+		return
+	}
+	w.logger.log(w.level, "%s", text)
+}
@@ -0,0 +1,63 @@
+package gologsgo_test
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"testing"
+
+	logs "github.com/big-squid/go-logs-go"
+)
+
+func TestWriter(test *testing.T) {
+	var entries []logs.LogMessage
+	logger := logs.New(&logs.RootLogConfig{
+		Label:      "app",
+		Level:      logs.Info,
+		LogHandler: func(msg logs.LogMessage) { entries = append(entries, msg) },
+	})
+
+	w := logger.Writer(logs.Warn)
+	fmt.Fprint(w, "first line\r\nsecond ")
+	fmt.Fprint(w, "line\n\n")
+	fmt.Fprint(w, "unterminated")
+	if len(entries) != 2 {
+		test.Fatalf("Expected only complete lines to be logged. Found: %+v", entries)
+	}
+	if err := w.Close(); err != nil {
+		test.Fatal(err)
+	}
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		test.Error("Expected writes after Close to fail")
+	}
+
+	log.New(logger.Writer(logs.Error), "", 0).Printf("http: TLS handshake error")
+	logger.Writer(logs.Debug).Write([]byte("filtered\n"))
+
+	var messages []string
+	for _, entry := range entries {
+		messages = append(messages, logs.LogLevels.Label(entry.Level)+" "+entry.Message)
+	}
+	expected := "WARN first line|WARN second line|WARN unterminated|ERROR http: TLS handshake error"
+	if strings.Join(messages, "|") != expected {
+		test.Errorf("Unexpected entries:\n%s\nShould be:\n%s", strings.Join(messages, "|"), expected)
+	}
+}
+
+func TestWriterCommand(test *testing.T) {
+	var entries []logs.LogMessage
+	logger := logs.New(&logs.RootLogConfig{
+		LogHandler: func(msg logs.LogMessage) { entries = append(entries, msg) },
+	})
+	stdout := logger.Writer(logs.Info)
+	cmd := exec.Command("sh", "-c", "echo one; echo two")
+	cmd.Stdout = stdout
+	if err := cmd.Run(); err != nil {
+		test.Skip(err)
+	}
+	stdout.Close()
+	if len(entries) != 2 || entries[0].Message != "one" || entries[1].Message != "two" {
+		test.Errorf("Expected each line of output to be logged. Found: %+v", entries)
+	}
+}